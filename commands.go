@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deskdaniel/GoMate/internal/admin"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/archive"
+	"github.com/deskdaniel/GoMate/internal/board"
+	"github.com/deskdaniel/GoMate/internal/navigation"
+	"github.com/deskdaniel/GoMate/internal/player"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
+// errUsage reports wrong arguments to a command after its usage has been
+// printed.
+var errUsage = errors.New("invalid arguments")
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(opts globalOptions, flags *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"play", "", "start the game (the default)", runPlay},
+	{"stats", "[--json] <username>", "print the stats of a player", runStats},
+	{"export", "[<archive.json>]", "export players and games, with the games also in PGN", runExport},
+	{"import", "[--replace] [--collisions fail|rename|skip] <archive.json>", "import an exported archive", runImport},
+	{"fen", "[--game <id> [--ply <n>]] [<move>...]", "print the position after a game or a list of moves", runFEN},
+	{"headless", "[--json] [--fen <position>]", "play moves read from stdin and print every position, without the game screen", runHeadless},
+	{"admin", "<command>", "manage players, see gomate admin", runAdmin},
+	{"version", "", "print the version", runVersion},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// runCommand runs a command with the log going to the file given with
+// --log, if any.
+func runCommand(opts globalOptions, cmd command, args []string) error {
+	if opts.logFlag != "" {
+		logFile, err := openLogFile(opts.logFlag)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		log.SetOutput(logFile)
+	}
+
+	return cmd.run(opts, cmd.flagSet(), args)
+}
+
+func openLogFile(path string) (*os.File, error) {
+	logFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return logFile, nil
+}
+
+func (c command) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: gomate [global flags] %s %s\n\n", c.name, c.args)
+		fmt.Fprintf(flags.Output(), "%s%s.\n", strings.ToUpper(c.summary[:1]), c.summary[1:])
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(flags.Output(), "\nFlags:\n")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseArgs parses the flags of a command and checks the number of
+// arguments left, printing the usage when they are wrong.
+func parseArgs(flags *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	if err != nil {
+		return errUsage
+	}
+	if flags.NArg() < minArgs || (maxArgs >= 0 && flags.NArg() > maxArgs) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func runPlay(opts globalOptions, flags *flag.FlagSet, args []string) error {
+	err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+	db, dbPath, err := opts.openDb()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Logs go to a file, since the terminal belongs to the UI. A log given
+	// with --log is already open.
+	if opts.logFlag == "" {
+		logFile, err := openLogFile(filepath.Join(filepath.Dir(dbPath), "gomate.log"))
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		log.SetOutput(logFile)
+	}
+
+	ctx := &app.Context{
+		Store:       storage.NewSQLite(db),
+		Config:      &cfg,
+		SessionPath: filepath.Join(filepath.Dir(dbPath), "session.json"),
+	}
+	err = player.RestoreSessions(ctx)
+	if err != nil {
+		log.Printf("failed to restore sessions: %v", err)
+	}
+
+	m := navigation.SetupNavigation(ctx)
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run GoMate: %w", err)
+	}
+	return nil
+}
+
+func runStats(opts globalOptions, flags *flag.FlagSet, args []string) error {
+	asJSON := flags.Bool("json", false, "print the stats as JSON")
+	err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+	db, _, err := opts.openDb()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := &app.Context{
+		Store:  storage.NewSQLite(db),
+		Config: &cfg,
+	}
+	return player.WriteStats(ctx, flags.Arg(0), os.Stdout, *asJSON)
+}
+
+func runExport(opts globalOptions, flags *flag.FlagSet, args []string) error {
+	err := parseArgs(flags, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	db, _, err := opts.openDb()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	store := storage.NewSQLite(db)
+
+	// Without a file the archive goes to stdout, without the PGN.
+	path := flags.Arg(0)
+	if path == "" || path == "-" {
+		exported, err := archive.Export(store, time.Now())
+		if err != nil {
+			return err
+		}
+		return exported.Write(os.Stdout)
+	}
+
+	exported, err := archive.ExportFile(store, path, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d players and %d games to %s and %s\n", len(exported.Users), len(exported.Games), path, archive.PGNPath(path))
+	return nil
+}
+
+func runImport(opts globalOptions, flags *flag.FlagSet, args []string) error {
+	replace := flags.Bool("replace", false, "delete all players, games, saved games, tournaments and matches before importing instead of merging")
+	collisions := flags.String("collisions", archive.CollisionsFail, "what to do with a player whose username is taken by another player: fail, rename or skip")
+	err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	db, _, err := opts.openDb()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	summary, err := archive.ImportFile(storage.NewSQLite(db), flags.Arg(0), archive.Options{
+		Replace:    *replace,
+		Collisions: *collisions,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d players, %d records, %d games, %d rating changes and %d achievements\n",
+		summary.Users, summary.Records, summary.Games, summary.RatingHistory, summary.Achievements)
+	for old, renamed := range summary.Renamed {
+		fmt.Printf("Renamed %s to %s\n", old, renamed)
+	}
+	for _, skipped := range summary.Skipped {
+		fmt.Printf("Skipped %s, whose username is taken\n", skipped)
+	}
+	return nil
+}
+
+func runFEN(opts globalOptions, flags *flag.FlagSet, args []string) error {
+	gameID := flags.String("game", "", "start from the moves of a stored game")
+	ply := flags.Int("ply", -1, "use only the first n half-moves of the game")
+	err := parseArgs(flags, args, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	var moves []string
+	if *gameID != "" {
+		db, _, err := opts.openDb()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		game, err := storage.NewSQLite(db).GetGame(context.Background(), *gameID)
+		if err != nil {
+			return fmt.Errorf("failed to get game: %w", err)
+		}
+		moves = strings.Fields(game.Moves)
+		if *ply > len(moves) {
+			return fmt.Errorf("game %s has only %d half-moves", *gameID, len(moves))
+		}
+		if *ply >= 0 {
+			moves = moves[:*ply]
+		}
+	} else if *ply >= 0 {
+		return fmt.Errorf("--ply needs --game")
+	}
+
+	fen, err := board.FEN(append(moves, flags.Args()...))
+	if err != nil {
+		return err
+	}
+	fmt.Println(fen)
+	return nil
+}
+
+func runHeadless(_ globalOptions, flags *flag.FlagSet, args []string) error {
+	asJSON := flags.Bool("json", false, "print one JSON object per line instead of text")
+	fen := flags.String("fen", "", "start from this position instead of the initial one")
+	err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	return board.RunHeadless(os.Stdin, os.Stdout, *fen, *asJSON)
+}
+
+func runAdmin(opts globalOptions, _ *flag.FlagSet, args []string) error {
+	db, _, err := opts.openDb()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return admin.Run(storage.NewSQLite(db), args, os.Stdin, os.Stdout)
+}
+
+func runVersion(_ globalOptions, flags *flag.FlagSet, args []string) error {
+	err := parseArgs(flags, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	// Builds installed with go install know the module version.
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok && v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v = info.Main.Version
+	}
+	fmt.Printf("gomate %s (%s)\n", v, runtime.Version())
+	return nil
+}
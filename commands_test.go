@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCommandsWithLegacyDb(t *testing.T) {
+	t.Setenv("GOMATE_DB", "")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	err := os.WriteFile("chess.db", nil, 0o644)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Only the commands opening the database care about a chess.db left in
+	// the current directory.
+	tests := []struct {
+		args       []string
+		wantLegacy bool
+	}{
+		{[]string{"version"}, false},
+		{[]string{"fen", "e4", "e5"}, false},
+		{[]string{"stats", "Alice"}, true},
+		{[]string{"fen", "--game", "missing"}, true},
+	}
+
+	for _, test := range tests {
+		cmd, ok := findCommand(test.args[0])
+		if !ok {
+			t.Fatalf("Unknown command %s", test.args[0])
+		}
+		err := runCommand(globalOptions{}, cmd, test.args[1:])
+		legacy := err != nil && strings.Contains(err.Error(), "found chess.db in the current directory")
+		if legacy != test.wantLegacy {
+			t.Errorf("%v: expected the legacy database to be reported to be %v, got %v", test.args, test.wantLegacy, err)
+		}
+		if !test.wantLegacy && err != nil {
+			t.Errorf("%v failed: %v", test.args, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package achievement
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/deskdaniel/GoMate/internal/rating"
+)
+
+// Game is a finished game seen from one player's side.
+type Game struct {
+	White bool
+	Score float64
+	// Moves holds both players' moves in SAN, White's first.
+	Moves []string
+	// Games counts the player's finished games and WinStreak the wins in a
+	// row, both including this game.
+	Games     int
+	WinStreak int
+}
+
+func (g Game) won() bool {
+	return g.Score == rating.ScoreWin
+}
+
+// ownMoves returns the moves the player made.
+func (g Game) ownMoves() []string {
+	var moves []string
+	for i, move := range g.Moves {
+		if (i%2 == 0) == g.White {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+func castled(moves []string, side string) bool {
+	return slices.ContainsFunc(moves, func(move string) bool {
+		return strings.TrimRight(move, "+#") == side
+	})
+}
+
+// Definition describes an achievement and the games that unlock it.
+type Definition struct {
+	// ID is stored with every unlock and must never change.
+	ID          string
+	Name        string
+	Description string
+	Unlocked    func(Game) bool
+}
+
+func winStreak(n int) func(Game) bool {
+	return func(g Game) bool {
+		return g.WinStreak >= n
+	}
+}
+
+func gamesPlayed(n int) func(Game) bool {
+	return func(g Game) bool {
+		return g.Games >= n
+	}
+}
+
+// Definitions lists every achievement in the order they are shown. Adding
+// an achievement only takes a new entry here.
+var Definitions = []Definition{
+	{
+		ID:          "first-game",
+		Name:        "First Steps",
+		Description: "Finish your first game.",
+		Unlocked:    gamesPlayed(1),
+	},
+	{
+		ID:          "first-win",
+		Name:        "First Victory",
+		Description: "Win a game.",
+		Unlocked:    Game.won,
+	},
+	{
+		ID:          "first-draw",
+		Name:        "Peacemaker",
+		Description: "Draw a game.",
+		Unlocked: func(g Game) bool {
+			return g.Score == rating.ScoreDraw
+		},
+	},
+	{
+		ID:          "black-win",
+		Name:        "Dark Horse",
+		Description: "Win a game with black.",
+		Unlocked: func(g Game) bool {
+			return g.won() && !g.White
+		},
+	},
+	{
+		ID:          "quick-win",
+		Name:        "Blitzkrieg",
+		Description: "Win in under 20 moves.",
+		Unlocked: func(g Game) bool {
+			return g.won() && len(g.ownMoves()) < 20
+		},
+	},
+	{
+		ID:          "promotion-mate",
+		Name:        "Promoted to Mate",
+		Description: "Checkmate with a pawn promotion.",
+		Unlocked: func(g Game) bool {
+			moves := g.ownMoves()
+			if !g.won() || len(moves) == 0 {
+				return false
+			}
+			last := moves[len(moves)-1]
+			return strings.Contains(last, "=") && strings.HasSuffix(last, "#")
+		},
+	},
+	{
+		ID:          "castling-both-sides",
+		Name:        "Castles on Both Wings",
+		Description: "Castle on both sides in one game, one king short and the other long.",
+		Unlocked: func(g Game) bool {
+			return castled(g.Moves, "O-O") && castled(g.Moves, "O-O-O")
+		},
+	},
+	{
+		ID:          "win-streak-3",
+		Name:        "Hat Trick",
+		Description: "Win 3 games in a row.",
+		Unlocked:    winStreak(3),
+	},
+	{
+		ID:          "win-streak-10",
+		Name:        "Unstoppable",
+		Description: "Win 10 games in a row.",
+		Unlocked:    winStreak(10),
+	},
+	{
+		ID:          "games-100",
+		Name:        "Centurion",
+		Description: "Finish 100 games.",
+		Unlocked:    gamesPlayed(100),
+	},
+}
+
+// Find returns the definition of an achievement by its ID.
+func Find(id string) (Definition, bool) {
+	i := slices.IndexFunc(Definitions, func(definition Definition) bool {
+		return definition.ID == id
+	})
+	if i < 0 {
+		return Definition{}, false
+	}
+	return Definitions[i], true
+}
+
+// Unlocked returns every achievement the game earns.
+func Unlocked(g Game) []Definition {
+	var unlocked []Definition
+	for _, definition := range Definitions {
+		if definition.Unlocked(g) {
+			unlocked = append(unlocked, definition)
+		}
+	}
+	return unlocked
+}
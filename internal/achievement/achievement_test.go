@@ -0,0 +1,91 @@
+package achievement
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/rating"
+)
+
+func ids(definitions []Definition) []string {
+	var ids []string
+	for _, definition := range definitions {
+		ids = append(ids, definition.ID)
+	}
+	return ids
+}
+
+func TestUnlocked(t *testing.T) {
+	// A long game where White castles short and Black long.
+	long := strings.Fields(strings.Repeat("Nf3 Nf6 Ng1 Ng8 ", 10) + "O-O O-O-O+")
+
+	tests := []struct {
+		name string
+		game Game
+		want []string
+	}{
+		{
+			"first loss",
+			Game{White: true, Score: rating.ScoreLoss, Moves: long, Games: 1},
+			[]string{"first-game", "castling-both-sides"},
+		},
+		{
+			"draw",
+			Game{White: false, Score: rating.ScoreDraw, Moves: []string{"e4", "e5"}, Games: 2},
+			[]string{"first-game", "first-draw"},
+		},
+		{
+			"castling one side",
+			Game{White: true, Score: rating.ScoreDraw, Moves: []string{"e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5", "O-O"}, Games: 4},
+			[]string{"first-game", "first-draw"},
+		},
+		{
+			"quick win with black",
+			Game{White: false, Score: rating.ScoreWin, Moves: []string{"f3", "e5", "g4", "Qh4#"}, Games: 1, WinStreak: 1},
+			[]string{"first-game", "first-win", "black-win", "quick-win"},
+		},
+		{
+			"mate by promotion after 20 moves",
+			Game{White: true, Score: rating.ScoreWin, Moves: append(long[:40:40], "b8=Q#"), Games: 3, WinStreak: 3},
+			[]string{"first-game", "first-win", "promotion-mate", "win-streak-3"},
+		},
+		{
+			"promotion without mate",
+			Game{White: true, Score: rating.ScoreWin, Moves: []string{"e4", "d5", "exd5", "Kd7", "d6", "Ke8", "dxc7", "Kd7", "cxb8=Q"}, Games: 1, WinStreak: 1},
+			[]string{"first-game", "first-win", "quick-win"},
+		},
+		{
+			"long streak",
+			Game{White: true, Score: rating.ScoreWin, Moves: long, Games: 100, WinStreak: 10},
+			[]string{"first-game", "first-win", "castling-both-sides", "win-streak-3", "win-streak-10", "games-100"},
+		},
+	}
+
+	for _, test := range tests {
+		if got := ids(Unlocked(test.game)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	seen := map[string]bool{}
+	for _, definition := range Definitions {
+		if definition.ID == "" || definition.Name == "" || definition.Description == "" || definition.Unlocked == nil {
+			t.Errorf("Incomplete definition %+v", definition)
+		}
+		if seen[definition.ID] {
+			t.Errorf("Duplicate achievement ID %s", definition.ID)
+		}
+		seen[definition.ID] = true
+
+		found, ok := Find(definition.ID)
+		if !ok || found.Name != definition.Name {
+			t.Errorf("Expected to find %s, got %+v", definition.ID, found)
+		}
+	}
+	if _, ok := Find("unknown"); ok {
+		t.Error("Expected an unknown achievement not to be found")
+	}
+}
@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/rating"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+const usage = `Usage: gomate admin <command>
+
+Commands:
+  list-users                            list the registered players with their stats
+  reset-stats [--yes] <username>|--all  clear the results and ratings of one or all players
+  delete-user [--yes] <username>        delete a player with their stats
+  reset-all [--yes]                     delete every player, game, saved game, tournament and match
+
+Commands that delete data ask for confirmation unless --yes is given.
+`
+
+// Run runs an admin command on the store, reading confirmations from in.
+func Run(store storage.Store, args []string, in io.Reader, out io.Writer) error {
+	if store == nil {
+		return fmt.Errorf("store is nil")
+	}
+	if len(args) == 0 {
+		fmt.Fprint(out, usage)
+		return fmt.Errorf("no admin command given")
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(out)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	all := false
+	if args[0] == "reset-stats" {
+		flags.BoolVar(&all, "all", false, "reset the stats of every player")
+	}
+	positional, err := parseArgs(flags, args[1:])
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(in)
+	confirmed := func(question string) (bool, error) {
+		if *yes {
+			return true, nil
+		}
+		return confirm(reader, out, question)
+	}
+
+	switch {
+	case args[0] == "list-users" && len(positional) == 0:
+		return listUsers(store, out)
+	case args[0] == "reset-stats" && all && len(positional) == 0:
+		ok, err := confirmed("Reset the stats of every player?")
+		if err != nil || !ok {
+			return err
+		}
+		err = resetAllStats(store)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Reset the stats of every player.")
+	case args[0] == "reset-stats" && !all && len(positional) == 1:
+		user, err := getUser(store, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirmed(fmt.Sprintf("Reset the stats of %s?", user.Username))
+		if err != nil || !ok {
+			return err
+		}
+		err = resetStats(store, user)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Reset the stats of %s.\n", user.Username)
+	case args[0] == "delete-user" && len(positional) == 1:
+		user, err := getUser(store, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirmed(fmt.Sprintf("Delete %s and their stats? Their games stay in the history.", user.Username))
+		if err != nil || !ok {
+			return err
+		}
+		err = deleteUser(store, user)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Deleted %s.\n", user.Username)
+	case args[0] == "reset-all" && len(positional) == 0:
+		ok, err := confirmed("Delete every player with their stats, and all games, saved games, tournaments and matches?")
+		if err != nil || !ok {
+			return err
+		}
+		err = resetAll(store)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Deleted every player, game, saved game, tournament and match.")
+	default:
+		fmt.Fprint(out, usage)
+		return fmt.Errorf("invalid admin command: %s", strings.Join(args, " "))
+	}
+
+	return nil
+}
+
+// parseArgs parses flags given anywhere among the arguments and returns the
+// other arguments.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// confirm asks a yes or no question, taking anything but yes as no.
+func confirm(in *bufio.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, err := in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Fprintln(out, "Cancelled.")
+		return false, nil
+	}
+	return true, nil
+}
+
+func listUsers(store storage.Store, out io.Writer) error {
+	users, err := store.ListUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	if len(users) == 0 {
+		fmt.Fprintln(out, "No registered players.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Username\tRegistered\tWins\tLosses\tDraws\tElo\tGlicko-2")
+	for _, user := range users {
+		record, err := store.GetRecordsByUserID(context.Background(), user.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			record = database.Record{Rating: rating.InitialElo, GlickoRating: rating.NewGlicko2().Rating}
+		} else if err != nil {
+			return fmt.Errorf("failed to get stats of %s: %w", user.Username, err)
+		}
+
+		registered := user.CreatedAt.String
+		if len(registered) > 10 {
+			registered = registered[:10]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%.0f\n", user.Username, registered,
+			record.Wins.Int64, record.Losses.Int64, record.Draws.Int64, record.Rating, record.GlickoRating)
+	}
+	return w.Flush()
+}
+
+func getUser(store storage.Store, username string) (database.User, error) {
+	user, err := store.GetUserByName(context.Background(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, fmt.Errorf("no player named %s", username)
+	}
+	if err != nil {
+		return database.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// resetStats clears a player's results, ratings and rating history. Their
+// games and achievements are kept.
+func resetStats(store storage.Store, user database.User) error {
+	return store.WithTx(context.Background(), func(store storage.Store) error {
+		err := store.DeleteRecordsByUserID(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reset record: %w", err)
+		}
+		err = store.DeleteRatingHistoryByUserID(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reset rating history: %w", err)
+		}
+		return nil
+	})
+}
+
+// resetAllStats clears every player's results, ratings and rating history.
+func resetAllStats(store storage.Store) error {
+	return store.WithTx(context.Background(), func(store storage.Store) error {
+		err := store.ResetRecords(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to reset records: %w", err)
+		}
+		err = store.ResetRatingHistory(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to reset rating history: %w", err)
+		}
+		return nil
+	})
+}
+
+func deleteUser(store storage.Store, user database.User) error {
+	err := store.DeleteUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// resetAll deletes every player with everything stored for them, and
+// every game, saved game, tournament and match.
+func resetAll(store storage.Store) error {
+	return store.WithTx(context.Background(), func(store storage.Store) error {
+		return storage.DeleteAll(context.Background(), store)
+	})
+}
@@ -0,0 +1,205 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// clubStore has Alice and Bob with one game between them, won by Alice,
+// and Carol who has not played yet.
+func clubStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	store := storage.NewMemory()
+	ids := map[string]sql.NullString{}
+	for i, username := range []string{"Alice", "Bob", "Carol"} {
+		user, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             username + "-id",
+			Username:       username,
+			CreatedAt:      sql.NullString{String: "2024-01-0" + string(rune('1'+i)) + " 10:00:00", Valid: true},
+			HashedPassword: "hash",
+		})
+		if err != nil {
+			t.Fatalf("RegisterUser failed: %v", err)
+		}
+		ids[username] = sql.NullString{String: user.ID, Valid: true}
+	}
+
+	err := results.Record(&app.Context{Store: store}, database.CreateGameParams{
+		ID:          "game-1",
+		WhiteUserID: ids["Alice"],
+		BlackUserID: ids["Bob"],
+		WhiteName:   "Alice",
+		BlackName:   "Bob",
+		Result:      results.WhiteWins,
+		Termination: "checkmate",
+		TimeControl: "-",
+	})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	return store
+}
+
+func run(store storage.Store, input string, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := Run(store, args, strings.NewReader(input), &out)
+	return out.String(), err
+}
+
+func TestListUsers(t *testing.T) {
+	out, err := run(clubStore(t), "", "list-users")
+	if err != nil {
+		t.Fatalf("list-users failed: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"Username  Registered  Wins  Losses  Draws  Elo   Glicko-2",
+		"Alice     2024-01-01  1     0       0      1520  1662",
+		"Bob       2024-01-02  0     1       0      1480  1338",
+		"Carol     2024-01-03  0     0       0      1500  1500",
+	}, "\n") + "\n"
+	if out != want {
+		t.Errorf("Unexpected list:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = run(storage.NewMemory(), "", "list-users")
+	if err != nil || out != "No registered players.\n" {
+		t.Errorf("Expected no players, got %q, %v", out, err)
+	}
+}
+
+func TestResetStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		args    []string
+		wantErr bool
+		// reset lists the players whose stats are gone afterwards.
+		reset []string
+	}{
+		{"declined", "n\n", []string{"reset-stats", "Alice"}, false, nil},
+		{"no answer", "", []string{"reset-stats", "Alice"}, false, nil},
+		{"confirmed", "y\n", []string{"reset-stats", "Alice"}, false, []string{"Alice"}},
+		{"without asking", "", []string{"reset-stats", "Alice", "--yes"}, false, []string{"Alice"}},
+		{"every player", "yes\n", []string{"reset-stats", "--all"}, false, []string{"Alice", "Bob"}},
+		{"unknown player", "y\n", []string{"reset-stats", "Dave"}, true, nil},
+		{"no player", "y\n", []string{"reset-stats"}, true, nil},
+	}
+
+	for _, test := range tests {
+		store := clubStore(t)
+		_, err := run(store, test.input, test.args...)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.wantErr, err)
+		}
+
+		for _, username := range []string{"Alice", "Bob"} {
+			_, err := store.GetRecordsByUserID(context.Background(), username+"-id")
+			reset := errors.Is(err, sql.ErrNoRows)
+			wantReset := strings.Contains(strings.Join(test.reset, " "), username)
+			if reset != wantReset {
+				t.Errorf("%s: expected %s's stats reset to be %v, got %v", test.name, username, wantReset, err)
+			}
+			history, err := store.ListRatingHistoryByUserID(context.Background(), database.ListRatingHistoryByUserIDParams{
+				UserID: username + "-id",
+				Limit:  10,
+			})
+			if err != nil {
+				t.Fatalf("ListRatingHistoryByUserID failed: %v", err)
+			}
+			if (len(history) == 0) != wantReset {
+				t.Errorf("%s: unexpected rating history for %s: %+v", test.name, username, history)
+			}
+		}
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	store := clubStore(t)
+
+	out, err := run(store, "n\n", "delete-user", "Bob")
+	if err != nil || !strings.Contains(out, "Cancelled.") {
+		t.Errorf("Expected the deletion to be cancelled, got %q, %v", out, err)
+	}
+
+	_, err = run(store, "y\n", "delete-user", "Bob")
+	if err != nil {
+		t.Fatalf("delete-user failed: %v", err)
+	}
+	_, err = store.GetUserByName(context.Background(), "Bob")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected Bob to be deleted, got %v", err)
+	}
+	game, err := store.GetGame(context.Background(), "game-1")
+	if err != nil || game.BlackUserID.Valid {
+		t.Errorf("Expected the game to stay without Bob, got %+v, %v", game, err)
+	}
+
+	_, err = run(store, "y\n", "delete-user", "Bob")
+	if err == nil {
+		t.Error("Expected deleting a missing player to fail")
+	}
+}
+
+func TestResetAll(t *testing.T) {
+	store := clubStore(t)
+	_, err := store.CreateMatch(context.Background(), database.CreateMatchParams{
+		ID:            "match-1",
+		Player1UserID: sql.NullString{String: "Alice-id", Valid: true},
+		Player1Name:   "Alice",
+		Player2Name:   "Guest",
+		Games:         3,
+	})
+	if err != nil {
+		t.Fatalf("CreateMatch failed: %v", err)
+	}
+
+	_, err = run(store, "", "reset-all")
+	if err != nil {
+		t.Fatalf("reset-all failed: %v", err)
+	}
+	count, err := store.CountUsers(context.Background())
+	if err != nil || count != 3 {
+		t.Errorf("Expected reset-all to wait for confirmation, got %d players, %v", count, err)
+	}
+
+	_, err = run(store, "y\n", "reset-all")
+	if err != nil {
+		t.Fatalf("reset-all failed: %v", err)
+	}
+	count, err = store.CountUsers(context.Background())
+	if err != nil || count != 0 {
+		t.Errorf("Expected no players, got %d, %v", count, err)
+	}
+	games, err := store.ListGames(context.Background())
+	if err != nil || len(games) != 0 {
+		t.Errorf("Expected no games, got %+v, %v", games, err)
+	}
+	matches, err := store.ListMatches(context.Background())
+	if err != nil || len(matches) != 0 {
+		t.Errorf("Expected no matches, got %+v, %v", matches, err)
+	}
+}
+
+func TestInvalidCommand(t *testing.T) {
+	for _, args := range [][]string{nil, {"list-users", "Alice"}, {"reset-all", "--all"}, {"promote", "Alice"}} {
+		out, err := run(storage.NewMemory(), "", args...)
+		if err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+		if !strings.Contains(out, "Usage: gomate admin") && !strings.Contains(out, "flag provided but not defined") {
+			t.Errorf("Expected usage for %v, got %q", args, out)
+		}
+	}
+}
@@ -1,19 +1,104 @@
 package app
 
 import (
-	"github.com/deskdaniel/GoMate/internal/database"
+	"slices"
+
+	"github.com/deskdaniel/GoMate/internal/config"
+	"github.com/deskdaniel/GoMate/internal/storage"
 )
 
 type Context struct {
-	Queries  *database.Queries
-	Username string
-	Password string
-	User1    *User
-	User2    *User
+	Store       storage.Store
+	Config      *config.Config
+	SessionPath string
+	Username    string
+	Password    string
+	// Roster holds the signed-in players in the order they signed in.
+	Roster []*User
+	// User1 and User2 are the roster players taking white and black in the
+	// next game, nil for a guest.
+	User1 *User
+	User2 *User
+	// Pairing is set while a tournament or match game is played. Its
+	// players take the seats instead of User1 and User2.
+	Pairing *Pairing
 }
 
 type User struct {
 	ID       string
 	Username string
-	Slot     int
+}
+
+// Pairing links a game to a tournament pairing or to a game of a match.
+type Pairing struct {
+	ID string
+	// Either TournamentID or MatchID names the event of the game.
+	TournamentID string
+	MatchID      string
+	// White and Black are nil for guests.
+	White     *User
+	Black     *User
+	WhiteName string
+	BlackName string
+	// Title is shown above the board, such as the score of a match.
+	Title string
+}
+
+func (c *Context) Settings() config.Config {
+	if c.Config == nil {
+		return config.Default()
+	}
+	return *c.Config
+}
+
+// SignedIn returns the roster player with username, or nil.
+func (c *Context) SignedIn(username string) *User {
+	for _, user := range c.Roster {
+		if user.Username == username {
+			return user
+		}
+	}
+	return nil
+}
+
+// SignIn adds user to the roster and seats them on the first free color.
+func (c *Context) SignIn(user *User) {
+	c.Roster = append(c.Roster, user)
+
+	switch {
+	case c.User1 == nil:
+		c.User1 = user
+	case c.User2 == nil:
+		c.User2 = user
+	}
+}
+
+// SignOut removes user from the roster and from their seat.
+func (c *Context) SignOut(user *User) {
+	c.Roster = slices.DeleteFunc(c.Roster, func(other *User) bool {
+		return other == user
+	})
+
+	if c.User1 == user {
+		c.User1 = nil
+	}
+	if c.User2 == user {
+		c.User2 = nil
+	}
+}
+
+// White returns the player with the white pieces, nil for a guest.
+func (c *Context) White() *User {
+	if c.Pairing != nil {
+		return c.Pairing.White
+	}
+	return c.User1
+}
+
+// Black returns the player with the black pieces, nil for a guest.
+func (c *Context) Black() *User {
+	if c.Pairing != nil {
+		return c.Pairing.Black
+	}
+	return c.User2
 }
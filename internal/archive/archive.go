@@ -0,0 +1,280 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// Version is the format written by Export. Archives up to this version can
+// be imported.
+const Version = 1
+
+// Archive holds every registered player with their record, rating history
+// and achievements, and every finished game.
+type Archive struct {
+	Version       int            `json:"version"`
+	ExportedAt    string         `json:"exported_at"`
+	Users         []User         `json:"users"`
+	Records       []Record       `json:"records"`
+	Games         []Game         `json:"games"`
+	RatingHistory []RatingChange `json:"rating_history"`
+	Achievements  []Achievement  `json:"achievements"`
+}
+
+type User struct {
+	ID             string `json:"id"`
+	Username       string `json:"username"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+type Record struct {
+	ID               string  `json:"id"`
+	UserID           string  `json:"user_id"`
+	CreatedAt        string  `json:"created_at,omitempty"`
+	UpdatedAt        string  `json:"updated_at,omitempty"`
+	Wins             int64   `json:"wins"`
+	Losses           int64   `json:"losses"`
+	Draws            int64   `json:"draws"`
+	Rating           int64   `json:"rating"`
+	RatedGames       int64   `json:"rated_games"`
+	GlickoRating     float64 `json:"glicko_rating"`
+	GlickoDeviation  float64 `json:"glicko_deviation"`
+	GlickoVolatility float64 `json:"glicko_volatility"`
+	GlickoRatedAt    string  `json:"glicko_rated_at,omitempty"`
+}
+
+type Game struct {
+	ID          string `json:"id"`
+	WhiteUserID string `json:"white_user_id,omitempty"`
+	BlackUserID string `json:"black_user_id,omitempty"`
+	WhiteName   string `json:"white_name"`
+	BlackName   string `json:"black_name"`
+	Result      string `json:"result"`
+	Termination string `json:"termination"`
+	StartedAt   string `json:"started_at"`
+	EndedAt     string `json:"ended_at"`
+	Moves       string `json:"moves"`
+	FinalFen    string `json:"final_fen"`
+	TimeControl string `json:"time_control"`
+}
+
+type RatingChange struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	GameID       string `json:"game_id"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	RatingBefore int64  `json:"rating_before"`
+	RatingAfter  int64  `json:"rating_after"`
+}
+
+type Achievement struct {
+	UserID        string `json:"user_id"`
+	AchievementID string `json:"achievement_id"`
+	GameID        string `json:"game_id"`
+	UnlockedAt    string `json:"unlocked_at"`
+}
+
+// nullString treats an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt64(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+// Export reads the archive from a consistent snapshot of the store.
+func Export(store storage.Store, now time.Time) (Archive, error) {
+	if store == nil {
+		return Archive{}, fmt.Errorf("store is nil")
+	}
+
+	archive := Archive{
+		Version:       Version,
+		ExportedAt:    now.Format(time.RFC3339),
+		Users:         []User{},
+		Records:       []Record{},
+		Games:         []Game{},
+		RatingHistory: []RatingChange{},
+		Achievements:  []Achievement{},
+	}
+	err := store.WithTx(context.Background(), func(store storage.Store) error {
+		users, err := store.ListUsers(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		for _, user := range users {
+			archive.Users = append(archive.Users, User{
+				ID:             user.ID,
+				Username:       user.Username,
+				CreatedAt:      user.CreatedAt.String,
+				UpdatedAt:      user.UpdatedAt.String,
+				HashedPassword: user.HashedPassword,
+			})
+		}
+
+		records, err := store.ListRecords(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list records: %w", err)
+		}
+		for _, record := range records {
+			archive.Records = append(archive.Records, Record{
+				ID:               record.ID,
+				UserID:           record.UserID,
+				CreatedAt:        record.CreatedAt.String,
+				UpdatedAt:        record.UpdatedAt.String,
+				Wins:             record.Wins.Int64,
+				Losses:           record.Losses.Int64,
+				Draws:            record.Draws.Int64,
+				Rating:           record.Rating,
+				RatedGames:       record.RatedGames,
+				GlickoRating:     record.GlickoRating,
+				GlickoDeviation:  record.GlickoDeviation,
+				GlickoVolatility: record.GlickoVolatility,
+				GlickoRatedAt:    record.GlickoRatedAt.String,
+			})
+		}
+
+		games, err := store.ListGames(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list games: %w", err)
+		}
+		for _, game := range games {
+			archive.Games = append(archive.Games, Game{
+				ID:          game.ID,
+				WhiteUserID: game.WhiteUserID.String,
+				BlackUserID: game.BlackUserID.String,
+				WhiteName:   game.WhiteName,
+				BlackName:   game.BlackName,
+				Result:      game.Result,
+				Termination: game.Termination,
+				StartedAt:   game.StartedAt,
+				EndedAt:     game.EndedAt,
+				Moves:       game.Moves,
+				FinalFen:    game.FinalFen,
+				TimeControl: game.TimeControl,
+			})
+		}
+
+		history, err := store.ListRatingHistory(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list rating history: %w", err)
+		}
+		for _, entry := range history {
+			archive.RatingHistory = append(archive.RatingHistory, RatingChange{
+				ID:           entry.ID,
+				UserID:       entry.UserID,
+				GameID:       entry.GameID,
+				CreatedAt:    entry.CreatedAt.String,
+				RatingBefore: entry.RatingBefore,
+				RatingAfter:  entry.RatingAfter,
+			})
+		}
+
+		achievements, err := store.ListAchievements(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list achievements: %w", err)
+		}
+		for _, achievement := range achievements {
+			archive.Achievements = append(archive.Achievements, Achievement(achievement))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Archive{}, err
+	}
+
+	return archive, nil
+}
+
+// Write writes the archive as indented JSON.
+func (a Archive) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(a)
+}
+
+// Read reads an archive, refusing versions this build does not know.
+func Read(r io.Reader) (Archive, error) {
+	var archive Archive
+	err := json.NewDecoder(r).Decode(&archive)
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if archive.Version < 1 {
+		return Archive{}, fmt.Errorf("not a GoMate archive")
+	}
+	if archive.Version > Version {
+		return Archive{}, fmt.Errorf("archive version %d is newer than this GoMate supports (%d)", archive.Version, Version)
+	}
+	return archive, nil
+}
+
+// PGNPath returns where the games of an archive are written as PGN: next to
+// it, with a .pgn extension.
+func PGNPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".pgn"
+}
+
+// ExportFile exports the store to path and its games to PGNPath(path). The
+// archive holds password hashes, so only the owner can read the files.
+func ExportFile(store storage.Store, path string, now time.Time) (Archive, error) {
+	archive, err := Export(store, now)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	err = writeFile(path, archive.Write)
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to write archive: %w", err)
+	}
+	err = writeFile(PGNPath(path), func(w io.Writer) error {
+		return WritePGN(w, archive.Games)
+	})
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to write PGN: %w", err)
+	}
+
+	return archive, nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ImportFile imports the archive at path into the store.
+func ImportFile(store storage.Store, path string, options Options) (Summary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	archive, err := Read(file)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	return Import(store, archive, options)
+}
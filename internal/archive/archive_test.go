@@ -0,0 +1,348 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/deskdaniel/GoMate/internal/storage/storagetest"
+)
+
+const testFEN = "rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3"
+
+func recordGame(t *testing.T, store storage.Store, id string, white, black *app.User, result, endedAt string) {
+	t.Helper()
+
+	err := results.Record(&app.Context{Store: store}, database.CreateGameParams{
+		ID:          id,
+		WhiteUserID: storagetest.UserID(white),
+		BlackUserID: storagetest.UserID(black),
+		WhiteName:   "White",
+		BlackName:   "Black",
+		Result:      result,
+		Termination: "checkmate",
+		StartedAt:   endedAt,
+		EndedAt:     endedAt,
+		Moves:       "f3 e5 g4 Qh4#",
+		FinalFen:    testFEN,
+		TimeControl: "-",
+	})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+}
+
+// clubStore has Alice and Bob with two games between them and one against a
+// guest.
+func clubStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	store := storage.NewMemory()
+	alice := storagetest.RegisterUser(t, store, "alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, store, "bob-id", "Bob")
+	recordGame(t, store, "game-1", alice, bob, results.BlackWins, "2024-01-01T10:00:00Z")
+	recordGame(t, store, "game-2", bob, alice, results.Draw, "2024-01-02T10:00:00Z")
+	recordGame(t, store, "game-3", nil, alice, results.WhiteWins, "2024-01-03T10:00:00Z")
+	return store
+}
+
+func exportArchive(t *testing.T, store storage.Store) Archive {
+	t.Helper()
+
+	archive, err := Export(store, time.Now())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Go through JSON like an archive file does.
+	var b bytes.Buffer
+	err = archive.Write(&b)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	archive, err = Read(&b)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return archive
+}
+
+func TestExportImport(t *testing.T) {
+	source := exportArchive(t, clubStore(t))
+	if len(source.Users) != 2 || len(source.Records) != 2 || len(source.Games) != 3 || len(source.RatingHistory) != 4 || len(source.Achievements) == 0 {
+		t.Fatalf("Unexpected archive %+v", source)
+	}
+
+	store := storage.NewMemory()
+	summary, err := Import(store, source, Options{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	want := Summary{
+		Users:         2,
+		Records:       2,
+		Games:         3,
+		RatingHistory: 4,
+		Achievements:  len(source.Achievements),
+		Renamed:       map[string]string{},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("Expected summary %+v, got %+v", want, summary)
+	}
+
+	copied := exportArchive(t, store)
+	copied.ExportedAt = source.ExportedAt
+	if !reflect.DeepEqual(copied, source) {
+		t.Errorf("Expected the imported store to export the same archive:\n%+v\ngot:\n%+v", source, copied)
+	}
+
+	// Importing the same archive again adds nothing.
+	summary, err = Import(store, source, Options{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !reflect.DeepEqual(summary, Summary{Renamed: map[string]string{}}) {
+		t.Errorf("Expected a second import to add nothing, got %+v", summary)
+	}
+}
+
+func TestImportMerge(t *testing.T) {
+	source := exportArchive(t, clubStore(t))
+
+	tests := []struct {
+		collisions string
+		wantErr    bool
+		usernames  []string
+		renamed    map[string]string
+		skipped    []string
+	}{
+		{CollisionsFail, true, nil, nil, nil},
+		{CollisionsRename, false, []string{"Alice", "Bob", "Bob_2", "Carol"}, map[string]string{"Bob": "Bob_2"}, nil},
+		{CollisionsSkip, false, []string{"Alice", "Bob", "Carol"}, map[string]string{}, []string{"Bob"}},
+	}
+
+	for _, test := range tests {
+		// The local Alice is the same player, with a newer record. The
+		// local Bob is someone else.
+		store := storage.NewMemory()
+		alice := storagetest.RegisterUser(t, store, "alice-id", "Alice")
+		storagetest.RegisterUser(t, store, "other-bob-id", "Bob")
+		storagetest.RegisterUser(t, store, "carol-id", "Carol")
+		recordGame(t, store, "game-4", alice, nil, results.WhiteWins, time.Now().Format(time.RFC3339))
+		before := exportArchive(t, store)
+
+		summary, err := Import(store, source, Options{Collisions: test.collisions})
+		if test.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "Bob") {
+				t.Errorf("%s: expected an error naming Bob, got %v", test.collisions, err)
+			}
+			after := exportArchive(t, store)
+			after.ExportedAt = before.ExportedAt
+			if !reflect.DeepEqual(after, before) {
+				t.Errorf("%s: expected a failed import to change nothing", test.collisions)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Import failed: %v", test.collisions, err)
+		}
+		if !reflect.DeepEqual(summary.Renamed, test.renamed) || !reflect.DeepEqual(summary.Skipped, test.skipped) {
+			t.Errorf("%s: unexpected summary %+v", test.collisions, summary)
+		}
+
+		users, err := store.ListUsers(context.Background())
+		if err != nil {
+			t.Fatalf("ListUsers failed: %v", err)
+		}
+		var usernames []string
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+		if !reflect.DeepEqual(usernames, test.usernames) {
+			t.Errorf("%s: expected players %v, got %v", test.collisions, test.usernames, usernames)
+		}
+
+		// Alice's local record is newer, so it is kept.
+		record, err := store.GetRecordsByUserID(context.Background(), "alice-id")
+		if err != nil {
+			t.Fatalf("GetRecordsByUserID failed: %v", err)
+		}
+		if record.Wins.Int64 != 1 || record.Losses.Int64 != 0 {
+			t.Errorf("%s: expected Alice's newer local record, got %+v", test.collisions, record)
+		}
+
+		games, err := store.ListGames(context.Background())
+		if err != nil {
+			t.Fatalf("ListGames failed: %v", err)
+		}
+		if len(games) != 4 {
+			t.Errorf("%s: expected 4 games, got %d", test.collisions, len(games))
+		}
+		for _, game := range games {
+			if game.ID == "game-1" && game.BlackUserID.Valid != (test.collisions == CollisionsRename) {
+				t.Errorf("%s: unexpected link to the imported Bob in %+v", test.collisions, game)
+			}
+		}
+	}
+}
+
+func TestImportReplace(t *testing.T) {
+	source := exportArchive(t, clubStore(t))
+
+	store := storage.NewMemory()
+	dave := storagetest.RegisterUser(t, store, "dave-id", "Dave")
+	recordGame(t, store, "game-4", dave, nil, results.WhiteWins, "2024-01-04T10:00:00Z")
+	_, err := store.CreateMatch(context.Background(), database.CreateMatchParams{
+		ID:            "match-1",
+		Player1UserID: storagetest.UserID(dave),
+		Player1Name:   "Dave",
+		Player2Name:   "Guest",
+		Games:         3,
+	})
+	if err != nil {
+		t.Fatalf("CreateMatch failed: %v", err)
+	}
+
+	_, err = Import(store, source, Options{Replace: true})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	matches, err := store.ListMatches(context.Background())
+	if err != nil || len(matches) != 0 {
+		t.Errorf("Expected no matches, got %+v, %v", matches, err)
+	}
+
+	copied := exportArchive(t, store)
+	copied.ExportedAt = source.ExportedAt
+	if !reflect.DeepEqual(copied, source) {
+		t.Errorf("Expected the store to hold only the archive, got %+v", copied)
+	}
+}
+
+func TestImportOptions(t *testing.T) {
+	_, err := Import(storage.NewMemory(), Archive{Version: Version}, Options{Collisions: "merge"})
+	if err == nil {
+		t.Error("Expected an unknown collision handling to fail")
+	}
+}
+
+func TestImportValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Archive)
+	}{
+		{"username too long", func(a *Archive) { a.Users[0].Username = "Alice_with_a_long_name" }},
+		{"username with spaces", func(a *Archive) { a.Users[0].Username = "Alice Smith" }},
+		{"player without ID", func(a *Archive) { a.Users[0].ID = "" }},
+		{"plain password", func(a *Archive) { a.Users[0].HashedPassword = "secret" }},
+		{"crafted hash", func(a *Archive) {
+			a.Users[0].HashedPassword = "$argon2id$v=19$m=19456,t=0,p=1$c2FsdHNhbHQ$a2tra2tra2tra2tra2tra2tra2tra2tra2tra2tra2s"
+		}},
+		{"unknown result", func(a *Archive) { a.Games[0].Result = "2-0" }},
+		{"unknown termination", func(a *Archive) { a.Games[0].Termination = "boredom" }},
+		{"invalid final position", func(a *Archive) { a.Games[0].FinalFen = "fen" }},
+		{"invalid moves", func(a *Archive) { a.Games[0].Moves = "f3 e5 Ke3 Qh4#" }},
+		{"moves ending elsewhere", func(a *Archive) { a.Games[0].Moves = "f3 e5 g4" }},
+		{"player against themselves", func(a *Archive) { a.Games[0].BlackUserID = a.Games[0].WhiteUserID }},
+	}
+
+	for _, test := range tests {
+		source := exportArchive(t, clubStore(t))
+		test.change(&source)
+
+		store := storage.NewMemory()
+		_, err := Import(store, source, Options{})
+		if err == nil {
+			t.Errorf("%s: expected the import to fail", test.name)
+		}
+		count, err := store.CountUsers(context.Background())
+		if err != nil || count != 0 {
+			t.Errorf("%s: expected a failed import to add nothing, got %d players, %v", test.name, count, err)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"current version", `{"version": 1, "users": []}`, false},
+		{"newer version", `{"version": 2}`, true},
+		{"not an archive", `{"players": []}`, true},
+		{"not JSON", `version 1`, true},
+	}
+
+	for _, test := range tests {
+		_, err := Read(strings.NewReader(test.input))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestWritePGN(t *testing.T) {
+	games := []Game{
+		{
+			WhiteName:   `Alice "Ace"`,
+			BlackName:   "Guest 2",
+			Result:      results.BlackWins,
+			Termination: "checkmate",
+			StartedAt:   "2024-01-31T10:00:00Z",
+			Moves:       "f3 e5 g4 Qh4#",
+			TimeControl: "-",
+		},
+		{
+			WhiteName: "Bob",
+			BlackName: "Carol",
+			Result:    results.Draw,
+			Moves:     strings.Repeat("Nf3 Nf6 Ng1 Ng8 ", 5),
+		},
+	}
+
+	var b bytes.Buffer
+	err := WritePGN(&b, games)
+	if err != nil {
+		t.Fatalf("WritePGN failed: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`[Event "GoMate game"]`,
+		`[Site "GoMate"]`,
+		`[Date "2024.01.31"]`,
+		`[Round "-"]`,
+		`[White "Alice \"Ace\""]`,
+		`[Black "Guest 2"]`,
+		`[Result "0-1"]`,
+		`[Termination "checkmate"]`,
+		`[TimeControl "-"]`,
+		``,
+		`1. f3 e5 2. g4 Qh4# 0-1`,
+		``,
+		`[Event "GoMate game"]`,
+		`[Site "GoMate"]`,
+		`[Date "????.??.??"]`,
+		`[Round "-"]`,
+		`[White "Bob"]`,
+		`[Black "Carol"]`,
+		`[Result "1/2-1/2"]`,
+		`[Termination ""]`,
+		`[TimeControl ""]`,
+		``,
+		`1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 4. Ng1 Ng8 5. Nf3 Nf6 6. Ng1 Ng8 7. Nf3 Nf6 8.`,
+		`Ng1 Ng8 9. Nf3 Nf6 10. Ng1 Ng8 1/2-1/2`,
+		``,
+	}, "\n") + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("Unexpected PGN:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/deskdaniel/GoMate/internal/board"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/player"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// What to do with an imported player whose username belongs to another
+// local player.
+const (
+	CollisionsFail   = "fail"
+	CollisionsRename = "rename"
+	CollisionsSkip   = "skip"
+)
+
+var collisionPolicies = []string{CollisionsFail, CollisionsRename, CollisionsSkip}
+
+// Options control how an archive is imported.
+type Options struct {
+	// Replace deletes every player, game, saved game, tournament and match
+	// before importing. Otherwise the archive is merged into the store.
+	Replace bool
+	// Collisions is one of CollisionsFail, CollisionsRename and
+	// CollisionsSkip.
+	Collisions string
+}
+
+// Summary counts what an import added.
+type Summary struct {
+	Users         int
+	Records       int
+	Games         int
+	RatingHistory int
+	Achievements  int
+	// Renamed maps the usernames of renamed players to their new names.
+	Renamed map[string]string
+	Skipped []string
+}
+
+// uniqueName appends the lowest free number to a username, keeping it
+// within the username length limit.
+func uniqueName(username string, taken map[string]bool) string {
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf("_%d", n)
+		base := username[:min(len(username), player.MaxUsernameLength-len(suffix))]
+		if !taken[base+suffix] {
+			return base + suffix
+		}
+	}
+}
+
+// samePosition compares the pieces and castling rights of two positions in
+// FEN. The side to move is left out, as accepting a draw offer passes the
+// turn without a move.
+func samePosition(a, b string) bool {
+	fieldsA, fieldsB := strings.Fields(a), strings.Fields(b)
+	if len(fieldsA) < 3 || len(fieldsB) < 3 {
+		return false
+	}
+	return fieldsA[0] == fieldsB[0] && fieldsA[2] == fieldsB[2]
+}
+
+// validate checks the players and games of an archive the way GoMate checks
+// its own, so an edited or crafted archive cannot store what the game would
+// refuse.
+func validate(archive Archive) error {
+	for _, user := range archive.Users {
+		if user.ID == "" {
+			return fmt.Errorf("player %s has no ID", user.Username)
+		}
+		err := player.CheckUsername(user.Username)
+		if err != nil {
+			return fmt.Errorf("invalid player %q: %w", user.Username, err)
+		}
+		err = player.ValidatePasswordHash(user.HashedPassword)
+		if err != nil {
+			return fmt.Errorf("invalid password of player %s: %w", user.Username, err)
+		}
+	}
+
+	for _, game := range archive.Games {
+		if game.ID == "" {
+			return fmt.Errorf("game between %s and %s has no ID", game.WhiteName, game.BlackName)
+		}
+		if game.Result != results.WhiteWins && game.Result != results.BlackWins && game.Result != results.Draw {
+			return fmt.Errorf("game %s has invalid result %q", game.ID, game.Result)
+		}
+		if !slices.Contains(results.Terminations, game.Termination) {
+			return fmt.Errorf("game %s has invalid termination %q", game.ID, game.Termination)
+		}
+		if game.WhiteUserID != "" && game.WhiteUserID == game.BlackUserID {
+			return fmt.Errorf("game %s has the same player on both sides", game.ID)
+		}
+		err := board.ValidateFEN(game.FinalFen)
+		if err != nil {
+			return fmt.Errorf("game %s has invalid final position: %w", game.ID, err)
+		}
+		fen, err := board.FEN(strings.Fields(game.Moves))
+		if err != nil {
+			return fmt.Errorf("game %s has invalid moves: %w", game.ID, err)
+		}
+		if !samePosition(fen, game.FinalFen) {
+			return fmt.Errorf("game %s does not end in its final position", game.ID)
+		}
+	}
+
+	return nil
+}
+
+// Import adds the archive to the store in a single transaction.
+//
+// Players are matched by ID. A player who is already in the store keeps
+// their username and password, and their record is replaced only by a
+// more recent one. Games, rating changes and achievements that are
+// already in the store are left alone.
+func Import(store storage.Store, archive Archive, options Options) (Summary, error) {
+	if store == nil {
+		return Summary{}, fmt.Errorf("store is nil")
+	}
+	if options.Collisions == "" {
+		options.Collisions = CollisionsFail
+	}
+	if !slices.Contains(collisionPolicies, options.Collisions) {
+		return Summary{}, fmt.Errorf("unknown collision handling %q, use one of %s", options.Collisions, strings.Join(collisionPolicies, ", "))
+	}
+	err := validate(archive)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var summary Summary
+	err = store.WithTx(context.Background(), func(store storage.Store) error {
+		summary = Summary{Renamed: map[string]string{}}
+
+		if options.Replace {
+			err := storage.DeleteAll(context.Background(), store)
+			if err != nil {
+				return err
+			}
+		}
+
+		users, err := importUsers(store, archive.Users, options.Collisions, &summary)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range archive.Records {
+			if !users[record.UserID] {
+				continue
+			}
+			imported, err := store.ImportRecord(context.Background(), database.ImportRecordParams{
+				ID:               record.ID,
+				UserID:           record.UserID,
+				CreatedAt:        nullString(record.CreatedAt),
+				UpdatedAt:        nullString(record.UpdatedAt),
+				Wins:             nullInt64(record.Wins),
+				Losses:           nullInt64(record.Losses),
+				Draws:            nullInt64(record.Draws),
+				Rating:           record.Rating,
+				RatedGames:       record.RatedGames,
+				GlickoRating:     record.GlickoRating,
+				GlickoDeviation:  record.GlickoDeviation,
+				GlickoVolatility: record.GlickoVolatility,
+				GlickoRatedAt:    nullString(record.GlickoRatedAt),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import record: %w", err)
+			}
+			summary.Records += int(imported)
+		}
+
+		// Games of skipped players are kept without a link to them.
+		userID := func(id string) string {
+			if users[id] {
+				return id
+			}
+			return ""
+		}
+		games := map[string]bool{}
+		for _, game := range archive.Games {
+			imported, err := store.ImportGame(context.Background(), database.ImportGameParams{
+				ID:          game.ID,
+				WhiteUserID: nullString(userID(game.WhiteUserID)),
+				BlackUserID: nullString(userID(game.BlackUserID)),
+				WhiteName:   game.WhiteName,
+				BlackName:   game.BlackName,
+				Result:      game.Result,
+				Termination: game.Termination,
+				StartedAt:   game.StartedAt,
+				EndedAt:     game.EndedAt,
+				Moves:       game.Moves,
+				FinalFen:    game.FinalFen,
+				TimeControl: game.TimeControl,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import game %s: %w", game.ID, err)
+			}
+			summary.Games += int(imported)
+			games[game.ID] = true
+		}
+
+		for _, entry := range archive.RatingHistory {
+			if !users[entry.UserID] || !games[entry.GameID] {
+				continue
+			}
+			imported, err := store.ImportRatingHistory(context.Background(), database.ImportRatingHistoryParams{
+				ID:           entry.ID,
+				UserID:       entry.UserID,
+				GameID:       entry.GameID,
+				CreatedAt:    nullString(entry.CreatedAt),
+				RatingBefore: entry.RatingBefore,
+				RatingAfter:  entry.RatingAfter,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import rating history: %w", err)
+			}
+			summary.RatingHistory += int(imported)
+		}
+
+		for _, achievement := range archive.Achievements {
+			if !users[achievement.UserID] {
+				continue
+			}
+			imported, err := store.UnlockAchievement(context.Background(), database.UnlockAchievementParams(achievement))
+			if err != nil {
+				return fmt.Errorf("failed to import achievement: %w", err)
+			}
+			summary.Achievements += int(imported)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+
+	return summary, nil
+}
+
+// importUsers registers the archive's players who are not in the store yet
+// and returns the IDs of the players whose data is imported.
+func importUsers(store storage.Store, archived []User, collisions string, summary *Summary) (map[string]bool, error) {
+	local, err := store.ListUsers(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	ids := map[string]bool{}
+	taken := map[string]bool{}
+	for _, user := range local {
+		ids[user.ID] = true
+		taken[user.Username] = true
+	}
+
+	users := map[string]bool{}
+	var collided []string
+	for _, user := range archived {
+		if ids[user.ID] {
+			users[user.ID] = true
+			continue
+		}
+
+		username := user.Username
+		if taken[username] {
+			switch collisions {
+			case CollisionsFail:
+				collided = append(collided, username)
+				continue
+			case CollisionsSkip:
+				summary.Skipped = append(summary.Skipped, username)
+				continue
+			}
+			username = uniqueName(username, taken)
+			summary.Renamed[user.Username] = username
+		}
+
+		_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             user.ID,
+			Username:       username,
+			CreatedAt:      nullString(user.CreatedAt),
+			UpdatedAt:      nullString(user.UpdatedAt),
+			HashedPassword: user.HashedPassword,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import user %s: %w", user.Username, err)
+		}
+		ids[user.ID] = true
+		taken[username] = true
+		users[user.ID] = true
+		summary.Users++
+	}
+
+	if len(collided) > 0 {
+		return nil, fmt.Errorf("usernames already taken by other players: %s", strings.Join(collided, ", "))
+	}
+
+	return users, nil
+}
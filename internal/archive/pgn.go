@@ -0,0 +1,77 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const pgnLineLength = 80
+
+func pgnTag(name, value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return fmt.Sprintf("[%s \"%s\"]\n", name, value)
+}
+
+// pgnDate turns an RFC 3339 time into a PGN date, e.g. 2024.01.31.
+func pgnDate(timestamp string) string {
+	if len(timestamp) < 10 {
+		return "????.??.??"
+	}
+	return strings.ReplaceAll(timestamp[:10], "-", ".")
+}
+
+// pgnMovetext numbers the moves and wraps them at the PGN line length.
+func pgnMovetext(moves, result string) string {
+	var tokens []string
+	for i, move := range strings.Fields(moves) {
+		if i%2 == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d.", i/2+1))
+		}
+		tokens = append(tokens, move)
+	}
+	tokens = append(tokens, result)
+
+	var b strings.Builder
+	length := 0
+	for i, token := range tokens {
+		if i > 0 {
+			if length+1+len(token) > pgnLineLength {
+				b.WriteString("\n")
+				length = 0
+			} else {
+				b.WriteString(" ")
+				length++
+			}
+		}
+		b.WriteString(token)
+		length += len(token)
+	}
+	return b.String()
+}
+
+// WritePGN writes the games in PGN, one after another.
+func WritePGN(w io.Writer, games []Game) error {
+	for _, game := range games {
+		var b strings.Builder
+		b.WriteString(pgnTag("Event", "GoMate game"))
+		b.WriteString(pgnTag("Site", "GoMate"))
+		b.WriteString(pgnTag("Date", pgnDate(game.StartedAt)))
+		b.WriteString(pgnTag("Round", "-"))
+		b.WriteString(pgnTag("White", game.WhiteName))
+		b.WriteString(pgnTag("Black", game.BlackName))
+		b.WriteString(pgnTag("Result", game.Result))
+		b.WriteString(pgnTag("Termination", game.Termination))
+		b.WriteString(pgnTag("TimeControl", game.TimeControl))
+		b.WriteString("\n")
+		b.WriteString(pgnMovetext(game.Moves, game.Result))
+		b.WriteString("\n\n")
+
+		_, err := io.WriteString(w, b.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
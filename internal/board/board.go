@@ -1,8 +1,6 @@
 package board
 
 import (
-	"context"
-	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,9 +10,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/deskdaniel/GoMate/internal/app"
-	"github.com/deskdaniel/GoMate/internal/database"
 	"github.com/deskdaniel/GoMate/internal/messages"
-	"github.com/google/uuid"
+	"github.com/deskdaniel/GoMate/internal/results"
 )
 
 type piece interface {
@@ -219,6 +216,9 @@ type boardModel struct {
 	offeredDraw     bool
 	gameOver        bool
 	gameOverMsg     string
+	gameID          string
+	startedAt       time.Time
+	moves           []string
 }
 
 func NewBoardModel(ctx *app.Context) tea.Model {
@@ -227,13 +227,7 @@ func NewBoardModel(ctx *app.Context) tea.Model {
 
 	input := textinput.New()
 
-	name := ""
-	if ctx.User1 != nil {
-		name = ctx.User1.Username
-	} else {
-		name = "Player 1"
-	}
-	input.Prompt = fmt.Sprintf("%s's(white) turn: ", name)
+	input.Prompt = fmt.Sprintf("%s's(white) turn: ", promptName(ctx, true))
 	input.Placeholder = "Enter command (e.g. A2 A3)"
 	input.Focus()
 	input.CharLimit = 15
@@ -244,6 +238,7 @@ func NewBoardModel(ctx *app.Context) tea.Model {
 		ctx:       ctx,
 		whiteTurn: whiteTurn,
 		input:     input,
+		startedAt: time.Now(),
 	}
 
 	return &m
@@ -251,6 +246,9 @@ func NewBoardModel(ctx *app.Context) tea.Model {
 
 func (m *boardModel) View() string {
 	s := m.board.renderString()
+	if m.ctx.Pairing != nil && m.ctx.Pairing.Title != "" {
+		s = lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true).Render(m.ctx.Pairing.Title) + "\n\n" + s
+	}
 	if m.promotionSquare != nil {
 		s += "Pawn promotion! Select a piece to promote to:\n"
 		pieces := []string{"Queen", "Rook", "Bishop", "Knight"}
@@ -264,7 +262,15 @@ func (m *boardModel) View() string {
 		return s
 	}
 	if m.gameOver {
-		s += fmt.Sprintf("Game over!\n\n%s\n\nPress any key to exit to main menu.", m.gameOverMsg)
+		exit := "main menu"
+		switch {
+		case m.ctx.Pairing == nil:
+		case m.ctx.Pairing.MatchID != "":
+			exit = "the match"
+		default:
+			exit = "the tournament"
+		}
+		s += fmt.Sprintf("Game over!\n\n%s\n\nPress any key to exit to %s.", m.gameOverMsg, exit)
 		return s
 	}
 	if m.check != "" {
@@ -301,11 +307,25 @@ const (
 	knightField
 )
 
+const (
+	terminationCheckmate            = results.Checkmate
+	terminationResignation          = results.Resignation
+	terminationStalemate            = results.Stalemate
+	terminationAgreement            = results.Agreement
+	terminationInsufficientMaterial = results.InsufficientMaterial
+	terminationFiftyMove            = results.FiftyMoveRule
+)
+
+const (
+	resultWhiteWins = results.WhiteWins
+	resultBlackWins = results.BlackWins
+	resultDraw      = results.Draw
+)
+
 type overMsg struct {
-	winner  *app.User
-	loser   *app.User
-	draw    bool
-	message string
+	message     string
+	result      string
+	termination string
 }
 
 func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -315,6 +335,16 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.gameOver {
 		switch msg.(type) {
 		case tea.KeyMsg:
+			if pairing := m.ctx.Pairing; pairing != nil && pairing.MatchID != "" {
+				return m, func() tea.Msg {
+					return messages.SwitchToMatch{ID: pairing.MatchID}
+				}
+			}
+			if pairing := m.ctx.Pairing; pairing != nil {
+				return m, func() tea.Msg {
+					return messages.SwitchToTournament{ID: pairing.TournamentID}
+				}
+			}
 			return m, func() tea.Msg {
 				return messages.SwitchToMainMenu{}
 			}
@@ -361,10 +391,14 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.promotionColor = ""
 				m.promotionFocus = 0
 
-				switchTurn(m)
-				resetInputField(m)
+				if len(m.moves) > 0 {
+					letter, err := pieceLetter(newPiece)
+					if err == nil {
+						m.moves[len(m.moves)-1] += strings.ToLower(string(letter))
+					}
+				}
 
-				return m, nil
+				return m, finishMove(m)
 			}
 		}
 	}
@@ -392,47 +426,22 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		parts := strings.Fields(msg.input)
 		if len(parts) == 1 {
 			message := strings.ToLower(parts[0])
-			var winnerName string
-			var loserName string
 			switch message {
 			case "resign", "surrender", "surr", "forfeit", "ff":
-				var winner *app.User
-				var loser *app.User
+				result := resultWhiteWins
+				winnerName := playerName(m.ctx, true)
+				loserName := playerName(m.ctx, false)
 				if m.whiteTurn {
-					winner = m.ctx.User2
-					if winner != nil {
-						winnerName = winner.Username
-					} else {
-						winnerName = "Guest 2"
-					}
-					loser = m.ctx.User1
-					if loser != nil {
-						loserName = loser.Username
-					} else {
-						loserName = "Guest 1"
-					}
-				} else {
-					winner = m.ctx.User1
-					if winner != nil {
-						winnerName = winner.Username
-					} else {
-						winnerName = "Guest 1"
-					}
-					loser = m.ctx.User2
-					if loser != nil {
-						loserName = loser.Username
-					} else {
-						loserName = "Guest 2"
-					}
+					result = resultBlackWins
+					winnerName, loserName = loserName, winnerName
 				}
 				return m, func() tea.Msg {
 					return overMsg{
-						winner: winner,
-						loser:  loser,
-						draw:   false,
 						message: fmt.Sprintf("%s has resigned. %s wins!",
 							loserName,
 							winnerName),
+						result:      result,
+						termination: terminationResignation,
 					}
 				}
 			case "draw":
@@ -441,10 +450,9 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.input.Blur()
 					return m, func() tea.Msg {
 						return overMsg{
-							winner:  nil,
-							loser:   nil,
-							draw:    true,
-							message: message,
+							message:     message,
+							result:      resultDraw,
+							termination: terminationAgreement,
 						}
 					}
 				} else {
@@ -454,6 +462,7 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					switchTurn(m)
 					resetInputField(m)
+					m.saveGame()
 
 					return m, nil
 				}
@@ -466,6 +475,7 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.drawTimer = 1
 			switchTurn(m)
 			resetInputField(m)
+			m.saveGame()
 			return m, nil
 		}
 
@@ -515,6 +525,7 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = strings.ToUpper(errString[:1]) + errString[1:]
 			return m, nil
 		}
+		m.moves = append(m.moves, strings.ToLower(fromStr+toStr))
 
 		switch m.whiteTurn {
 		case true:
@@ -535,273 +546,145 @@ func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		if !haveSufficientMaterial(m.board) {
-			message := "Draw due to insufficient material! Game over."
-			m.input.Blur()
-			return m, func() tea.Msg {
-				return overMsg{
-					winner:  nil,
-					loser:   nil,
-					draw:    true,
-					message: message,
-				}
-			}
+		if over := finishMove(m); over != nil {
+			return m, over
 		}
+	case overMsg:
+		m.gameOver = true
+		m.gameOverMsg = msg.message
+		m.recordGame(msg)
+		m.deleteSavedGame()
+	}
+	return m, cmd
+}
 
-		switchTurn(m)
-
-		if m.check != "" {
-			color := "white"
-			if !m.whiteTurn {
-				color = "black"
-			}
-			if !hasLegalMove(m.board, color) {
-				capitalColor := strings.ToUpper(color[:1]) + color[1:]
-				message := fmt.Sprintf("%s king is in checkmate! Game over.", capitalColor)
-				m.input.Blur()
-				var winner *app.User
-				var loser *app.User
-				if color == "white" {
-					winner = m.ctx.User2
-					loser = m.ctx.User1
-				} else {
-					winner = m.ctx.User1
-					loser = m.ctx.User2
-				}
-				return m, func() tea.Msg {
-					return overMsg{
-						winner:  winner,
-						loser:   loser,
-						draw:    false,
-						message: message,
-					}
-				}
+// finishMove ends the turn after a move, including a promotion, and returns
+// the command ending the game when the move finished it.
+func finishMove(m *boardModel) tea.Cmd {
+	// The turn passes first, so the final position has the right side to
+	// move whatever ends the game.
+	switchTurn(m)
+
+	if !haveSufficientMaterial(m.board) {
+		message := "Draw due to insufficient material! Game over."
+		m.input.Blur()
+		return func() tea.Msg {
+			return overMsg{
+				message:     message,
+				result:      resultDraw,
+				termination: terminationInsufficientMaterial,
 			}
 		}
+	}
 
-		if stalemateCheck(m) {
-			message := "Draw due to stalemate! Game over."
+	if m.check != "" {
+		color := "white"
+		if !m.whiteTurn {
+			color = "black"
+		}
+		if !hasLegalMove(m.board, color) {
+			capitalColor := strings.ToUpper(color[:1]) + color[1:]
+			message := fmt.Sprintf("%s king is in checkmate! Game over.", capitalColor)
 			m.input.Blur()
-			return m, func() tea.Msg {
-				return overMsg{
-					winner:  nil,
-					loser:   nil,
-					draw:    true,
-					message: message,
-				}
+			result := resultWhiteWins
+			if color == "white" {
+				result = resultBlackWins
 			}
-		}
-
-		draw, _ := check50MoveFule(m.board.staleTurns)
-		if draw {
-			message := "Draw due to fifty-move rule! Game over."
-			return m, func() tea.Msg {
+			return func() tea.Msg {
 				return overMsg{
-					winner:  nil,
-					loser:   nil,
-					draw:    true,
-					message: message,
+					message:     message,
+					result:      result,
+					termination: terminationCheckmate,
 				}
 			}
 		}
+	}
 
-		resetInputField(m)
-	case overMsg:
-		now := sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true}
-		if msg.winner != nil {
-			winnerRecord, err := m.ctx.Queries.GetRecordsByUserID(context.Background(), msg.winner.ID)
-			switch err {
-			case nil:
-				winnerRecord.Wins.Int64++
-				updatedWinner := database.UpdateRecordParams{
-					UserID:    msg.winner.ID,
-					Wins:      winnerRecord.Wins,
-					Losses:    winnerRecord.Losses,
-					Draws:     winnerRecord.Draws,
-					UpdatedAt: now,
-				}
-				_, err = m.ctx.Queries.UpdateRecord(context.Background(), updatedWinner)
-				if err != nil {
-					return m, nil
-				}
-			case sql.ErrNoRows:
-				id, err := uuid.NewUUID()
-				if err != nil {
-					return m, nil
-				}
-				winnerRecord := database.RegisterRecordParams{
-					ID:        id.String(),
-					UserID:    msg.winner.ID,
-					Wins:      sql.NullInt64{Int64: 1, Valid: true},
-					Losses:    sql.NullInt64{Int64: 0, Valid: true},
-					Draws:     sql.NullInt64{Int64: 0, Valid: true},
-					CreatedAt: now,
-					UpdatedAt: now,
-				}
-				_, err = m.ctx.Queries.RegisterRecord(context.Background(), winnerRecord)
-				if err != nil {
-					return m, nil
-				}
-			default:
-				return m, nil
-			}
-		}
-		if msg.loser != nil {
-			loserRecord, err := m.ctx.Queries.GetRecordsByUserID(context.Background(), msg.loser.ID)
-			switch err {
-			case nil:
-				loserRecord.Losses.Int64++
-				updatedLoser := database.UpdateRecordParams{
-					UserID:    msg.loser.ID,
-					Wins:      loserRecord.Wins,
-					Losses:    loserRecord.Losses,
-					Draws:     loserRecord.Draws,
-					UpdatedAt: now,
-				}
-				_, err = m.ctx.Queries.UpdateRecord(context.Background(), updatedLoser)
-				if err != nil {
-					return m, nil
-				}
-			case sql.ErrNoRows:
-				id, err := uuid.NewUUID()
-				if err != nil {
-					return m, nil
-				}
-				loserRecord := database.RegisterRecordParams{
-					ID:        id.String(),
-					UserID:    msg.loser.ID,
-					Wins:      sql.NullInt64{Int64: 0, Valid: true},
-					Losses:    sql.NullInt64{Int64: 1, Valid: true},
-					Draws:     sql.NullInt64{Int64: 0, Valid: true},
-					CreatedAt: now,
-					UpdatedAt: now,
-				}
-				_, err = m.ctx.Queries.RegisterRecord(context.Background(), loserRecord)
-				if err != nil {
-					return m, nil
-				}
-			default:
-				return m, nil
+	if stalemateCheck(m) {
+		message := "Draw due to stalemate! Game over."
+		m.input.Blur()
+		return func() tea.Msg {
+			return overMsg{
+				message:     message,
+				result:      resultDraw,
+				termination: terminationStalemate,
 			}
 		}
-		if msg.draw {
-			if m.ctx.User1 != nil {
-				user1Record, err := m.ctx.Queries.GetRecordsByUserID(context.Background(), m.ctx.User1.ID)
-				switch err {
-				case nil:
-					user1Record.Draws.Int64++
-					updatedUser1 := database.UpdateRecordParams{
-						UserID:    m.ctx.User1.ID,
-						Wins:      user1Record.Wins,
-						Losses:    user1Record.Losses,
-						Draws:     user1Record.Draws,
-						UpdatedAt: now,
-					}
-					_, err = m.ctx.Queries.UpdateRecord(context.Background(), updatedUser1)
-					if err != nil {
-						return m, nil
-					}
-				case sql.ErrNoRows:
-					id, err := uuid.NewUUID()
-					if err != nil {
-						return m, nil
-					}
-					user1Record := database.RegisterRecordParams{
-						ID:        id.String(),
-						UserID:    m.ctx.User1.ID,
-						Wins:      sql.NullInt64{Int64: 0, Valid: true},
-						Losses:    sql.NullInt64{Int64: 0, Valid: true},
-						Draws:     sql.NullInt64{Int64: 1, Valid: true},
-						CreatedAt: now,
-						UpdatedAt: now,
-					}
-					_, err = m.ctx.Queries.RegisterRecord(context.Background(), user1Record)
-					if err != nil {
-						return m, nil
-					}
-				default:
-					return m, nil
-				}
-			}
-			if m.ctx.User2 != nil {
-				user2Record, err := m.ctx.Queries.GetRecordsByUserID(context.Background(), m.ctx.User2.ID)
-				switch err {
-				case nil:
-					user2Record.Draws.Int64++
-					updatedUser2 := database.UpdateRecordParams{
-						UserID:    m.ctx.User2.ID,
-						Wins:      user2Record.Wins,
-						Losses:    user2Record.Losses,
-						Draws:     user2Record.Draws,
-						UpdatedAt: now,
-					}
-					_, err = m.ctx.Queries.UpdateRecord(context.Background(), updatedUser2)
-					if err != nil {
-						return m, nil
-					}
-				case sql.ErrNoRows:
-					id, err := uuid.NewUUID()
-					if err != nil {
-						return m, nil
-					}
-					user2Record := database.RegisterRecordParams{
-						ID:        id.String(),
-						UserID:    m.ctx.User2.ID,
-						Wins:      sql.NullInt64{Int64: 0, Valid: true},
-						Losses:    sql.NullInt64{Int64: 0, Valid: true},
-						Draws:     sql.NullInt64{Int64: 1, Valid: true},
-						CreatedAt: now,
-						UpdatedAt: now,
-					}
-					_, err = m.ctx.Queries.RegisterRecord(context.Background(), user2Record)
-					if err != nil {
-						return m, nil
-					}
-				default:
-					return m, nil
-				}
+	}
+
+	draw, _ := check50MoveFule(m.board.staleTurns)
+	if draw {
+		message := "Draw due to fifty-move rule! Game over."
+		return func() tea.Msg {
+			return overMsg{
+				message:     message,
+				result:      resultDraw,
+				termination: terminationFiftyMove,
 			}
 		}
-		m.gameOver = true
-		m.gameOverMsg = msg.message
 	}
-	return m, cmd
+
+	resetInputField(m)
+	m.saveGame()
+	return nil
 }
 
 func clearEnPassant(m *boardModel) {
-	if m.board.enPassantTarget != nil {
-		targetColor, err := m.board.enPassantTarget.piece.colorString()
-		if m.whiteTurn {
-			if err != nil || targetColor == "white" {
-				m.board.enPassantTarget = nil
-			}
-		} else {
-			if err != nil || targetColor == "black" {
-				m.board.enPassantTarget = nil
-			}
-		}
+	m.board.clearEnPassant(m.whiteTurn)
+}
+
+func (b *board) clearEnPassant(whiteTurn bool) {
+	if b.enPassantCapture(whiteTurn) == nil {
+		b.enPassantTarget = nil
+	}
+}
+
+// enPassantCapture returns the square of the pawn the side to move can
+// capture en passant: an opponent's pawn that has just moved two squares and
+// is still where it landed, not a piece that captured it there.
+func (b *board) enPassantCapture(whiteTurn bool) *position {
+	if b.enPassantTarget == nil {
+		return nil
+	}
+
+	color, rank := "white", 3
+	if whiteTurn {
+		color, rank = "black", 4
+	}
+	p, ok := b.enPassantTarget.piece.(*pawn)
+	if !ok || p.color != color || b.enPassantTarget.rank != rank {
+		return nil
+	}
+	return b.enPassantTarget
+}
+
+// promptName returns the name shown in the input prompt for the player of
+// the given color.
+func promptName(ctx *app.Context, white bool) string {
+	user, guest := ctx.White(), "Player 1"
+	if !white {
+		user, guest = ctx.Black(), "Player 2"
+	}
+
+	switch {
+	case user != nil:
+		return user.Username
+	case ctx.Pairing != nil:
+		return playerName(ctx, white)
 	}
+	return guest
 }
 
 func resetInputField(m *boardModel) {
 	m.input.SetValue("")
-	name := ""
 	switch {
 	case m.whiteTurn:
-		if m.ctx.User1 != nil {
-			name = m.ctx.User1.Username
-		} else {
-			name = "Player 1"
-		}
+		name := promptName(m.ctx, true)
 		color := "white"
 		m.input.Prompt = fmt.Sprintf("%s's(%s) turn: ", name, color)
 		m.input.Placeholder = "Enter command (e.g. A2 A3)"
 	case !m.whiteTurn:
-		if m.ctx.User2 != nil {
-			name = m.ctx.User2.Username
-		} else {
-			name = "Player 2"
-		}
+		name := promptName(m.ctx, false)
 		color := "black"
 		m.input.Prompt = fmt.Sprintf("%s's(%s) turn: ", name, color)
 		m.input.Placeholder = "Enter command (e.g. A2 A3)"
@@ -844,26 +727,8 @@ func hasLegalMove(board *board, color string) bool {
 
 				for toRank := 0; toRank < 8; toRank++ {
 					for toFile := 0; toFile < 8; toFile++ {
-						toSquare := board.spots[toRank][toFile]
-						if square.piece.validMove(square, toSquare, board) {
-							movingPiece := square.piece
-							capturedPiece := toSquare.piece
-							toSquare.piece = movingPiece
-							square.piece = nil
-							var kingPosition *position
-							if _, isKing := movingPiece.(*king); isKing {
-								kingPosition = toSquare
-							} else if color == "white" {
-								kingPosition = board.whiteKingPosition
-							} else {
-								kingPosition = board.blackKingPosition
-							}
-							underAttack := isUnderAttack(kingPosition, color, board)
-							square.piece = movingPiece
-							toSquare.piece = capturedPiece
-							if !underAttack {
-								return true
-							}
+						if isLegalMove(board, square, board.spots[toRank][toFile], color) {
+							return true
 						}
 					}
 				}
@@ -874,6 +739,30 @@ func hasLegalMove(board *board, color string) bool {
 	return false
 }
 
+func isLegalMove(board *board, square, toSquare *position, color string) bool {
+	if square.piece == nil || !square.piece.validMove(square, toSquare, board) {
+		return false
+	}
+
+	movingPiece := square.piece
+	capturedPiece := toSquare.piece
+	toSquare.piece = movingPiece
+	square.piece = nil
+	var kingPosition *position
+	if _, isKing := movingPiece.(*king); isKing {
+		kingPosition = toSquare
+	} else if color == "white" {
+		kingPosition = board.whiteKingPosition
+	} else {
+		kingPosition = board.blackKingPosition
+	}
+	underAttack := isUnderAttack(kingPosition, color, board)
+	square.piece = movingPiece
+	toSquare.piece = capturedPiece
+
+	return !underAttack
+}
+
 func stalemateCheck(m *boardModel) bool {
 	if m.check == "" {
 		color := "white"
@@ -1,6 +1,7 @@
 package board
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -265,6 +266,31 @@ func TestIsUnderAttack(t *testing.T) {
 	})
 }
 
+func TestEnPassantAfterCapture(t *testing.T) {
+	b := initializeBoard()
+	whiteTurn := true
+	for _, move := range []string{"e2e4", "c7c5", "g1f3", "d7d5", "e4d5"} {
+		err := applyMove(b, whiteTurn, move)
+		if err != nil {
+			t.Fatalf("applyMove(%s) failed: %v", move, err)
+		}
+		whiteTurn = !whiteTurn
+	}
+
+	// The white pawn that captured on d5 stands where Black's pawn landed
+	// after its double push, but cannot be taken en passant.
+	fen, err := b.toFEN(whiteTurn, 3)
+	if err != nil {
+		t.Fatalf("toFEN failed: %v", err)
+	}
+	if fields := strings.Fields(fen); fields[3] != "-" {
+		t.Errorf("Expected no en passant square, got %q", fen)
+	}
+	if applyMove(b, whiteTurn, "c5d4") == nil {
+		t.Error("Expected the capture of d5 en passant to fail")
+	}
+}
+
 func TestStalemate(t *testing.T) {
 	model := boardModel{}
 
@@ -544,8 +570,8 @@ func TestDrawOffer(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected overMsg, got %T", msgOut)
 	}
-	if !over.draw {
-		t.Error("Expected draw flag to be set to true")
+	if over.result != resultDraw {
+		t.Errorf("Expected draw result, got %q", over.result)
 	}
 	if over.message != "Game ended in a draw by agreement." {
 		t.Errorf("Unexpected game over message: %s", over.message)
@@ -603,8 +629,8 @@ func TestOverMsgCheckmate(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected overMsg, got %T", msgOut)
 	}
-	if over.draw {
-		t.Error("Expected game to end in a win/loss, not in draw")
+	if over.result != resultWhiteWins {
+		t.Errorf("Expected white to win, got %q", over.result)
 	}
 	if over.message != "Black king is in checkmate! Game over." {
 		t.Errorf("Expected black king to be in checkmate, got: %q", over.message)
@@ -695,8 +721,8 @@ func TestOverMsgStalemate(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected overMsg, got %T", msgOut)
 	}
-	if !over.draw {
-		t.Error("Expected game to end in a draw")
+	if over.result != resultDraw {
+		t.Errorf("Expected game to end in a draw, got %q", over.result)
 	}
 	if over.message != "Draw due to stalemate! Game over." {
 		t.Errorf("Expected stalemate, got: %q", over.message)
@@ -746,12 +772,21 @@ func TestOverMsgInsufficientMaterial(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected overMsg, got %T", msgOut)
 	}
-	if !over.draw {
-		t.Error("Expected game to end in a draw")
+	if over.result != resultDraw {
+		t.Errorf("Expected game to end in a draw, got %q", over.result)
 	}
 	if over.message != "Draw due to insufficient material! Game over." {
 		t.Errorf("Expected insufficient material, got: %q", over.message)
 	}
+
+	// The final position stored with the game has black to move.
+	fen, err := model.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	if fields := strings.Fields(fen); len(fields) < 2 || fields[1] != "b" {
+		t.Errorf("Expected black to move in the final position, got %q", fen)
+	}
 }
 
 func TestOverMsg50MoveRule(t *testing.T) {
@@ -798,8 +833,8 @@ func TestOverMsg50MoveRule(t *testing.T) {
 	if !ok {
 		t.Errorf("Expected overMsg, got %T", msgOut)
 	}
-	if !over.draw {
-		t.Error("Expected game to end in a draw")
+	if over.result != resultDraw {
+		t.Errorf("Expected game to end in a draw, got %q", over.result)
 	}
 	if over.message != "Draw due to fifty-move rule! Game over." {
 		t.Errorf("Expected fifty-move rule draw, got: %q", over.message)
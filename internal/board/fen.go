@@ -0,0 +1,288 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func pieceLetter(p piece) (rune, error) {
+	color, err := p.colorString()
+	if err != nil {
+		return 0, err
+	}
+
+	var letter rune
+	switch p.(type) {
+	case *pawn:
+		letter = 'p'
+	case *knight:
+		letter = 'n'
+	case *bishop:
+		letter = 'b'
+	case *rook:
+		letter = 'r'
+	case *queen:
+		letter = 'q'
+	case *king:
+		letter = 'k'
+	default:
+		return 0, fmt.Errorf("unknown piece type %T", p)
+	}
+
+	if color == "white" {
+		letter = letter - 'a' + 'A'
+	}
+
+	return letter, nil
+}
+
+func pieceFromLetter(letter rune) (piece, error) {
+	color := "black"
+	if letter >= 'A' && letter <= 'Z' {
+		color = "white"
+		letter = letter - 'A' + 'a'
+	}
+
+	switch letter {
+	case 'p':
+		direction := -1
+		if color == "white" {
+			direction = 1
+		}
+		return &pawn{color: color, direction: direction}, nil
+	case 'n':
+		return &knight{color: color}, nil
+	case 'b':
+		return &bishop{color: color}, nil
+	case 'r':
+		return &rook{color: color}, nil
+	case 'q':
+		return &queen{color: color}, nil
+	case 'k':
+		return &king{color: color}, nil
+	default:
+		return nil, fmt.Errorf("unknown piece letter %q", letter)
+	}
+}
+
+func canCastle(b *board, rank, rookFile int) bool {
+	k, ok := b.spots[rank][4].piece.(*king)
+	if !ok || k.hasMoved {
+		return false
+	}
+	r, ok := b.spots[rank][rookFile].piece.(*rook)
+	if !ok || r.hasMoved {
+		return false
+	}
+
+	return r.color == k.color
+}
+
+// toFEN describes the board in Forsyth-Edwards Notation. The en passant
+// square is reported whenever the last move was a double pawn push.
+func (b *board) toFEN(whiteTurn bool, fullMove int) (string, error) {
+	var placement strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			square := b.spots[rank][file]
+			if square.piece == nil {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				placement.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			letter, err := pieceLetter(square.piece)
+			if err != nil {
+				return "", err
+			}
+			placement.WriteRune(letter)
+		}
+		if empty > 0 {
+			placement.WriteString(strconv.Itoa(empty))
+		}
+		if rank > 0 {
+			placement.WriteString("/")
+		}
+	}
+
+	active := "w"
+	if !whiteTurn {
+		active = "b"
+	}
+
+	castling := ""
+	if canCastle(b, 0, 7) {
+		castling += "K"
+	}
+	if canCastle(b, 0, 0) {
+		castling += "Q"
+	}
+	if canCastle(b, 7, 7) {
+		castling += "k"
+	}
+	if canCastle(b, 7, 0) {
+		castling += "q"
+	}
+	if castling == "" {
+		castling = "-"
+	}
+
+	enPassant := "-"
+	if target := b.enPassantCapture(whiteTurn); target != nil {
+		skipped := position{
+			rank: target.rank - 1,
+			file: target.file,
+		}
+		if whiteTurn {
+			skipped.rank = target.rank + 1
+		}
+		var err error
+		enPassant, err = skipped.string()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s %s %d %d", placement.String(), active, castling, enPassant, b.staleTurns, fullMove), nil
+}
+
+// boardFromFEN rebuilds a board from Forsyth-Edwards Notation and reports
+// whose turn it is and the full move number.
+func boardFromFEN(fen string) (*board, bool, int, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 6 {
+		return nil, false, 0, fmt.Errorf("FEN must contain 6 fields, got %d", len(fields))
+	}
+
+	b := board{}
+	for i := range b.spots {
+		for j := range b.spots[i] {
+			b.spots[i][j] = &position{
+				rank:  i,
+				file:  j,
+				piece: nil,
+			}
+		}
+	}
+
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != 8 {
+		return nil, false, 0, fmt.Errorf("FEN piece placement must contain 8 ranks, got %d", len(rows))
+	}
+	for i, row := range rows {
+		rank := 7 - i
+		file := 0
+		for _, c := range row {
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			if file > 7 {
+				return nil, false, 0, fmt.Errorf("FEN rank %d is too long", rank+1)
+			}
+			p, err := pieceFromLetter(c)
+			if err != nil {
+				return nil, false, 0, err
+			}
+			b.spots[rank][file].piece = p
+			switch p := p.(type) {
+			case *king:
+				p.hasMoved = true
+				if p.color == "white" {
+					b.whiteKingPosition = b.spots[rank][file]
+				} else {
+					b.blackKingPosition = b.spots[rank][file]
+				}
+			case *rook:
+				p.hasMoved = true
+			case *pawn:
+				p.hasMoved = (p.color == "white" && rank != 1) || (p.color == "black" && rank != 6)
+			}
+			file++
+		}
+		if file != 8 {
+			return nil, false, 0, fmt.Errorf("FEN rank %d must describe 8 files", rank+1)
+		}
+	}
+	if b.whiteKingPosition == nil || b.blackKingPosition == nil {
+		return nil, false, 0, fmt.Errorf("FEN must contain both kings")
+	}
+
+	var whiteTurn bool
+	switch fields[1] {
+	case "w":
+		whiteTurn = true
+	case "b":
+		whiteTurn = false
+	default:
+		return nil, false, 0, fmt.Errorf("invalid FEN active color %q", fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			rank, rookFile := 0, 7
+			switch c {
+			case 'K':
+			case 'Q':
+				rookFile = 0
+			case 'k':
+				rank = 7
+			case 'q':
+				rank, rookFile = 7, 0
+			default:
+				return nil, false, 0, fmt.Errorf("invalid FEN castling rights %q", fields[2])
+			}
+			k, kingOk := b.spots[rank][4].piece.(*king)
+			r, rookOk := b.spots[rank][rookFile].piece.(*rook)
+			if !kingOk || !rookOk || k.color != r.color {
+				return nil, false, 0, fmt.Errorf("FEN castling rights %q do not match piece placement", fields[2])
+			}
+			k.hasMoved = false
+			r.hasMoved = false
+		}
+	}
+
+	if fields[3] != "-" {
+		m := &boardModel{}
+		skipped, err := positionFromString(fields[3], m)
+		if err != nil {
+			return nil, false, 0, fmt.Errorf("invalid FEN en passant square %q", fields[3])
+		}
+		pawnRank := skipped.rank - 1
+		if !whiteTurn {
+			pawnRank = skipped.rank + 1
+		}
+		if pawnRank < 0 || pawnRank > 7 {
+			return nil, false, 0, fmt.Errorf("invalid FEN en passant square %q", fields[3])
+		}
+		target := b.spots[pawnRank][skipped.file]
+		if _, ok := target.piece.(*pawn); !ok {
+			return nil, false, 0, fmt.Errorf("FEN en passant square %q does not follow a pawn move", fields[3])
+		}
+		b.enPassantTarget = target
+	}
+
+	staleTurns, err := strconv.Atoi(fields[4])
+	if err != nil || staleTurns < 0 {
+		return nil, false, 0, fmt.Errorf("invalid FEN halfmove clock %q", fields[4])
+	}
+	b.staleTurns = staleTurns
+
+	fullMove, err := strconv.Atoi(fields[5])
+	if err != nil || fullMove < 1 {
+		return nil, false, 0, fmt.Errorf("invalid FEN fullmove number %q", fields[5])
+	}
+
+	return &b, whiteTurn, fullMove, nil
+}
+
+// ValidateFEN reports whether fen describes a position this build can
+// load.
+func ValidateFEN(fen string) error {
+	_, _, _, err := boardFromFEN(fen)
+	return err
+}
@@ -0,0 +1,111 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/deskdaniel/GoMate/internal/app"
+)
+
+func TestInitialBoardFEN(t *testing.T) {
+	b := initializeBoard()
+	fen, err := b.toFEN(true, 1)
+	if err != nil {
+		t.Fatalf("toFEN failed: %v", err)
+	}
+
+	expected := "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	if fen != expected {
+		t.Errorf("Expected %q, got %q", expected, fen)
+	}
+}
+
+func TestFENRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"initial position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"en passant available", "rnbqkbnr/ppp1pppp/8/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3"},
+		{"partial castling rights", "r3k2r/8/8/8/8/8/8/R3K2R b Kq - 12 30"},
+		{"no castling rights", "8/8/4k3/8/8/4K3/8/8 w - - 57 80"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, whiteTurn, fullMove, err := boardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("boardFromFEN(%q) failed: %v", test.fen, err)
+			}
+			fen, err := b.toFEN(whiteTurn, fullMove)
+			if err != nil {
+				t.Fatalf("toFEN failed: %v", err)
+			}
+			if fen != test.fen {
+				t.Errorf("Expected %q, got %q", test.fen, fen)
+			}
+		})
+	}
+}
+
+func TestInvalidFEN(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"empty", ""},
+		{"missing fields", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -"},
+		{"missing king", "rnbq1bnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQ - 0 1"},
+		{"short rank", "rnbqkbnr/ppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"unknown piece", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1"},
+		{"castling without rook", "rnbqkbn1/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"invalid active color", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, _, err := boardFromFEN(test.fen)
+			if err == nil {
+				t.Errorf("Expected boardFromFEN(%q) to fail", test.fen)
+			}
+		})
+	}
+}
+
+func TestFENAfterMoves(t *testing.T) {
+	ctx := app.Context{}
+	model := &boardModel{
+		board:     initializeBoard(),
+		whiteTurn: true,
+		input:     textinput.New(),
+		ctx:       &ctx,
+	}
+
+	for _, input := range []string{"e2 e4", "c7 c5", "e4 e5", "d7 d5"} {
+		model.Update(gameMsg{input: input})
+	}
+
+	fen, err := model.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	expected := "rnbqkbnr/pp2pppp/8/2ppP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3"
+	if fen != expected {
+		t.Errorf("Expected %q, got %q", expected, fen)
+	}
+
+	if len(model.moves) != 4 || model.moves[0] != "e2e4" || model.moves[3] != "d7d5" {
+		t.Errorf("Unexpected move list: %v", model.moves)
+	}
+
+	model.Update(gameMsg{input: "e5 d6"})
+	model.Update(gameMsg{input: "e8 d7"})
+	fen, err = model.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	expected = "rnbq1bnr/pp1kpppp/3P4/2p5/8/8/PPPP1PPP/RNBQKBNR w KQ - 1 4"
+	if fen != expected {
+		t.Errorf("Expected %q, got %q", expected, fen)
+	}
+}
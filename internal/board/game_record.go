@@ -0,0 +1,84 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/google/uuid"
+)
+
+const noTimeControl = "-"
+
+func playerName(ctx *app.Context, white bool) string {
+	if ctx.Pairing != nil {
+		if white {
+			return ctx.Pairing.WhiteName
+		}
+		return ctx.Pairing.BlackName
+	}
+
+	user, slot := ctx.User1, 1
+	if !white {
+		user, slot = ctx.User2, 2
+	}
+	if user != nil {
+		return user.Username
+	}
+	return fmt.Sprintf("Guest %d", slot)
+}
+
+func (m *boardModel) gameParams(msg overMsg) (database.CreateGameParams, error) {
+	id := m.gameID
+	if id == "" {
+		newID, err := uuid.NewUUID()
+		if err != nil {
+			return database.CreateGameParams{}, fmt.Errorf("failed to generate game ID: %w", err)
+		}
+		id = newID.String()
+	}
+
+	san, err := sanMoves(m.moves)
+	if err != nil {
+		return database.CreateGameParams{}, fmt.Errorf("failed to convert moves to SAN: %w", err)
+	}
+
+	fen, err := m.fen()
+	if err != nil {
+		return database.CreateGameParams{}, fmt.Errorf("failed to describe final position: %w", err)
+	}
+
+	return database.CreateGameParams{
+		ID:          id,
+		WhiteUserID: nullUserID(m.ctx.White()),
+		BlackUserID: nullUserID(m.ctx.Black()),
+		WhiteName:   playerName(m.ctx, true),
+		BlackName:   playerName(m.ctx, false),
+		Result:      msg.result,
+		Termination: msg.termination,
+		StartedAt:   m.startedAt.Format(time.RFC3339),
+		EndedAt:     time.Now().Format(time.RFC3339),
+		Moves:       strings.Join(san, " "),
+		FinalFen:    fen,
+		TimeControl: noTimeControl,
+	}, nil
+}
+
+// recordGame stores the finished game and the players' results. A failure
+// is reported on the game over screen.
+func (m *boardModel) recordGame(msg overMsg) {
+	if m.ctx == nil || m.ctx.Store == nil {
+		return
+	}
+
+	params, err := m.gameParams(msg)
+	if err == nil {
+		err = results.Record(m.ctx, params)
+	}
+	if err != nil {
+		m.gameOverMsg += fmt.Sprintf("\n\nError: the result could not be recorded: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package board
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	eventStart = "start"
+	eventMove  = "move"
+	eventError = "error"
+	eventOver  = "over"
+)
+
+// HeadlessEvent is a line written by RunHeadless.
+type HeadlessEvent struct {
+	Type        string `json:"type"`
+	Input       string `json:"input,omitempty"`
+	SAN         string `json:"san,omitempty"`
+	FEN         string `json:"fen,omitempty"`
+	Turn        string `json:"turn,omitempty"`
+	Check       bool   `json:"check,omitempty"`
+	Result      string `json:"result,omitempty"`
+	Termination string `json:"termination,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func (e HeadlessEvent) text() string {
+	switch e.Type {
+	case eventStart:
+		return fmt.Sprintf("start %s", e.FEN)
+	case eventMove:
+		return fmt.Sprintf("move %s %s", e.SAN, e.FEN)
+	case eventOver:
+		return fmt.Sprintf("over %s %s", e.Result, e.Termination)
+	}
+	return fmt.Sprintf("error %s: %s", e.Input, e.Error)
+}
+
+// headlessGame is the state of a game played by RunHeadless. The position
+// is kept as FEN, so a rejected move cannot leave it half changed.
+type headlessGame struct {
+	fen         string
+	result      string
+	termination string
+}
+
+func turnName(whiteTurn bool) string {
+	if whiteTurn {
+		return "white"
+	}
+	return "black"
+}
+
+// gameStatus returns the result and termination of a finished game, checked
+// in the same order as on the game screen, or empty strings while the side
+// to move can play on.
+func gameStatus(b *board, whiteTurn bool) (string, string) {
+	color := turnName(whiteTurn)
+	kingPosition := b.whiteKingPosition
+	if !whiteTurn {
+		kingPosition = b.blackKingPosition
+	}
+
+	switch {
+	case !haveSufficientMaterial(b):
+		return resultDraw, terminationInsufficientMaterial
+	case !hasLegalMove(b, color) && isUnderAttack(kingPosition, color, b):
+		if whiteTurn {
+			return resultBlackWins, terminationCheckmate
+		}
+		return resultWhiteWins, terminationCheckmate
+	case !hasLegalMove(b, color):
+		return resultDraw, terminationStalemate
+	}
+	if draw, _ := check50MoveFule(b.staleTurns); draw {
+		return resultDraw, terminationFiftyMove
+	}
+	return "", ""
+}
+
+func newHeadlessGame(fen string) (*headlessGame, HeadlessEvent, error) {
+	if fen == "" {
+		var err error
+		fen, err = initializeBoard().toFEN(true, 1)
+		if err != nil {
+			return nil, HeadlessEvent{}, err
+		}
+	}
+
+	b, whiteTurn, _, err := boardFromFEN(fen)
+	if err != nil {
+		return nil, HeadlessEvent{}, err
+	}
+
+	g := &headlessGame{fen: fen}
+	g.result, g.termination = gameStatus(b, whiteTurn)
+	return g, HeadlessEvent{Type: eventStart, FEN: fen, Turn: turnName(whiteTurn)}, nil
+}
+
+// play plays a move given in coordinate notation or SAN and returns the
+// events it causes.
+func (g *headlessGame) play(input string) []HeadlessEvent {
+	if g.result != "" {
+		return []HeadlessEvent{{Type: eventError, Input: input, Error: "the game is over"}}
+	}
+
+	b, whiteTurn, fullMove, err := boardFromFEN(g.fen)
+	if err != nil {
+		return []HeadlessEvent{{Type: eventError, Input: input, Error: err.Error()}}
+	}
+	move, err := parseMove(b, whiteTurn, input)
+	if err != nil {
+		return []HeadlessEvent{{Type: eventError, Input: input, Error: err.Error()}}
+	}
+	san, err := applyMoveSAN(b, whiteTurn, move)
+	if err != nil {
+		return []HeadlessEvent{{Type: eventError, Input: input, Error: err.Error()}}
+	}
+
+	if !whiteTurn {
+		fullMove++
+	}
+	whiteTurn = !whiteTurn
+	fen, err := b.toFEN(whiteTurn, fullMove)
+	if err != nil {
+		return []HeadlessEvent{{Type: eventError, Input: input, Error: err.Error()}}
+	}
+	g.fen = fen
+
+	events := []HeadlessEvent{{
+		Type:  eventMove,
+		Input: input,
+		SAN:   san,
+		FEN:   fen,
+		Turn:  turnName(whiteTurn),
+		Check: strings.HasSuffix(san, "+") || strings.HasSuffix(san, "#"),
+	}}
+	g.result, g.termination = gameStatus(b, whiteTurn)
+	if g.result != "" {
+		events = append(events, HeadlessEvent{Type: eventOver, Result: g.result, Termination: g.termination})
+	}
+	return events
+}
+
+// RunHeadless plays a game without the terminal UI. It reads one move per
+// line from in, in coordinate notation (e.g. e2e4, e7e8q) or SAN, and
+// writes the resulting position, the end of the game and rejected moves to
+// out, as text or as one JSON object per line. Blank lines and lines
+// starting with # are skipped. The game starts from fen, or from the
+// initial position when fen is empty.
+func RunHeadless(in io.Reader, out io.Writer, fen string, asJSON bool) error {
+	write := func(event HeadlessEvent) error {
+		if asJSON {
+			return json.NewEncoder(out).Encode(event)
+		}
+		_, err := fmt.Fprintln(out, event.text())
+		return err
+	}
+
+	g, start, err := newHeadlessGame(fen)
+	if err != nil {
+		return err
+	}
+	err = write(start)
+	if err != nil {
+		return err
+	}
+	if g.result != "" {
+		err = write(HeadlessEvent{Type: eventOver, Result: g.result, Termination: g.termination})
+		if err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, event := range g.play(line) {
+			err = write(event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read moves: %w", err)
+	}
+
+	return nil
+}
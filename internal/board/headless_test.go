@@ -0,0 +1,136 @@
+package board
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunHeadless(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		input    string
+		expected []string
+	}{
+		{
+			"fool's mate",
+			"",
+			"f3\n# black replies\ne7e5\n\ng2 g4\nQh4#\na2a3\n",
+			[]string{
+				"start rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+				"move f3 rnbqkbnr/pppppppp/8/8/8/5P2/PPPPP1PP/RNBQKBNR b KQkq - 0 1",
+				"move e5 rnbqkbnr/pppp1ppp/8/4p3/8/5P2/PPPPP1PP/RNBQKBNR w KQkq e6 0 2",
+				"move g4 rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq g3 0 2",
+				"move Qh4# rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3",
+				"over 0-1 checkmate",
+				"error a2a3: the game is over",
+			},
+		},
+		{
+			"rejected moves keep the position",
+			"",
+			"e4\nc5\nNf3\nd5\nKe3\ne4e6\nexd5\nc5d4\nQxd5\n",
+			[]string{
+				"start rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+				"move e4 rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+				"move c5 rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+				"move Nf3 rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+				"move d5 rnbqkbnr/pp2pppp/8/2pp4/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq d6 0 3",
+				`error Ke3: illegal move "Ke3"`,
+				"error e4e6: invalid move for pawn",
+				// The pawn that captured on d5 cannot be taken en passant.
+				"move exd5 rnbqkbnr/pp2pppp/8/2pP4/8/5N2/PPPP1PPP/RNBQKB1R b KQkq - 0 3",
+				"error c5d4: invalid move for pawn",
+				"move Qxd5 rnb1kbnr/pp2pppp/8/2pq4/8/5N2/PPPP1PPP/RNBQKB1R w KQkq - 0 4",
+			},
+		},
+		{
+			"stalemate from a position",
+			"7k/8/6Q1/8/8/8/8/K7 w - - 0 1",
+			"Qf7\n",
+			[]string{
+				"start 7k/8/6Q1/8/8/8/8/K7 w - - 0 1",
+				"move Qf7 7k/5Q2/8/8/8/8/8/K7 b - - 1 1",
+				"over 1/2-1/2 stalemate",
+			},
+		},
+		{
+			"finished position",
+			"7k/8/8/8/8/8/8/K7 w - - 0 1",
+			"Kb2\n",
+			[]string{
+				"start 7k/8/8/8/8/8/8/K7 w - - 0 1",
+				"over 1/2-1/2 insufficient material",
+				"error Kb2: the game is over",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := RunHeadless(strings.NewReader(test.input), &out, test.fen, false)
+			if err != nil {
+				t.Fatalf("RunHeadless failed: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if len(lines) != len(test.expected) {
+				t.Fatalf("Expected %d lines, got:\n%s", len(test.expected), out.String())
+			}
+			for i := range lines {
+				if lines[i] != test.expected[i] {
+					t.Errorf("Line %d: expected %q, got %q", i+1, test.expected[i], lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunHeadlessJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := RunHeadless(strings.NewReader("e2e4\ne5\nQh5\nNc6\nBc4\nNf6\nQxf7#\n"), &out, "", true)
+	if err != nil {
+		t.Fatalf("RunHeadless failed: %v", err)
+	}
+
+	var events []HeadlessEvent
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var event HeadlessEvent
+		err = decoder.Decode(&event)
+		if err != nil {
+			t.Fatalf("Expected JSON lines, got %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 9 {
+		t.Fatalf("Expected 9 events, got %+v", events)
+	}
+	if events[1] != (HeadlessEvent{
+		Type:  "move",
+		Input: "e2e4",
+		SAN:   "e4",
+		FEN:   "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		Turn:  "black",
+	}) {
+		t.Errorf("Unexpected first move %+v", events[1])
+	}
+	if last := events[7]; last.SAN != "Qxf7#" || !last.Check || last.Turn != "black" {
+		t.Errorf("Unexpected mating move %+v", last)
+	}
+	if events[8] != (HeadlessEvent{Type: "over", Result: "1-0", Termination: "checkmate"}) {
+		t.Errorf("Unexpected end %+v", events[8])
+	}
+}
+
+func TestRunHeadlessInvalidFEN(t *testing.T) {
+	var out bytes.Buffer
+	err := RunHeadless(strings.NewReader("e4\n"), &out, "8/8/8/8/8/8/8/8 w - - 0 1", false)
+	if err == nil {
+		t.Errorf("Expected a position without kings to fail, got %q", out.String())
+	}
+}
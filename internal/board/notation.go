@@ -0,0 +1,375 @@
+package board
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func parseCoordinateMove(b *board, move string) (*position, *position, rune, error) {
+	move = strings.ToLower(strings.ReplaceAll(move, " ", ""))
+	if len(move) != 4 && len(move) != 5 {
+		return nil, nil, 0, fmt.Errorf("incorrect move %q, expected format like e2e4 or e7e8q", move)
+	}
+
+	m := &boardModel{}
+	from, err := positionFromString(move[0:2], m)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("incorrect move %q: %w", move, err)
+	}
+	to, err := positionFromString(move[2:4], m)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("incorrect move %q: %w", move, err)
+	}
+
+	var promotion rune
+	if len(move) == 5 {
+		promotion = rune(move[4])
+		if !strings.ContainsRune("qrbn", promotion) {
+			return nil, nil, 0, fmt.Errorf("incorrect promotion piece in move %q", move)
+		}
+	}
+
+	return b.spots[from.rank][from.file], b.spots[to.rank][to.file], promotion, nil
+}
+
+// applyMove plays a move given in coordinate notation (e.g. e2e4, e7e8q)
+// for the side to move, using the same rules as the game screen.
+func applyMove(b *board, whiteTurn bool, move string) error {
+	from, to, promotion, err := parseCoordinateMove(b, move)
+	if err != nil {
+		return err
+	}
+
+	color := "white"
+	if !whiteTurn {
+		color = "black"
+	}
+
+	fromString, err := from.string()
+	if err != nil {
+		return err
+	}
+	if from.piece == nil {
+		return fmt.Errorf("no piece on %s", fromString)
+	}
+	pieceColor, err := from.piece.colorString()
+	if err != nil {
+		return err
+	}
+	if pieceColor != color {
+		return fmt.Errorf("piece on %s does not belong to %s", fromString, color)
+	}
+
+	b.clearEnPassant(whiteTurn)
+
+	err = from.piece.move(from, to, b)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := to.piece.(*pawn); ok && (to.rank == 7 || to.rank == 0) {
+		if promotion == 0 {
+			promotion = 'q'
+		}
+		if color == "white" {
+			promotion = promotion - 'a' + 'A'
+		}
+		newPiece, err := pieceFromLetter(promotion)
+		if err != nil {
+			return err
+		}
+		to.piece = newPiece
+	}
+
+	return nil
+}
+
+// applyMoveSAN plays a coordinate move and returns it in Standard
+// Algebraic Notation.
+func applyMoveSAN(b *board, whiteTurn bool, move string) (string, error) {
+	from, to, promotion, err := parseCoordinateMove(b, move)
+	if err != nil {
+		return "", err
+	}
+	if from.piece == nil {
+		return "", fmt.Errorf("no piece on the starting square of %s", move)
+	}
+
+	color := "white"
+	opponent := "black"
+	if !whiteTurn {
+		color, opponent = "black", "white"
+	}
+
+	san := ""
+	_, isPawn := from.piece.(*pawn)
+	_, isKing := from.piece.(*king)
+	target, err := to.string()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case isKing && abs(to.file-from.file) == 2:
+		if to.file == 6 {
+			san = "O-O"
+		} else {
+			san = "O-O-O"
+		}
+	case isPawn:
+		if to.file != from.file {
+			san = string(rune('a'+from.file)) + "x"
+		}
+		san += target
+		if to.rank == 7 || to.rank == 0 {
+			if promotion == 0 {
+				promotion = 'q'
+			}
+			san += "=" + strings.ToUpper(string(promotion))
+		}
+	default:
+		letter, err := pieceLetter(from.piece)
+		if err != nil {
+			return "", err
+		}
+		san = strings.ToUpper(string(letter))
+
+		sameFile, sameRank, ambiguous := false, false, false
+		for rank := 0; rank < 8; rank++ {
+			for file := 0; file < 8; file++ {
+				other := b.spots[rank][file]
+				if other == from || other.piece == nil {
+					continue
+				}
+				otherLetter, err := pieceLetter(other.piece)
+				if err != nil || otherLetter != letter {
+					continue
+				}
+				if isLegalMove(b, other, to, color) {
+					ambiguous = true
+					if other.file == from.file {
+						sameFile = true
+					}
+					if other.rank == from.rank {
+						sameRank = true
+					}
+				}
+			}
+		}
+		if ambiguous {
+			fromString, err := from.string()
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case !sameFile:
+				san += fromString[0:1]
+			case !sameRank:
+				san += fromString[1:2]
+			default:
+				san += fromString
+			}
+		}
+
+		if to.piece != nil {
+			san += "x"
+		}
+		san += target
+	}
+
+	err = applyMove(b, whiteTurn, move)
+	if err != nil {
+		return "", err
+	}
+
+	kingPosition := b.blackKingPosition
+	if opponent == "white" {
+		kingPosition = b.whiteKingPosition
+	}
+	if isUnderAttack(kingPosition, opponent, b) {
+		if hasLegalMove(b, opponent) {
+			san += "+"
+		} else {
+			san += "#"
+		}
+	}
+
+	return san, nil
+}
+
+// sanMoves converts a game recorded in coordinate notation, starting from
+// the initial position, into Standard Algebraic Notation.
+func sanMoves(moves []string) ([]string, error) {
+	b := initializeBoard()
+	whiteTurn := true
+
+	result := make([]string, 0, len(moves))
+	for i, move := range moves {
+		san, err := applyMoveSAN(b, whiteTurn, move)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%s): %w", i+1, move, err)
+		}
+		result = append(result, san)
+		whiteTurn = !whiteTurn
+	}
+
+	return result, nil
+}
+
+// parseSAN finds the coordinate move for a move given in Standard
+// Algebraic Notation by matching the SAN of every legal candidate.
+func parseSAN(b *board, whiteTurn bool, san string) (string, error) {
+	stripped := strings.TrimRight(san, "+#!?")
+	stripped = strings.ReplaceAll(stripped, "0", "O")
+
+	color := "white"
+	homeRank := 0
+	if !whiteTurn {
+		color = "black"
+		homeRank = 7
+	}
+
+	var targets []*position
+	var promotion string
+	switch stripped {
+	case "O-O":
+		targets = append(targets, b.spots[homeRank][6])
+	case "O-O-O":
+		targets = append(targets, b.spots[homeRank][2])
+	default:
+		if i := strings.Index(stripped, "="); i >= 0 {
+			promotion = strings.ToLower(stripped[i+1:])
+			stripped = stripped[:i] + "=" + strings.ToUpper(promotion)
+			if len(promotion) != 1 {
+				return "", fmt.Errorf("incorrect promotion in move %q", san)
+			}
+		}
+		target := strings.TrimRight(stripped, "=QRBNqrbn")
+		if len(target) < 2 {
+			return "", fmt.Errorf("incorrect move %q", san)
+		}
+		to, err := positionFromString(target[len(target)-2:], &boardModel{})
+		if err != nil {
+			return "", fmt.Errorf("incorrect move %q: %w", san, err)
+		}
+		targets = append(targets, b.spots[to.rank][to.file])
+	}
+
+	fen, err := b.toFEN(whiteTurn, 1)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, to := range targets {
+		for rank := 0; rank < 8; rank++ {
+			for file := 0; file < 8; file++ {
+				from := b.spots[rank][file]
+				if from.piece == nil {
+					continue
+				}
+				pieceColor, err := from.piece.colorString()
+				if err != nil || pieceColor != color || !isLegalMove(b, from, to, color) {
+					continue
+				}
+
+				fromString, err := from.string()
+				if err != nil {
+					return "", err
+				}
+				toString, err := to.string()
+				if err != nil {
+					return "", err
+				}
+				move := fromString + toString + promotion
+
+				candidate, _, _, err := boardFromFEN(fen)
+				if err != nil {
+					return "", err
+				}
+				candidateSAN, err := applyMoveSAN(candidate, whiteTurn, move)
+				if err != nil {
+					continue
+				}
+				if strings.TrimRight(candidateSAN, "+#") == stripped {
+					matches = append(matches, move)
+				}
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("illegal move %q", san)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("ambiguous move %q", san)
+}
+
+// replayFENs replays a game recorded in Standard Algebraic Notation and
+// returns the position before the first move and after every move.
+func replayFENs(moves []string) ([]string, error) {
+	b := initializeBoard()
+	whiteTurn := true
+
+	fen, err := b.toFEN(whiteTurn, 1)
+	if err != nil {
+		return nil, err
+	}
+	fens := []string{fen}
+
+	for i, san := range moves {
+		move, err := parseSAN(b, whiteTurn, san)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%s): %w", i+1, san, err)
+		}
+		err = applyMove(b, whiteTurn, move)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%s): %w", i+1, san, err)
+		}
+		whiteTurn = !whiteTurn
+
+		fen, err = b.toFEN(whiteTurn, (i+1)/2+1)
+		if err != nil {
+			return nil, err
+		}
+		fens = append(fens, fen)
+	}
+
+	return fens, nil
+}
+
+var coordinateMovePattern = regexp.MustCompile(`^[a-h][1-8] ?[a-h][1-8][qrbnQRBN]?$`)
+
+// parseMove finds the coordinate move for a move given either in coordinate
+// notation (e.g. e2e4, e2 e4, e7e8q) or in Standard Algebraic Notation.
+func parseMove(b *board, whiteTurn bool, move string) (string, error) {
+	move = strings.TrimSpace(move)
+	if coordinateMovePattern.MatchString(move) {
+		return strings.ToLower(strings.ReplaceAll(move, " ", "")), nil
+	}
+	return parseSAN(b, whiteTurn, move)
+}
+
+// FEN plays moves given in coordinate notation or SAN from the initial
+// position and returns the resulting position in Forsyth-Edwards Notation.
+func FEN(moves []string) (string, error) {
+	b := initializeBoard()
+	whiteTurn := true
+
+	for i, input := range moves {
+		move, err := parseMove(b, whiteTurn, input)
+		if err != nil {
+			return "", fmt.Errorf("move %d (%s): %w", i+1, input, err)
+		}
+		err = applyMove(b, whiteTurn, move)
+		if err != nil {
+			return "", fmt.Errorf("move %d (%s): %w", i+1, input, err)
+		}
+		whiteTurn = !whiteTurn
+	}
+
+	return b.toFEN(whiteTurn, len(moves)/2+1)
+}
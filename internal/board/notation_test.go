@@ -0,0 +1,245 @@
+package board
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSANMoves(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    string
+		expected string
+	}{
+		{
+			"scholar's mate",
+			"e2e4 e7e5 f1c4 b8c6 d1h5 g8f6 h5f7",
+			"e4 e5 Bc4 Nc6 Qh5 Nf6 Qxf7#",
+		},
+		{
+			"castling both sides",
+			"e2e4 d7d5 g1f3 c8g4 f1e2 d8d6 e1g1 b8c6 d2d3 e8c8",
+			"e4 d5 Nf3 Bg4 Be2 Qd6 O-O Nc6 d3 O-O-O",
+		},
+		{
+			"en passant and check",
+			"e2e4 a7a6 e4e5 d7d5 e5d6 e7d6 d1e2",
+			"e4 a6 e5 d5 exd6 exd6 Qe2+",
+		},
+		{
+			"knight disambiguation",
+			"g1f3 a7a6 b1c3 a6a5 c3e4 a5a4 f3g5 a4a3 e4f6 e7f6 g5e4",
+			"Nf3 a6 Nc3 a5 Ne4 a4 Nfg5 a3 Nf6+ exf6 Ne4",
+		},
+		{
+			"promotion",
+			"a2a4 b7b5 a4b5 a7a6 b5a6 c8b7 a6b7 b8c6 b7a8q",
+			"a4 b5 axb5 a6 bxa6 Bb7 axb7 Nc6 bxa8=Q",
+		},
+		{
+			"illegal move",
+			"e2e4 e7e5 e1e3",
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			san, err := sanMoves(strings.Fields(test.moves))
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("Expected illegal move sequence to fail, got %v", san)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanMoves failed: %v", err)
+			}
+			got := strings.Join(san, " ")
+			if got != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestSANDisambiguation(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		move     string
+		expected string
+	}{
+		{"by file", "7k/8/8/8/8/8/8/R4RK1 w - - 0 1", "a1c1", "Rac1"},
+		{"by rank", "R7/8/7k/8/8/8/8/R6K w - - 0 1", "a1a4", "R1a4"},
+		{"by square", "7k/8/8/8/8/Q1Q5/8/Q6K w - - 0 1", "a3b2", "Qa3b2+"},
+		{"pinned piece is ignored", "4r2k/8/8/8/8/8/4N3/2N1K3 w - - 0 1", "c1d3", "Nd3"},
+		{"underpromotion", "8/1P5k/8/8/8/8/8/7K w - - 0 1", "b7b8n", "b8=N"},
+		{"promotion with check", "8/1P6/8/8/8/8/8/1k5K w - - 0 1", "b7b8q", "b8=Q+"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, whiteTurn, _, err := boardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("boardFromFEN failed: %v", err)
+			}
+			san, err := applyMoveSAN(b, whiteTurn, test.move)
+			if err != nil {
+				t.Fatalf("applyMoveSAN failed: %v", err)
+			}
+			if san != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, san)
+			}
+		})
+	}
+}
+
+func TestApplyMoveRejectsIllegalMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []string
+	}{
+		{"empty square", []string{"e3e4"}},
+		{"wrong color", []string{"e7e5"}},
+		{"invalid notation", []string{"e2"}},
+		{"invalid promotion piece", []string{"e2e4k"}},
+		{"exposes king", []string{"e2e4", "e7e5", "d1h5", "f7f6", "h5e5", "e8f7", "a2a3", "f7g6"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := initializeBoard()
+			whiteTurn := true
+			var err error
+			for _, move := range test.moves {
+				err = applyMove(b, whiteTurn, move)
+				if err != nil {
+					break
+				}
+				whiteTurn = !whiteTurn
+			}
+			if err == nil {
+				t.Errorf("Expected move sequence %v to fail", test.moves)
+			}
+		})
+	}
+}
+
+func TestReplayFENs(t *testing.T) {
+	games := []string{
+		"e2e4 e7e5 f1c4 b8c6 d1h5 g8f6 h5f7",
+		"e2e4 d7d5 g1f3 c8g4 f1e2 d8d6 e1g1 b8c6 d2d3 e8c8",
+		"e2e4 a7a6 e4e5 d7d5 e5d6 e7d6 d1e2",
+		"g1f3 a7a6 b1c3 a6a5 c3e4 a5a4 f3g5 a4a3 e4f6 e7f6 g5e4",
+		"a2a4 b7b5 a4b5 a7a6 b5a6 c8b7 a6b7 b8c6 b7a8n",
+	}
+
+	for _, game := range games {
+		t.Run(game, func(t *testing.T) {
+			moves := strings.Fields(game)
+			san, err := sanMoves(moves)
+			if err != nil {
+				t.Fatalf("sanMoves failed: %v", err)
+			}
+
+			fens, err := replayFENs(san)
+			if err != nil {
+				t.Fatalf("replayFENs failed: %v", err)
+			}
+			if len(fens) != len(moves)+1 {
+				t.Fatalf("Expected %d positions, got %d", len(moves)+1, len(fens))
+			}
+
+			b := initializeBoard()
+			whiteTurn := true
+			for i, move := range moves {
+				err = applyMove(b, whiteTurn, move)
+				if err != nil {
+					t.Fatalf("applyMove failed: %v", err)
+				}
+				whiteTurn = !whiteTurn
+				expected, err := b.toFEN(whiteTurn, (i+1)/2+1)
+				if err != nil {
+					t.Fatalf("toFEN failed: %v", err)
+				}
+				if fens[i+1] != expected {
+					t.Errorf("After %s expected %q, got %q", move, expected, fens[i+1])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSAN(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		san      string
+		expected string
+	}{
+		{"pawn push", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e4", "e2e4"},
+		{"castling with zeros", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "0-0-0", "e8c8"},
+		{"disambiguated rook", "7k/8/8/8/8/8/8/R4RK1 w - - 0 1", "Rac1", "a1c1"},
+		{"underpromotion", "8/1P5k/8/8/8/8/8/7K w - - 0 1", "b8=N", "b7b8n"},
+		{"check suffix is optional", "7k/8/8/8/8/Q1Q5/8/Q6K w - - 0 1", "Qa3b2", "a3b2"},
+		{"ambiguous", "7k/8/8/8/8/8/8/R4RK1 w - - 0 1", "Rc1", ""},
+		{"illegal", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "e5", ""},
+		{"garbage", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", "x", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, whiteTurn, _, err := boardFromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("boardFromFEN failed: %v", err)
+			}
+			move, err := parseSAN(b, whiteTurn, test.san)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("Expected %q to fail, got %q", test.san, move)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSAN failed: %v", err)
+			}
+			if move != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, move)
+			}
+		})
+	}
+}
+
+func TestFEN(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    string
+		expected string
+	}{
+		{"no moves", "", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{"coordinates", "e2e4 c7c5 e4e5 d7d5", "rnbqkbnr/pp2pppp/8/2ppP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3"},
+		{"SAN", "e4 c5 e5 d5", "rnbqkbnr/pp2pppp/8/2ppP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3"},
+		{"mixed", "e4 c7c5 Nf3", "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"},
+		{"illegal", "e4 e5 Ke3", ""},
+		{"wrong side", "e2e4 e4e5", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fen, err := FEN(strings.Fields(test.moves))
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("Expected %q to fail, got %q", test.moves, fen)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FEN failed: %v", err)
+			}
+			if fen != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, fen)
+			}
+		})
+	}
+}
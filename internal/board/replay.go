@@ -0,0 +1,123 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+type replayModel struct {
+	ctx      *app.Context
+	game     database.Game
+	moves    []string
+	fens     []string
+	ply      int
+	username string
+}
+
+// NewReplayModel opens a stored game for move-by-move replay. Leaving the
+// replay returns to the game history of username.
+func NewReplayModel(ctx *app.Context, gameID, username string) (tea.Model, error) {
+	if ctx == nil || ctx.Store == nil {
+		return nil, fmt.Errorf("context or Store is nil")
+	}
+
+	game, err := ctx.Store.GetGame(context.Background(), gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+
+	moves := strings.Fields(game.Moves)
+	fens, err := replayFENs(moves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay game: %w", err)
+	}
+
+	m := replayModel{
+		ctx:      ctx,
+		game:     game,
+		moves:    moves,
+		fens:     fens,
+		username: username,
+	}
+
+	return &m, nil
+}
+
+func (m *replayModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *replayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			username := m.username
+			return m, func() tea.Msg {
+				return messages.SwitchToGameHistory{Username: username}
+			}
+		case "left":
+			m.ply = max(m.ply-1, 0)
+		case "right":
+			m.ply = min(m.ply+1, len(m.moves))
+		case "home":
+			m.ply = 0
+		case "end":
+			m.ply = len(m.moves)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *replayModel) View() string {
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	date := m.game.EndedAt
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	s := fmt.Sprintf("%s vs %s, %s\n", m.game.WhiteName, m.game.BlackName, date)
+	s += fmt.Sprintf("Result: %s (%s)\n\n", m.game.Result, m.game.Termination)
+
+	b, _, _, err := boardFromFEN(m.fens[m.ply])
+	if err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+		s += errStyle.Render(err.Error()) + "\n"
+	} else {
+		s += b.renderString()
+	}
+
+	if m.ply == 0 {
+		s += "Starting position\n\n"
+	} else {
+		s += fmt.Sprintf("Move %d of %d\n\n", m.ply, len(m.moves))
+	}
+
+	for i, move := range m.moves {
+		if i%2 == 0 {
+			s += fmt.Sprintf("%d. ", i/2+1)
+		}
+		if i+1 == m.ply {
+			s += highlightStyle.Render(move)
+		} else {
+			s += move
+		}
+		s += " "
+	}
+	if len(m.moves) > 0 {
+		s += "\n"
+	}
+
+	s += "\nUse left/right arrows to step through moves, home/end to jump.\n"
+	s += "Press q, esc or ctrl+c to return to the game history.\n"
+
+	return s
+}
@@ -0,0 +1,174 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/google/uuid"
+)
+
+func nullUserID(user *app.User) sql.NullString {
+	if user == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: user.ID, Valid: true}
+}
+
+func (m *boardModel) fen() (string, error) {
+	return m.board.toFEN(m.whiteTurn, len(m.moves)/2+1)
+}
+
+func (m *boardModel) saveGame() {
+	if m.ctx == nil || m.ctx.Store == nil {
+		return
+	}
+
+	if m.gameID == "" {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			m.err = fmt.Sprintf("Failed to save game: %v", err)
+			return
+		}
+		m.gameID = id.String()
+
+		if m.ctx.Pairing != nil {
+			err = m.linkPairing()
+			if err != nil {
+				m.err = fmt.Sprintf("Failed to save game: %v", err)
+				return
+			}
+		}
+	}
+
+	fen, err := m.fen()
+	if err != nil {
+		m.err = fmt.Sprintf("Failed to save game: %v", err)
+		return
+	}
+
+	params := database.SaveGameParams{
+		ID:          m.gameID,
+		WhiteUserID: nullUserID(m.ctx.White()),
+		BlackUserID: nullUserID(m.ctx.Black()),
+		CreatedAt:   sql.NullString{String: m.startedAt.Format(time.RFC3339), Valid: true},
+		UpdatedAt:   sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+		Fen:         fen,
+		Moves:       strings.Join(m.moves, " "),
+		OfferedDraw: m.offeredDraw,
+	}
+	_, err = m.ctx.Store.SaveGame(context.Background(), params)
+	if err != nil {
+		m.err = fmt.Sprintf("Failed to save game: %v", err)
+	}
+}
+
+func (m *boardModel) deleteSavedGame() {
+	if m.ctx == nil || m.ctx.Store == nil || m.gameID == "" {
+		return
+	}
+
+	err := m.ctx.Store.DeleteSavedGame(context.Background(), m.gameID)
+	if err != nil {
+		m.gameOverMsg += fmt.Sprintf("\n\nWarning: failed to remove saved game: %v", err)
+	}
+}
+
+func FindSavedGame(ctx *app.Context) (string, error) {
+	if ctx == nil || ctx.Store == nil {
+		return "", fmt.Errorf("context or Store is nil")
+	}
+
+	params := database.GetLatestSavedGameParams{
+		WhiteUserID: nullUserID(ctx.User1),
+		BlackUserID: nullUserID(ctx.User2),
+	}
+	saved, err := ctx.Store.GetLatestSavedGame(context.Background(), params)
+	if err == sql.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to look up saved game: %w", err)
+	}
+
+	return saved.ID, nil
+}
+
+func ResumeBoardModel(ctx *app.Context, gameID string) (tea.Model, error) {
+	if ctx == nil || ctx.Store == nil {
+		return nil, fmt.Errorf("context or Store is nil")
+	}
+
+	saved, err := ctx.Store.GetSavedGame(context.Background(), gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved game: %w", err)
+	}
+
+	if saved.WhiteUserID != nullUserID(ctx.White()) || saved.BlackUserID != nullUserID(ctx.Black()) {
+		return nil, fmt.Errorf("saved game belongs to different players")
+	}
+
+	b, whiteTurn, _, err := boardFromFEN(saved.Fen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore saved position: %w", err)
+	}
+
+	startedAt := time.Now()
+	if saved.CreatedAt.Valid {
+		parsed, err := time.Parse(time.RFC3339, saved.CreatedAt.String)
+		if err == nil {
+			startedAt = parsed
+		}
+	}
+
+	input := textinput.New()
+	input.Focus()
+	input.CharLimit = 15
+	input.Width = 30
+
+	m := boardModel{
+		board:       b,
+		ctx:         ctx,
+		whiteTurn:   whiteTurn,
+		input:       input,
+		gameID:      saved.ID,
+		startedAt:   startedAt,
+		moves:       strings.Fields(saved.Moves),
+		offeredDraw: saved.OfferedDraw,
+	}
+
+	if whiteTurn && isUnderAttack(b.whiteKingPosition, "white", b) {
+		m.check = "White king is under check!"
+	} else if !whiteTurn && isUnderAttack(b.blackKingPosition, "black", b) {
+		m.check = "Black king is under check!"
+	}
+
+	if m.offeredDraw {
+		m.drawMsg = "Draw offer sent by opponent. You can accept by typing 'draw'."
+		m.drawTimer = 1
+	}
+	resetInputField(&m)
+
+	return &m, nil
+}
+
+// linkPairing stores the ID of the game with its tournament pairing or
+// match game, so the game can be resumed from there.
+func (m *boardModel) linkPairing() error {
+	gameID := sql.NullString{String: m.gameID, Valid: true}
+	if m.ctx.Pairing.MatchID != "" {
+		return m.ctx.Store.SetMatchGameID(context.Background(), database.SetMatchGameIDParams{
+			GameID: gameID,
+			ID:     m.ctx.Pairing.ID,
+		})
+	}
+	return m.ctx.Store.SetTournamentPairingGame(context.Background(), database.SetTournamentPairingGameParams{
+		GameID: gameID,
+		ID:     m.ctx.Pairing.ID,
+	})
+}
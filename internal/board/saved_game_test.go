@@ -0,0 +1,282 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+func TestSaveAndResumeGame(t *testing.T) {
+	ctx := &app.Context{
+		Store: storage.NewMemory(),
+	}
+
+	model := NewBoardModel(ctx).(*boardModel)
+	for _, input := range []string{"e2 e4", "e7 e5", "g1 f3", "draw"} {
+		model.Update(gameMsg{input: input})
+	}
+	if model.gameID == "" {
+		t.Fatal("Expected game to be saved after the first move")
+	}
+
+	gameID, err := FindSavedGame(ctx)
+	if err != nil {
+		t.Fatalf("FindSavedGame failed: %v", err)
+	}
+	if gameID != model.gameID {
+		t.Fatalf("Expected saved game %q, got %q", model.gameID, gameID)
+	}
+
+	resumed, err := ResumeBoardModel(ctx, gameID)
+	if err != nil {
+		t.Fatalf("ResumeBoardModel failed: %v", err)
+	}
+	resumedModel := resumed.(*boardModel)
+
+	expectedFEN, err := model.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	resumedFEN, err := resumedModel.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	if resumedFEN != expectedFEN {
+		t.Errorf("Expected resumed position %q, got %q", expectedFEN, resumedFEN)
+	}
+	if !resumedModel.whiteTurn {
+		t.Error("Expected white to answer the draw offer after resuming")
+	}
+	if !resumedModel.offeredDraw {
+		t.Error("Expected pending draw offer to be restored")
+	}
+	if len(resumedModel.moves) != 3 || resumedModel.moves[2] != "g1f3" {
+		t.Errorf("Unexpected resumed move list: %v", resumedModel.moves)
+	}
+
+	_, cmd := resumedModel.Update(gameMsg{input: "draw"})
+	if cmd == nil {
+		t.Fatal("Expected accepted draw to end the game")
+	}
+	resumedModel.Update(cmd())
+	if !resumedModel.gameOver {
+		t.Fatal("Expected game to be over")
+	}
+
+	_, err = ctx.Store.GetSavedGame(context.Background(), gameID)
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected saved game to be removed after game over, got %v", err)
+	}
+
+	game, err := ctx.Store.GetGame(context.Background(), gameID)
+	if err != nil {
+		t.Fatalf("Expected finished game to be stored: %v", err)
+	}
+	if game.Moves != "e4 e5 Nf3" {
+		t.Errorf("Unexpected stored moves %q", game.Moves)
+	}
+	if game.Result != resultDraw || game.Termination != terminationAgreement {
+		t.Errorf("Unexpected stored result %q (%q)", game.Result, game.Termination)
+	}
+	if game.WhiteName != "Guest 1" || game.BlackName != "Guest 2" || game.WhiteUserID.Valid || game.BlackUserID.Valid {
+		t.Errorf("Expected guest players to be stored, got %+v", game)
+	}
+	if game.FinalFen != expectedFEN {
+		t.Errorf("Expected final position %q, got %q", expectedFEN, game.FinalFen)
+	}
+
+	ctx.User1 = &app.User{ID: "someone", Username: "someone"}
+	_, err = ResumeBoardModel(ctx, gameID)
+	if err == nil {
+		t.Error("Expected resuming a removed game to fail")
+	}
+}
+
+func TestRatedGame(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	for i, name := range []string{"WhitePlayer", "BlackPlayer"} {
+		user, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             name + "-id",
+			Username:       name,
+			HashedPassword: "hash",
+		})
+		if err != nil {
+			t.Fatalf("RegisterUser failed: %v", err)
+		}
+		player := &app.User{ID: user.ID, Username: user.Username}
+		if i == 0 {
+			ctx.User1 = player
+		} else {
+			ctx.User2 = player
+		}
+	}
+
+	model := NewBoardModel(ctx).(*boardModel)
+	var cmd tea.Cmd
+	for _, input := range []string{"f2 f3", "e7 e5", "g2 g4", "d8 h4"} {
+		_, cmd = model.Update(gameMsg{input: input})
+	}
+	if cmd == nil {
+		t.Fatal("Expected fool's mate to end the game")
+	}
+	model.Update(cmd())
+	if !model.gameOver {
+		t.Fatal("Expected game to be over")
+	}
+	if strings.Contains(model.gameOverMsg, "Warning") {
+		t.Fatalf("Unexpected warning after game over: %s", model.gameOverMsg)
+	}
+
+	expected := map[string]int64{
+		"WhitePlayer-id": 1480,
+		"BlackPlayer-id": 1520,
+	}
+	for userID, want := range expected {
+		record, err := store.GetRecordsByUserID(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("GetRecordsByUserID failed: %v", err)
+		}
+		if record.Rating != want || record.RatedGames != 1 {
+			t.Errorf("Expected rating %d after 1 game for %s, got %d after %d", want, userID, record.Rating, record.RatedGames)
+		}
+		if (record.GlickoRating > 1500) != (want > 1500) || record.GlickoDeviation >= 350 || !record.GlickoRatedAt.Valid {
+			t.Errorf("Unexpected Glicko-2 rating for %s: %.2f ± %.2f rated at %v", userID, record.GlickoRating, record.GlickoDeviation, record.GlickoRatedAt)
+		}
+
+		history, err := store.ListRatingHistoryByUserID(context.Background(), database.ListRatingHistoryByUserIDParams{
+			UserID: userID,
+			Limit:  10,
+		})
+		if err != nil {
+			t.Fatalf("ListRatingHistoryByUserID failed: %v", err)
+		}
+		if len(history) != 1 || history[0].RatingBefore != 1500 || history[0].RatingAfter != want || history[0].GameID != model.gameID {
+			t.Errorf("Unexpected rating history for %s: %+v", userID, history)
+		}
+	}
+}
+
+func TestPromotionMate(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	for i, name := range []string{"WhitePlayer", "BlackPlayer"} {
+		user, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             name + "-id",
+			Username:       name,
+			HashedPassword: "hash",
+		})
+		if err != nil {
+			t.Fatalf("RegisterUser failed: %v", err)
+		}
+		player := &app.User{ID: user.ID, Username: user.Username}
+		if i == 0 {
+			ctx.User1 = player
+		} else {
+			ctx.User2 = player
+		}
+	}
+
+	model := NewBoardModel(ctx).(*boardModel)
+	for _, input := range []string{"e2 e4", "d7 d5", "e4 d5", "c7 c6", "d5 c6", "d8 d6", "c6 b7", "d6 h2", "b7 c8"} {
+		model.Update(gameMsg{input: input})
+	}
+	if model.promotionSquare == nil {
+		t.Fatal("Expected the pawn on c8 to wait for its promotion")
+	}
+
+	// The queen is the first choice.
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the promotion to a queen to mate")
+	}
+	msg, ok := cmd().(overMsg)
+	if !ok || msg.result != resultWhiteWins || msg.termination != terminationCheckmate {
+		t.Fatalf("Expected white to win by checkmate, got %+v", msg)
+	}
+	model.Update(msg)
+
+	game, err := store.GetGame(context.Background(), model.gameID)
+	if err != nil {
+		t.Fatalf("Expected finished game to be stored: %v", err)
+	}
+	if !strings.HasSuffix(game.Moves, "bxc8=Q#") {
+		t.Errorf("Expected the game to end with bxc8=Q#, got %q", game.Moves)
+	}
+
+	achievements, err := store.ListAchievementsByUserID(context.Background(), "WhitePlayer-id")
+	if err != nil {
+		t.Fatalf("ListAchievementsByUserID failed: %v", err)
+	}
+	promoted := false
+	for _, unlocked := range achievements {
+		promoted = promoted || unlocked.AchievementID == "promotion-mate"
+	}
+	if !promoted {
+		t.Errorf("Expected the promotion mate to be unlocked, got %+v", achievements)
+	}
+}
+
+func TestReplayModel(t *testing.T) {
+	ctx := &app.Context{
+		Store: storage.NewMemory(),
+	}
+
+	model := NewBoardModel(ctx).(*boardModel)
+	var cmd tea.Cmd
+	for _, input := range []string{"e2 e4", "e7 e5", "f1 c4", "b8 c6", "d1 h5", "g8 f6", "h5 f7"} {
+		_, cmd = model.Update(gameMsg{input: input})
+	}
+	if cmd == nil {
+		t.Fatal("Expected scholar's mate to end the game")
+	}
+	model.Update(cmd())
+
+	replay, err := NewReplayModel(ctx, model.gameID, "someone")
+	if err != nil {
+		t.Fatalf("NewReplayModel failed: %v", err)
+	}
+	replayModel := replay.(*replayModel)
+
+	finalFEN, err := model.fen()
+	if err != nil {
+		t.Fatalf("fen failed: %v", err)
+	}
+	if len(replayModel.fens) != 8 || replayModel.fens[7] != finalFEN {
+		t.Fatalf("Expected replay to end in %q, got %v", finalFEN, replayModel.fens)
+	}
+
+	keys := []struct {
+		key tea.KeyType
+		ply int
+	}{
+		{tea.KeyLeft, 0},
+		{tea.KeyRight, 1},
+		{tea.KeyEnd, 7},
+		{tea.KeyRight, 7},
+		{tea.KeyHome, 0},
+	}
+	for _, key := range keys {
+		replayModel.Update(tea.KeyMsg{Type: key.key})
+		if replayModel.ply != key.ply {
+			t.Errorf("Expected ply %d after %v, got %d", key.ply, key.key, replayModel.ply)
+		}
+	}
+
+	_, err = NewReplayModel(ctx, "missing", "someone")
+	if err == nil {
+		t.Error("Expected replaying a missing game to fail")
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	RatingSystemElo     = "elo"
+	RatingSystemGlicko2 = "glicko2"
+)
+
+type Config struct {
+	RatingSystem string        `json:"rating_system"`
+	Elo          EloConfig     `json:"elo"`
+	Glicko2      Glicko2Config `json:"glicko2"`
+}
+
+type EloConfig struct {
+	KFactor            float64 `json:"k_factor"`
+	ProvisionalKFactor float64 `json:"provisional_k_factor"`
+	ProvisionalGames   int64   `json:"provisional_games"`
+}
+
+type Glicko2Config struct {
+	Tau              float64 `json:"tau"`
+	RatingPeriodDays float64 `json:"rating_period_days"`
+}
+
+func Default() Config {
+	return Config{
+		RatingSystem: RatingSystemElo,
+		Elo: EloConfig{
+			KFactor:            20,
+			ProvisionalKFactor: 40,
+			ProvisionalGames:   30,
+		},
+		Glicko2: Glicko2Config{
+			Tau:              0.5,
+			RatingPeriodDays: 7,
+		},
+	}
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/gomate/config.json, or the
+// platform's user config directory when XDG_CONFIG_HOME is not set.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "gomate", "config.json"), nil
+}
+
+// Load reads the JSON configuration at path. Settings missing from the
+// file, or the whole file if it does not exist, fall back to Default.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	if c.RatingSystem != RatingSystemElo && c.RatingSystem != RatingSystemGlicko2 {
+		return fmt.Errorf("rating_system must be %q or %q", RatingSystemElo, RatingSystemGlicko2)
+	}
+	if c.Elo.KFactor <= 0 {
+		return fmt.Errorf("elo.k_factor must be positive")
+	}
+	if c.Elo.ProvisionalKFactor <= 0 {
+		return fmt.Errorf("elo.provisional_k_factor must be positive")
+	}
+	if c.Elo.ProvisionalGames < 0 {
+		return fmt.Errorf("elo.provisional_games cannot be negative")
+	}
+	if c.Glicko2.Tau <= 0 {
+		return fmt.Errorf("glicko2.tau must be positive")
+	}
+	if c.Glicko2.RatingPeriodDays <= 0 {
+		return fmt.Errorf("glicko2.rating_period_days must be positive")
+	}
+
+	return nil
+}
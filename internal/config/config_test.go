@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("Load failed for missing file: %v", err)
+	}
+	if cfg != Default() {
+		t.Errorf("Expected defaults for missing file, got %+v", cfg)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		kFactor float64
+	}{
+		{"partial override", `{"elo": {"k_factor": 32}}`, false, 32},
+		{"empty object", `{}`, false, Default().Elo.KFactor},
+		{"malformed json", `{"elo": `, true, 0},
+		{"negative k factor", `{"elo": {"k_factor": -5}}`, true, 0},
+		{"glicko2 selected", `{"rating_system": "glicko2", "glicko2": {"tau": 0.3}}`, false, Default().Elo.KFactor},
+		{"unknown rating system", `{"rating_system": "trueskill"}`, true, 0},
+		{"zero rating period", `{"glicko2": {"rating_period_days": 0}}`, true, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.json")
+			err := os.WriteFile(path, []byte(test.content), 0o600)
+			if err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			cfg, err := Load(path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Load error = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && cfg.Elo.KFactor != test.kFactor {
+				t.Errorf("Expected k factor %v, got %v", test.kFactor, cfg.Elo.KFactor)
+			}
+			if err == nil && cfg.Elo.ProvisionalGames != Default().Elo.ProvisionalGames {
+				t.Errorf("Expected unset settings to keep defaults, got %+v", cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/deskdaniel/GoMate/sql/schema"
+	"github.com/pressly/goose/v3"
+)
+
+// Migrate applies the schema migrations embedded in the binary that have
+// not been applied to db yet. It refuses to touch a database created by a
+// newer version of GoMate.
+func Migrate(db *sql.DB) error {
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	sources := provider.ListSources()
+	if len(sources) == 0 {
+		return fmt.Errorf("no migrations embedded in binary")
+	}
+	latest := sources[len(sources)-1].Version
+
+	current, err := provider.GetDBVersion(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read database schema version: %w", err)
+	}
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than the latest version %d supported by this build, please upgrade GoMate", current, latest)
+	}
+
+	_, err = provider.Up(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return nil
+}
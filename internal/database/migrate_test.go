@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/sql/schema"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose/v3"
+)
+
+func TestMigrate(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	err = Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate failed on empty database: %v", err)
+	}
+
+	_, err = db.Exec("SELECT id FROM users")
+	if err != nil {
+		t.Errorf("Expected users table to exist after migrating: %v", err)
+	}
+
+	err = Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate failed on up to date database: %v", err)
+	}
+
+	_, err = db.Exec("INSERT INTO goose_db_version (version_id, is_applied) VALUES (9999, 1)")
+	if err != nil {
+		t.Fatalf("Failed to simulate newer schema: %v", err)
+	}
+
+	err = Migrate(db)
+	if err == nil {
+		t.Error("Expected Migrate to refuse a database newer than the binary")
+	}
+}
+
+func TestMigrateMergesDuplicateRecords(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	_, err = provider.UpTo(context.Background(), 6)
+	if err != nil {
+		t.Fatalf("Failed to migrate to version 6: %v", err)
+	}
+
+	statements := []string{
+		"INSERT INTO users (id, username, hashed_password) VALUES ('user', 'User', 'hash')",
+		"INSERT INTO records (id, user_id, wins, losses, draws) VALUES ('a', 'user', 1, 2, 0)",
+		"INSERT INTO records (id, user_id, wins, losses, draws) VALUES ('b', 'user', 3, NULL, 1)",
+	}
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+		if err != nil {
+			t.Fatalf("Failed to insert test data: %v", err)
+		}
+	}
+
+	err = Migrate(db)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	record, err := New(db).GetRecordsByUserID(context.Background(), "user")
+	if err != nil {
+		t.Fatalf("GetRecordsByUserID failed: %v", err)
+	}
+	if record.ID != "a" || record.Wins.Int64 != 4 || record.Losses.Int64 != 2 || record.Draws.Int64 != 1 {
+		t.Errorf("Expected duplicate records to be merged, got %+v", record)
+	}
+
+	_, err = db.Exec("INSERT INTO records (id, user_id) VALUES ('c', 'user')")
+	if err == nil {
+		t.Error("Expected a second record for the same user to be rejected")
+	}
+}
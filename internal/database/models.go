@@ -8,14 +8,126 @@ import (
 	"database/sql"
 )
 
+type Achievement struct {
+	UserID        string
+	AchievementID string
+	GameID        string
+	UnlockedAt    string
+}
+
+type Game struct {
+	ID          string
+	WhiteUserID sql.NullString
+	BlackUserID sql.NullString
+	WhiteName   string
+	BlackName   string
+	Result      string
+	Termination string
+	StartedAt   string
+	EndedAt     string
+	Moves       string
+	FinalFen    string
+	TimeControl string
+}
+
+type LoginAttempt struct {
+	Username       string
+	FailedAttempts int64
+	LastFailedAt   string
+	LockedUntil    sql.NullString
+}
+
+type Match struct {
+	ID            string
+	Player1UserID sql.NullString
+	Player2UserID sql.NullString
+	Player1Name   string
+	Player2Name   string
+	Games         int64
+	Armageddon    bool
+	CreatedAt     string
+	FinishedAt    sql.NullString
+}
+
+type MatchGame struct {
+	ID           string
+	MatchID      string
+	Number       int64
+	Player1White bool
+	Armageddon   bool
+	Result       sql.NullString
+	GameID       sql.NullString
+}
+
+type RatingHistory struct {
+	ID           string
+	UserID       string
+	GameID       string
+	CreatedAt    sql.NullString
+	RatingBefore int64
+	RatingAfter  int64
+}
+
 type Record struct {
-	ID        string
+	ID               string
+	UserID           string
+	CreatedAt        sql.NullString
+	UpdatedAt        sql.NullString
+	Wins             sql.NullInt64
+	Losses           sql.NullInt64
+	Draws            sql.NullInt64
+	Rating           int64
+	RatedGames       int64
+	GlickoRating     float64
+	GlickoDeviation  float64
+	GlickoVolatility float64
+	GlickoRatedAt    sql.NullString
+}
+
+type SavedGame struct {
+	ID          string
+	WhiteUserID sql.NullString
+	BlackUserID sql.NullString
+	CreatedAt   sql.NullString
+	UpdatedAt   sql.NullString
+	Fen         string
+	Moves       string
+	OfferedDraw bool
+}
+
+type Session struct {
+	TokenHash string
 	UserID    string
 	CreatedAt sql.NullString
-	UpdatedAt sql.NullString
-	Wins      sql.NullInt64
-	Losses    sql.NullInt64
-	Draws     sql.NullInt64
+	ExpiresAt string
+}
+
+type Tournament struct {
+	ID        string
+	Name      string
+	Format    string
+	Rounds    int64
+	CreatedAt string
+	StartedAt sql.NullString
+}
+
+type TournamentPairing struct {
+	ID            string
+	TournamentID  string
+	Round         int64
+	Board         int64
+	WhitePlayerID string
+	BlackPlayerID sql.NullString
+	Result        sql.NullString
+	GameID        sql.NullString
+}
+
+type TournamentPlayer struct {
+	ID           string
+	TournamentID string
+	UserID       sql.NullString
+	Name         string
+	Seed         int64
 }
 
 type User struct {
@@ -3,18 +3,32 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 )
 
-func OpenDb() (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", "./chess.db")
+func OpenDb(path string) (*sql.DB, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
 
 	_, err = db.Exec("PRAGMA foreign_keys = ON;")
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to enable foreign keys in db: %w", err)
 	}
 
+	err = Migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return db, nil
 }
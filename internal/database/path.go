@@ -0,0 +1,125 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	EnvDbPath  = "GOMATE_DB"
+	LegacyPath = "chess.db"
+	appDirName = "gomate"
+	dbFileName = "chess.db"
+)
+
+// DefaultPath returns the database location used when neither the --db
+// flag nor GOMATE_DB is set: $XDG_DATA_HOME/gomate/chess.db, falling back
+// to ~/.local/share (or the user config directory on Windows).
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if runtime.GOOS == "windows" {
+			dir, err := os.UserConfigDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to locate user data directory: %w", err)
+			}
+			dataHome = dir
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to locate home directory: %w", err)
+			}
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+
+	return filepath.Join(dataHome, appDirName, dbFileName), nil
+}
+
+func ResolvePath(flagPath string) (string, error) {
+	if flagPath != "" {
+		return flagPath, nil
+	}
+	if envPath := os.Getenv(EnvDbPath); envPath != "" {
+		return envPath, nil
+	}
+
+	return DefaultPath()
+}
+
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// HasLegacyDb reports whether there is no database at path yet, but a
+// chess.db from older versions, which always used the working directory,
+// is present.
+func HasLegacyDb(path string) (bool, error) {
+	legacy, err := filepath.Abs(LegacyPath)
+	if err != nil {
+		return false, err
+	}
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	if legacy == target {
+		return false, nil
+	}
+
+	exists, err := fileExists(target)
+	if err != nil || exists {
+		return false, err
+	}
+
+	return fileExists(legacy)
+}
+
+// CopyLegacyDb copies ./chess.db to path. It refuses to overwrite an
+// existing database.
+func CopyLegacyDb(path string) error {
+	exists, err := fileExists(path)
+	if err != nil {
+		return fmt.Errorf("failed to check database location: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("database already exists at %s, refusing to overwrite it", path)
+	}
+
+	src, err := os.Open(LegacyPath)
+	if err != nil {
+		return fmt.Errorf("failed to open local database: %w", err)
+	}
+	defer src.Close()
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create database file: %w", err)
+	}
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		dst.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to copy local database: %w", err)
+	}
+
+	return dst.Close()
+}
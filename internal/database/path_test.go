@@ -0,0 +1,86 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	t.Setenv(EnvDbPath, "")
+
+	path, err := ResolvePath("")
+	if err != nil {
+		t.Fatalf("ResolvePath failed: %v", err)
+	}
+	if path != filepath.Join("/data", "gomate", "chess.db") {
+		t.Errorf("Expected XDG default path, got %q", path)
+	}
+
+	t.Setenv(EnvDbPath, "/env/chess.db")
+	path, err = ResolvePath("")
+	if err != nil {
+		t.Fatalf("ResolvePath failed: %v", err)
+	}
+	if path != "/env/chess.db" {
+		t.Errorf("Expected path from environment, got %q", path)
+	}
+
+	path, err = ResolvePath("/flag/chess.db")
+	if err != nil {
+		t.Fatalf("ResolvePath failed: %v", err)
+	}
+	if path != "/flag/chess.db" {
+		t.Errorf("Expected path from flag, got %q", path)
+	}
+}
+
+func TestCopyLegacyDb(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	target := filepath.Join(dir, "data", "gomate", "chess.db")
+
+	legacy, err := HasLegacyDb(target)
+	if err != nil {
+		t.Fatalf("HasLegacyDb failed: %v", err)
+	}
+	if legacy {
+		t.Error("Expected no legacy database in empty directory")
+	}
+
+	err = os.WriteFile(LegacyPath, []byte("legacy"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to create legacy database: %v", err)
+	}
+
+	legacy, err = HasLegacyDb(target)
+	if err != nil {
+		t.Fatalf("HasLegacyDb failed: %v", err)
+	}
+	if !legacy {
+		t.Error("Expected legacy database to be detected")
+	}
+
+	err = CopyLegacyDb(target)
+	if err != nil {
+		t.Fatalf("CopyLegacyDb failed: %v", err)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil || string(content) != "legacy" {
+		t.Errorf("Expected legacy database to be copied, got %q (%v)", content, err)
+	}
+
+	legacy, err = HasLegacyDb(target)
+	if err != nil {
+		t.Fatalf("HasLegacyDb failed: %v", err)
+	}
+	if legacy {
+		t.Error("Expected legacy database to be ignored once the target exists")
+	}
+
+	err = CopyLegacyDb(target)
+	if err == nil {
+		t.Error("Expected CopyLegacyDb to refuse overwriting an existing database")
+	}
+}
@@ -10,8 +10,76 @@ import (
 	"database/sql"
 )
 
+const addResult = `-- name: AddResult :one
+INSERT INTO records (id, user_id, created_at, updated_at, wins, losses, draws)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    updated_at = excluded.updated_at,
+    wins = COALESCE(records.wins, 0) + excluded.wins,
+    losses = COALESCE(records.losses, 0) + excluded.losses,
+    draws = COALESCE(records.draws, 0) + excluded.draws
+RETURNING id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at
+`
+
+type AddResultParams struct {
+	ID        string
+	UserID    string
+	CreatedAt sql.NullString
+	UpdatedAt sql.NullString
+	Wins      sql.NullInt64
+	Losses    sql.NullInt64
+	Draws     sql.NullInt64
+}
+
+func (q *Queries) AddResult(ctx context.Context, arg AddResultParams) (Record, error) {
+	row := q.db.QueryRowContext(ctx, addResult,
+		arg.ID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Wins,
+		arg.Losses,
+		arg.Draws,
+	)
+	var i Record
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Wins,
+		&i.Losses,
+		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
+	)
+	return i, err
+}
+
+const deleteRecordsByUserID = `-- name: DeleteRecordsByUserID :exec
+DELETE FROM records
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRecordsByUserID(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRecordsByUserID, userID)
+	return err
+}
+
 const getRecordsByUserID = `-- name: GetRecordsByUserID :one
-SELECT id, user_id, created_at, updated_at, wins, losses, draws FROM records
+SELECT id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at FROM records
 WHERE user_id = ?
 `
 
@@ -26,10 +94,127 @@ func (q *Queries) GetRecordsByUserID(ctx context.Context, userID string) (Record
 		&i.Wins,
 		&i.Losses,
 		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
 	)
 	return i, err
 }
 
+const importRecord = `-- name: ImportRecord :execrows
+INSERT INTO records (id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    updated_at = excluded.updated_at,
+    wins = excluded.wins,
+    losses = excluded.losses,
+    draws = excluded.draws,
+    rating = excluded.rating,
+    rated_games = excluded.rated_games,
+    glicko_rating = excluded.glicko_rating,
+    glicko_deviation = excluded.glicko_deviation,
+    glicko_volatility = excluded.glicko_volatility,
+    glicko_rated_at = excluded.glicko_rated_at
+WHERE COALESCE(excluded.updated_at, '') > COALESCE(records.updated_at, '')
+`
+
+type ImportRecordParams struct {
+	ID               string
+	UserID           string
+	CreatedAt        sql.NullString
+	UpdatedAt        sql.NullString
+	Wins             sql.NullInt64
+	Losses           sql.NullInt64
+	Draws            sql.NullInt64
+	Rating           int64
+	RatedGames       int64
+	GlickoRating     float64
+	GlickoDeviation  float64
+	GlickoVolatility float64
+	GlickoRatedAt    sql.NullString
+}
+
+func (q *Queries) ImportRecord(ctx context.Context, arg ImportRecordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importRecord,
+		arg.ID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Wins,
+		arg.Losses,
+		arg.Draws,
+		arg.Rating,
+		arg.RatedGames,
+		arg.GlickoRating,
+		arg.GlickoDeviation,
+		arg.GlickoVolatility,
+		arg.GlickoRatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listRecords = `-- name: ListRecords :many
+SELECT id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at FROM records
+ORDER BY user_id
+`
+
+func (q *Queries) ListRecords(ctx context.Context) ([]Record, error) {
+	rows, err := q.db.QueryContext(ctx, listRecords)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Record
+	for rows.Next() {
+		var i Record
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Wins,
+			&i.Losses,
+			&i.Draws,
+			&i.Rating,
+			&i.RatedGames,
+			&i.GlickoRating,
+			&i.GlickoDeviation,
+			&i.GlickoVolatility,
+			&i.GlickoRatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const registerRecord = `-- name: RegisterRecord :one
 INSERT INTO records (id, user_id, created_at, updated_at, wins, losses, draws)
 VALUES (
@@ -41,7 +226,7 @@ VALUES (
     ?,
     ?
 )
-RETURNING id, user_id, created_at, updated_at, wins, losses, draws
+RETURNING id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at
 `
 
 type RegisterRecordParams struct {
@@ -73,6 +258,12 @@ func (q *Queries) RegisterRecord(ctx context.Context, arg RegisterRecordParams)
 		&i.Wins,
 		&i.Losses,
 		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
 	)
 	return i, err
 }
@@ -86,6 +277,92 @@ func (q *Queries) ResetRecords(ctx context.Context) error {
 	return err
 }
 
+const updateGlicko2 = `-- name: UpdateGlicko2 :one
+UPDATE records
+SET
+    updated_at = ?,
+    glicko_rating = ?,
+    glicko_deviation = ?,
+    glicko_volatility = ?,
+    glicko_rated_at = ?
+WHERE user_id = ?
+RETURNING id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at
+`
+
+type UpdateGlicko2Params struct {
+	UpdatedAt        sql.NullString
+	GlickoRating     float64
+	GlickoDeviation  float64
+	GlickoVolatility float64
+	GlickoRatedAt    sql.NullString
+	UserID           string
+}
+
+func (q *Queries) UpdateGlicko2(ctx context.Context, arg UpdateGlicko2Params) (Record, error) {
+	row := q.db.QueryRowContext(ctx, updateGlicko2,
+		arg.UpdatedAt,
+		arg.GlickoRating,
+		arg.GlickoDeviation,
+		arg.GlickoVolatility,
+		arg.GlickoRatedAt,
+		arg.UserID,
+	)
+	var i Record
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Wins,
+		&i.Losses,
+		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
+	)
+	return i, err
+}
+
+const updateRating = `-- name: UpdateRating :one
+UPDATE records
+SET
+    updated_at = ?,
+    rating = ?,
+    rated_games = rated_games + 1
+WHERE user_id = ?
+RETURNING id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at
+`
+
+type UpdateRatingParams struct {
+	UpdatedAt sql.NullString
+	Rating    int64
+	UserID    string
+}
+
+func (q *Queries) UpdateRating(ctx context.Context, arg UpdateRatingParams) (Record, error) {
+	row := q.db.QueryRowContext(ctx, updateRating, arg.UpdatedAt, arg.Rating, arg.UserID)
+	var i Record
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Wins,
+		&i.Losses,
+		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
+	)
+	return i, err
+}
+
 const updateRecord = `-- name: UpdateRecord :one
 UPDATE records
 SET
@@ -94,7 +371,7 @@ SET
     losses = ?,
     draws = ?
 WHERE user_id = ?
-RETURNING id, user_id, created_at, updated_at, wins, losses, draws
+RETURNING id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at
 `
 
 type UpdateRecordParams struct {
@@ -122,6 +399,12 @@ func (q *Queries) UpdateRecord(ctx context.Context, arg UpdateRecordParams) (Rec
 		&i.Wins,
 		&i.Losses,
 		&i.Draws,
+		&i.Rating,
+		&i.RatedGames,
+		&i.GlickoRating,
+		&i.GlickoDeviation,
+		&i.GlickoVolatility,
+		&i.GlickoRatedAt,
 	)
 	return i, err
 }
@@ -10,6 +10,17 @@ import (
 	"database/sql"
 )
 
+const deleteUser = `-- name: DeleteUser :exec
+
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
 const getUserByName = `-- name: GetUserByName :one
 SELECT id, username, created_at, updated_at, hashed_password FROM users
 WHERE username = ?
@@ -28,6 +39,41 @@ func (q *Queries) GetUserByName(ctx context.Context, username string) (User, err
 	return i, err
 }
 
+const listUsers = `-- name: ListUsers :many
+
+SELECT id, username, created_at, updated_at, hashed_password FROM users
+ORDER BY username
+`
+
+func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.HashedPassword,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const registerUser = `-- name: RegisterUser :one
 
 INSERT INTO users (id, username, created_at, updated_at, hashed_password)
@@ -77,3 +123,57 @@ func (q *Queries) ResetUsers(ctx context.Context) error {
 	_, err := q.db.ExecContext(ctx, resetUsers)
 	return err
 }
+
+const updatePassword = `-- name: UpdatePassword :one
+
+UPDATE users
+SET hashed_password = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, username, created_at, updated_at, hashed_password
+`
+
+type UpdatePasswordParams struct {
+	HashedPassword string
+	UpdatedAt      sql.NullString
+	ID             string
+}
+
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updatePassword, arg.HashedPassword, arg.UpdatedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+	)
+	return i, err
+}
+
+const updateUsername = `-- name: UpdateUsername :one
+
+UPDATE users
+SET username = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, username, created_at, updated_at, hashed_password
+`
+
+type UpdateUsernameParams struct {
+	Username  string
+	UpdatedAt sql.NullString
+	ID        string
+}
+
+func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUsername, arg.Username, arg.UpdatedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+	)
+	return i, err
+}
@@ -2,10 +2,13 @@ package game
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/board"
 	"github.com/deskdaniel/GoMate/internal/messages"
 )
 
@@ -13,124 +16,195 @@ type mainMenuFields int
 
 const (
 	startNewGame mainMenuFields = iota
-	loginPlayer1
-	loginPlayer2
+	resumeGame
+	signIn
+	signOut
 	registerUser
+	manageAccount
 	viewStats
+	viewLeaderboard
+	viewHistory
+	viewTournaments
+	viewMatches
 	viewHelp
 	quit
 )
 
 type mainMenuModel struct {
-	focusIndex mainMenuFields
-	fields     []mainMenuFields
-	ctx        *app.Context
+	focusIndex  int
+	fields      []mainMenuFields
+	ctx         *app.Context
+	savedGameID string
+	err         error
 }
 
 func SetupMainMenu(ctx *app.Context) tea.Model {
-	fields := []mainMenuFields{
-		startNewGame,
-		loginPlayer1,
-		loginPlayer2,
-		registerUser,
+	var savedGameID string
+	var err error
+	if ctx != nil && ctx.Store != nil {
+		savedGameID, err = board.FindSavedGame(ctx)
+	}
+
+	fields := []mainMenuFields{startNewGame}
+	if savedGameID != "" {
+		fields = append(fields, resumeGame)
+	}
+	signedIn := ctx != nil && len(ctx.Roster) > 0
+	fields = append(fields, signIn)
+	if signedIn {
+		fields = append(fields, signOut)
+	}
+	fields = append(fields, registerUser)
+	if signedIn {
+		fields = append(fields, manageAccount)
+	}
+	fields = append(fields,
 		viewStats,
+		viewLeaderboard,
+		viewHistory,
+		viewTournaments,
+		viewMatches,
 		viewHelp,
 		quit,
-	}
+	)
 
 	m := mainMenuModel{
-		focusIndex: startNewGame,
-		fields:     fields,
-		ctx:        ctx,
+		focusIndex:  0,
+		fields:      fields,
+		ctx:         ctx,
+		savedGameID: savedGameID,
+		err:         err,
 	}
 	return m
 }
 
+// menuKey returns the number key selecting the entry at index i: 1-9,
+// then 0 for the tenth entry.
+func menuKey(i int) string {
+	switch {
+	case i < 9:
+		return strconv.Itoa(i + 1)
+	case i == 9:
+		return "0"
+	}
+	return ""
+}
+
+func rosterNames(ctx *app.Context) string {
+	names := make([]string, len(ctx.Roster))
+	for i, user := range ctx.Roster {
+		names[i] = user.Username
+	}
+	return strings.Join(names, ", ")
+}
+
 func (m mainMenuModel) Init() tea.Cmd {
 	return nil
 }
 
+func (m mainMenuModel) selectField(field mainMenuFields) tea.Cmd {
+	switch field {
+	case startNewGame:
+		if len(m.ctx.Roster) > 0 {
+			return func() tea.Msg {
+				return messages.SwitchToNewGame{}
+			}
+		}
+		return func() tea.Msg {
+			return messages.SwitchToGame{}
+		}
+	case resumeGame:
+		gameID := m.savedGameID
+		return func() tea.Msg {
+			return messages.SwitchToResumeGame{GameID: gameID}
+		}
+	case signIn:
+		return func() tea.Msg {
+			return messages.SwitchToLoginPlayer{}
+		}
+	case signOut:
+		return func() tea.Msg {
+			return messages.SwitchToSignOut{}
+		}
+	case registerUser:
+		return func() tea.Msg {
+			return messages.SwitchToRegisterUser{}
+		}
+	case manageAccount:
+		return func() tea.Msg {
+			return messages.SwitchToAccount{}
+		}
+	case viewStats:
+		return func() tea.Msg {
+			return messages.SwitchToStats{}
+		}
+	case viewLeaderboard:
+		return func() tea.Msg {
+			return messages.SwitchToLeaderboard{}
+		}
+	case viewHistory:
+		return func() tea.Msg {
+			return messages.SwitchToGameHistory{}
+		}
+	case viewTournaments:
+		return func() tea.Msg {
+			return messages.SwitchToTournaments{}
+		}
+	case viewMatches:
+		return func() tea.Msg {
+			return messages.SwitchToMatches{}
+		}
+	case viewHelp:
+		return func() tea.Msg {
+			return messages.SwitchToHelp{}
+		}
+	case quit:
+		return func() tea.Msg {
+			return messages.SwitchToQuit{}
+		}
+	}
+
+	return nil
+}
+
 func (m mainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "1":
-			return m, func() tea.Msg {
-				return messages.SwitchToGame{}
-			}
-		case "2":
-			return m, func() tea.Msg {
-				return messages.SwitchToLoginPlayer{Slot: 1}
+		s := msg.String()
+		if number, err := strconv.Atoi(s); err == nil {
+			if number == 0 {
+				number = 10
 			}
-		case "3":
-			return m, func() tea.Msg {
-				return messages.SwitchToLoginPlayer{Slot: 2}
+			if number >= 1 && number <= len(m.fields) {
+				return m, m.selectField(m.fields[number-1])
 			}
-		case "4":
-			return m, func() tea.Msg {
-				return messages.SwitchToRegisterUser{}
-			}
-		case "5":
-			return m, func() tea.Msg {
-				return messages.SwitchToStats{}
-			}
-		case "6":
-			return m, func() tea.Msg {
-				return messages.SwitchToHelp{}
-			}
-		case "7", "q", "esc", "ctrl+c":
-			return m, func() tea.Msg {
-				return messages.SwitchToQuit{}
-			}
-		case "up", "down":
-			s := msg.String()
+			return m, nil
+		}
 
+		switch s {
+		case "q", "esc", "ctrl+c":
+			return m, m.selectField(quit)
+		case "up", "down":
 			if s == "up" {
 				m.focusIndex--
 			} else {
 				m.focusIndex++
 			}
 
-			if m.focusIndex > quit {
-				m.focusIndex = startNewGame
-			} else if m.focusIndex < startNewGame {
-				m.focusIndex = quit
+			if m.focusIndex >= len(m.fields) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.fields) - 1
 			}
 
 			return m, nil
 
 		case "enter":
-			switch m.focusIndex {
-			case startNewGame:
-				return m, func() tea.Msg {
-					return messages.SwitchToGame{}
-				}
-			case loginPlayer1:
-				return m, func() tea.Msg {
-					return messages.SwitchToLoginPlayer{Slot: 1}
-				}
-			case loginPlayer2:
-				return m, func() tea.Msg {
-					return messages.SwitchToLoginPlayer{Slot: 2}
-				}
-			case registerUser:
-				return m, func() tea.Msg {
-					return messages.SwitchToRegisterUser{}
-				}
-			case viewStats:
-				return m, func() tea.Msg {
-					return messages.SwitchToStats{}
-				}
-			case viewHelp:
-				return m, func() tea.Msg {
-					return messages.SwitchToHelp{}
-				}
-			case quit:
-				return m, func() tea.Msg {
-					return messages.SwitchToQuit{}
-				}
-			}
+			return m, m.selectField(m.fields[m.focusIndex])
 		}
+	case error:
+		m.err = msg
+		return m, nil
 	}
 
 	return m, nil
@@ -138,6 +212,10 @@ func (m mainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m mainMenuModel) View() string {
 	s := "Welcome to Go-Chess!\n\n"
+	if len(m.ctx.Roster) > 0 {
+		s += fmt.Sprintf("Signed in: %s\n", rosterNames(m.ctx))
+		s += fmt.Sprintf("Next game: %s vs %s\n\n", choiceLabel(m.ctx.User1), choiceLabel(m.ctx.User2))
+	}
 
 	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
@@ -146,39 +224,55 @@ func (m mainMenuModel) View() string {
 		var label string
 		switch field {
 		case startNewGame:
-			label = "1. Start game"
-		case loginPlayer1:
-			if m.ctx.User1 != nil {
-				label = fmt.Sprintf("2. Sign out - %s", m.ctx.User1.Username)
-			} else {
-				label = "2. Sign in - player 1"
-			}
-		case loginPlayer2:
-			if m.ctx.User2 != nil {
-				label = fmt.Sprintf("3. Sign out - %s", m.ctx.User2.Username)
-			} else {
-				label = "3. Sign in - player 2"
-			}
+			label = "Start game"
+		case resumeGame:
+			label = "Resume game"
+		case signIn:
+			label = "Sign in"
+		case signOut:
+			label = "Sign out"
 		case registerUser:
-			label = "4. Register user"
+			label = "Register user"
+		case manageAccount:
+			label = "Account"
 		case viewStats:
-			label = "5. Stats"
+			label = "Stats"
+		case viewLeaderboard:
+			label = "Leaderboard"
+		case viewHistory:
+			label = "Game history"
+		case viewTournaments:
+			label = "Tournaments"
+		case viewMatches:
+			label = "Matches"
 		case viewHelp:
-			label = "6. Help"
+			label = "Help"
 		case quit:
-			label = "7. Quit"
+			label = "Quit"
+		}
+		if key := menuKey(i); key != "" {
+			label = fmt.Sprintf("%s. %s", key, label)
 		}
 
-		if i == int(m.focusIndex) {
+		if i == m.focusIndex {
 			s += highlightStyle.Render(label) + "\n"
 		} else {
 			s += buttonStyle.Render(label) + "\n"
 		}
 	}
 
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
 	s += "\nUse up/down arrows to navigate, enter to select.\n"
 	s += "Alternatively, press the number key for the option.\n"
-	s += "Press 7, q, esc or ctrl+c to quit.\n"
+	if key := menuKey(len(m.fields) - 1); key != "" {
+		s += fmt.Sprintf("Press %s, q, esc or ctrl+c to quit.\n", key)
+	} else {
+		s += "Press q, esc or ctrl+c to quit.\n"
+	}
 
 	return s
 }
@@ -0,0 +1,180 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+// seatPlayers seats white and black for the next game, swapping the colors
+// when swap is set. Either player may be nil for a guest.
+func seatPlayers(ctx *app.Context, white, black *app.User, swap bool) error {
+	if ctx == nil {
+		return fmt.Errorf("context is nil")
+	}
+	if white != nil && white == black {
+		return fmt.Errorf("%s cannot play both colors", white.Username)
+	}
+
+	if swap {
+		white, black = black, white
+	}
+	ctx.User1 = white
+	ctx.User2 = black
+
+	return nil
+}
+
+type newGameField int
+
+const (
+	whiteField newGameField = iota
+	blackField
+	randomColorsField
+	startField
+)
+
+type newGameModel struct {
+	ctx        *app.Context
+	focusIndex newGameField
+	// white and black index choices(), 0 being the guest.
+	white        int
+	black        int
+	randomColors bool
+	err          error
+}
+
+func SetupNewGame(ctx *app.Context) tea.Model {
+	if ctx == nil {
+		panic("SetupNewGame called with nil ctx")
+	}
+
+	m := newGameModel{ctx: ctx}
+	for i, user := range ctx.Roster {
+		if user == ctx.User1 {
+			m.white = i + 1
+		}
+		if user == ctx.User2 {
+			m.black = i + 1
+		}
+	}
+
+	return &m
+}
+
+// choices lists the players who can take a color: a guest followed by the
+// roster.
+func (m *newGameModel) choices() []*app.User {
+	return append([]*app.User{nil}, m.ctx.Roster...)
+}
+
+func choiceLabel(user *app.User) string {
+	if user == nil {
+		return "Guest"
+	}
+	return user.Username
+}
+
+func (m *newGameModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *newGameModel) cycle(delta int) {
+	n := len(m.choices())
+	switch m.focusIndex {
+	case whiteField:
+		m.white = (m.white + n + delta) % n
+	case blackField:
+		m.black = (m.black + n + delta) % n
+	case randomColorsField:
+		m.randomColors = !m.randomColors
+	}
+	m.err = nil
+}
+
+func (m *newGameModel) start() tea.Cmd {
+	choices := m.choices()
+	swap := m.randomColors && rand.IntN(2) == 1
+
+	m.err = seatPlayers(m.ctx, choices[m.white], choices[m.black], swap)
+	if m.err != nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return messages.SwitchToGame{}
+	}
+}
+
+func (m *newGameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		case "up", "shift+tab":
+			m.focusIndex = (m.focusIndex + startField) % (startField + 1)
+		case "down", "tab":
+			m.focusIndex = (m.focusIndex + 1) % (startField + 1)
+		case "left":
+			m.cycle(-1)
+		case "right", " ":
+			m.cycle(1)
+		case "enter":
+			if m.focusIndex == startField {
+				return m, m.start()
+			}
+			if m.focusIndex == randomColorsField {
+				m.cycle(1)
+				return m, nil
+			}
+			m.focusIndex++
+		}
+	case error:
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *newGameModel) View() string {
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	choices := m.choices()
+	checkbox := "[ ] Random colors"
+	if m.randomColors {
+		checkbox = "[x] Random colors"
+	}
+	labels := []string{
+		fmt.Sprintf("White: < %s >", choiceLabel(choices[m.white])),
+		fmt.Sprintf("Black: < %s >", choiceLabel(choices[m.black])),
+		checkbox,
+		"[ Start ]",
+	}
+
+	s := "New Game\n\n"
+	for i, label := range labels {
+		if newGameField(i) == m.focusIndex {
+			s += highlightStyle.Render(label) + "\n"
+		} else {
+			s += buttonStyle.Render(label) + "\n"
+		}
+	}
+
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nUse up/down arrows to navigate, left/right to change a player.\n"
+	s += "Press esc to return to main menu.\n"
+
+	return s
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+)
+
+func TestSeatPlayers(t *testing.T) {
+	alice := &app.User{ID: "alice-id", Username: "Alice"}
+	bob := &app.User{ID: "bob-id", Username: "Bob"}
+	carol := &app.User{ID: "carol-id", Username: "Carol"}
+
+	tests := []struct {
+		name    string
+		white   *app.User
+		black   *app.User
+		swap    bool
+		want1   *app.User
+		want2   *app.User
+		wantErr bool
+	}{
+		{"two players", alice, carol, false, alice, carol, false},
+		{"swapped colors", alice, carol, true, carol, alice, false},
+		{"guest as black", bob, nil, false, bob, nil, false},
+		{"guest swapped", bob, nil, true, nil, bob, false},
+		{"two guests", nil, nil, false, nil, nil, false},
+		{"same player", alice, alice, false, nil, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := &app.Context{}
+			for _, user := range []*app.User{alice, bob, carol} {
+				ctx.SignIn(user)
+			}
+
+			err := seatPlayers(ctx, test.white, test.black, test.swap)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				if ctx.User1 != alice || ctx.User2 != bob {
+					t.Errorf("Expected the seats to stay unchanged, got %+v and %+v", ctx.User1, ctx.User2)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("seatPlayers failed: %v", err)
+			}
+			if ctx.User1 != test.want1 || ctx.User2 != test.want2 {
+				t.Errorf("Expected %+v vs %+v, got %+v vs %+v", test.want1, test.want2, ctx.User1, ctx.User2)
+			}
+		})
+	}
+}
+
+func TestNewGameDefaultsToSeats(t *testing.T) {
+	alice := &app.User{ID: "alice-id", Username: "Alice"}
+	bob := &app.User{ID: "bob-id", Username: "Bob"}
+	ctx := &app.Context{}
+	ctx.SignIn(alice)
+	ctx.SignIn(bob)
+	ctx.SignOut(alice)
+
+	m := SetupNewGame(ctx).(*newGameModel)
+	choices := m.choices()
+	if choices[m.white] != nil || choices[m.black] != bob {
+		t.Errorf("Expected guest vs Bob, got %+v vs %+v", choices[m.white], choices[m.black])
+	}
+	if len(ctx.Roster) != 1 || ctx.Roster[0] != bob {
+		t.Errorf("Expected only Bob on the roster, got %+v", ctx.Roster)
+	}
+}
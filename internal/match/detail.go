@@ -0,0 +1,166 @@
+package match
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+type matchModel struct {
+	ctx   *app.Context
+	id    string
+	state *matchState
+	err   error
+}
+
+func SetupMatch(ctx *app.Context, id string) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupMatch called with nil ctx or nil ctx.Store")
+	}
+
+	m := matchModel{
+		ctx: ctx,
+		id:  id,
+	}
+	m.load()
+
+	return &m
+}
+
+// load reads the match, marking it finished once it is decided.
+func (m *matchModel) load() {
+	state, err := loadMatch(m.ctx, m.id)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.state = state
+	m.err = finishMatch(m.ctx, state, time.Now())
+}
+
+func (m *matchModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *matchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg {
+				return messages.SwitchToMatches{}
+			}
+		case "enter":
+			if m.state != nil {
+				return m, m.play()
+			}
+		}
+	case error:
+		m.ctx.Pairing = nil
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+// play starts the next game of the match, or resumes the one that was
+// interrupted.
+func (m *matchModel) play() tea.Cmd {
+	game, err := nextGame(m.ctx, m.state, func() bool {
+		return rand.IntN(2) == 0
+	})
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.ctx.Pairing = m.state.gamePairing(game)
+
+	if game.GameID.Valid {
+		_, err := m.ctx.Store.GetSavedGame(context.Background(), game.GameID.String)
+		if err == nil {
+			gameID := game.GameID.String
+			return func() tea.Msg {
+				return messages.SwitchToResumeGame{GameID: gameID}
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		return messages.SwitchToGame{}
+	}
+}
+
+func (m *matchModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.state == nil {
+		s := "Match\n\n"
+		if m.err != nil {
+			s += errStyle.Render(m.err.Error()) + "\n"
+		}
+		return s + "\nPress esc to go back.\n"
+	}
+
+	match := m.state.match
+	s := fmt.Sprintf("%s vs %s - %d games", match.Player1Name, match.Player2Name, match.Games)
+	if match.Armageddon {
+		s += ", Armageddon if tied"
+	}
+	s += "\n\n" + m.state.scoreLine() + "\n\n"
+
+	for _, game := range m.state.games {
+		players := fmt.Sprintf("%s - %s", m.state.playerName(game.Player1White), m.state.playerName(!game.Player1White))
+		label := fmt.Sprintf("%d. %s", game.Number, players)
+		if game.Armageddon {
+			label = "Armageddon: " + players
+		}
+		result := "in progress"
+		if game.Result.Valid {
+			result = game.Result.String
+		}
+		s += buttonStyle.Render(fmt.Sprintf("%s  %s", label, result)) + "\n"
+	}
+	if len(m.state.games) > 0 {
+		s += "\n"
+	}
+
+	winner, over := m.state.outcome()
+	switch {
+	case over && winner == 0:
+		s += "The match is drawn.\n"
+	case over:
+		s += fmt.Sprintf("%s wins the match!\n", m.state.playerName(winner == 1))
+	default:
+		current, ok := m.state.current()
+		number := int64(len(m.state.games) + 1)
+		action := "Play"
+		if ok {
+			number = current.Number
+			action = "Continue"
+		}
+		label := fmt.Sprintf("[ %s game %d ]", action, number)
+		if number > match.Games {
+			label = fmt.Sprintf("[ %s the Armageddon game ]", action)
+		}
+		s += highlightStyle.Render(label) + "\n"
+	}
+
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+	if !over {
+		s += "\nPress enter to play, esc to go back.\n"
+	} else {
+		s += "\nPress esc to go back.\n"
+	}
+
+	return s
+}
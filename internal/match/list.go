@@ -0,0 +1,256 @@
+package match
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+type createField int
+
+const (
+	player1Field createField = iota
+	player2Field
+	gamesField
+	armageddonField
+	startField
+)
+
+type matchesModel struct {
+	ctx     *app.Context
+	matches []database.Match
+	// focusIndex 0 is the new match entry, the matches follow.
+	focusIndex int
+	confirming bool
+	creating   bool
+	field      createField
+	// player1 and player2 index choices(), 0 being a guest.
+	player1    int
+	player2    int
+	games      int64
+	armageddon bool
+	err        error
+}
+
+func SetupMatches(ctx *app.Context) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupMatches called with nil ctx or nil ctx.Store")
+	}
+
+	m := matchesModel{
+		ctx:   ctx,
+		games: defaultGames,
+	}
+	for i, user := range ctx.Roster {
+		if user == ctx.User1 {
+			m.player1 = i + 1
+		}
+		if user == ctx.User2 {
+			m.player2 = i + 1
+		}
+	}
+	m.load()
+
+	return &m
+}
+
+func (m *matchesModel) load() {
+	matches, err := m.ctx.Store.ListMatches(context.Background())
+	if err != nil {
+		m.err = fmt.Errorf("failed to list matches: %w", err)
+		return
+	}
+	m.matches = matches
+	m.focusIndex = min(m.focusIndex, len(m.matches))
+}
+
+// choices lists the players who can play a match: a guest followed by the
+// roster.
+func (m *matchesModel) choices() []*app.User {
+	return append([]*app.User{nil}, m.ctx.Roster...)
+}
+
+func choiceLabel(user *app.User) string {
+	if user == nil {
+		return "Guest"
+	}
+	return user.Username
+}
+
+func (m *matchesModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *matchesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.err = deleteMatch(m.ctx, m.matches[m.focusIndex-1].ID)
+				m.load()
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		if m.creating {
+			return m, m.updateCreate(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		case "up":
+			m.focusIndex = (m.focusIndex + len(m.matches)) % (len(m.matches) + 1)
+		case "down":
+			m.focusIndex = (m.focusIndex + 1) % (len(m.matches) + 1)
+		case "d":
+			if m.focusIndex > 0 {
+				m.confirming = true
+			}
+		case "enter":
+			if m.focusIndex == 0 {
+				m.creating = true
+				m.field = player1Field
+				m.err = nil
+				return m, nil
+			}
+			id := m.matches[m.focusIndex-1].ID
+			return m, func() tea.Msg {
+				return messages.SwitchToMatch{ID: id}
+			}
+		}
+	case error:
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *matchesModel) cycle(delta int) {
+	n := len(m.choices())
+	switch m.field {
+	case player1Field:
+		m.player1 = (m.player1 + n + delta) % n
+	case player2Field:
+		m.player2 = (m.player2 + n + delta) % n
+	case gamesField:
+		m.games = min(max(m.games+int64(delta), minGames), maxGames)
+	case armageddonField:
+		m.armageddon = !m.armageddon
+	}
+	m.err = nil
+}
+
+func (m *matchesModel) updateCreate(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.creating = false
+		m.err = nil
+	case "up", "shift+tab":
+		m.field = (m.field + startField) % (startField + 1)
+	case "down", "tab":
+		m.field = (m.field + 1) % (startField + 1)
+	case "left":
+		m.cycle(-1)
+	case "right", " ":
+		m.cycle(1)
+	case "enter":
+		switch m.field {
+		case startField:
+			choices := m.choices()
+			match, err := createMatch(m.ctx, choices[m.player1], choices[m.player2], m.games, m.armageddon, time.Now())
+			if err != nil {
+				m.err = err
+				return nil
+			}
+			return func() tea.Msg {
+				return messages.SwitchToMatch{ID: match.ID}
+			}
+		case armageddonField:
+			m.cycle(1)
+		default:
+			m.field++
+		}
+	}
+
+	return nil
+}
+
+func (m *matchesModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.creating {
+		choices := m.choices()
+		checkbox := "[ ] Armageddon if tied"
+		if m.armageddon {
+			checkbox = "[x] Armageddon if tied"
+		}
+		labels := []string{
+			fmt.Sprintf("Player 1: < %s >", choiceLabel(choices[m.player1])),
+			fmt.Sprintf("Player 2: < %s >", choiceLabel(choices[m.player2])),
+			fmt.Sprintf("Games: < %d >", m.games),
+			checkbox,
+			"[ Start ]",
+		}
+
+		s := "New Match\n\n"
+		for i, label := range labels {
+			if createField(i) == m.field {
+				s += highlightStyle.Render(label) + "\n"
+			} else {
+				s += buttonStyle.Render(label) + "\n"
+			}
+		}
+		if m.err != nil {
+			s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+		}
+		s += "\nPlayer 1 has white in odd games. The first to score more than half the games wins.\n"
+		s += "Use up/down arrows to navigate, left/right to change a setting.\n"
+		s += "Press esc to go back.\n"
+		return s
+	}
+
+	s := "Matches\n\n"
+	labels := []string{"[ New match ]"}
+	for _, match := range m.matches {
+		status := "in progress"
+		if match.FinishedAt.Valid {
+			status = "finished"
+		}
+		labels = append(labels, fmt.Sprintf("%s vs %s - %d games, %s", match.Player1Name, match.Player2Name, match.Games, status))
+	}
+	for i, label := range labels {
+		if i == m.focusIndex {
+			s += highlightStyle.Render(label) + "\n"
+		} else {
+			s += buttonStyle.Render(label) + "\n"
+		}
+	}
+
+	if m.confirming {
+		match := m.matches[m.focusIndex-1]
+		s += "\n" + errStyle.Render(fmt.Sprintf("Delete the match %s vs %s? Finished games stay in the history. (y/n)", match.Player1Name, match.Player2Name)) + "\n"
+	}
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nUse up/down arrows to navigate, enter to open, d to delete.\n"
+	s += "Press esc to return to main menu.\n"
+
+	return s
+}
@@ -0,0 +1,275 @@
+package match
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/google/uuid"
+)
+
+const (
+	minGames     = 1
+	maxGames     = 15
+	defaultGames = 3
+)
+
+// matchState is a match with its games in order.
+type matchState struct {
+	match database.Match
+	games []database.MatchGame
+}
+
+func loadMatch(ctx *app.Context, id string) (*matchState, error) {
+	if ctx == nil || ctx.Store == nil {
+		return nil, fmt.Errorf("context or Store is nil")
+	}
+
+	match, err := ctx.Store.GetMatch(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	games, err := ctx.Store.ListMatchGames(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match games: %w", err)
+	}
+
+	return &matchState{match: match, games: games}, nil
+}
+
+// player1Score returns the points player 1 scored in a finished game.
+func player1Score(game database.MatchGame) float64 {
+	score := results.WhiteScore(game.Result.String)
+	if !game.Player1White {
+		score = 1 - score
+	}
+	return score
+}
+
+// score returns both players' points from the finished games, leaving out
+// the Armageddon game.
+func (s *matchState) score() (float64, float64) {
+	var player1, player2 float64
+	for _, game := range s.games {
+		if game.Armageddon || !game.Result.Valid {
+			continue
+		}
+		score := player1Score(game)
+		player1 += score
+		player2 += 1 - score
+	}
+	return player1, player2
+}
+
+// outcome reports whether the match is over and who won it, 1 or 2 for the
+// players and 0 for a drawn match. A player who scored more than half the
+// games wins right away. A tie after all games is drawn, or goes to an
+// Armageddon game where a draw counts as a win for Black.
+func (s *matchState) outcome() (winner int, over bool) {
+	player1, player2 := s.score()
+	half := float64(s.match.Games) / 2
+	switch {
+	case player1 > half:
+		return 1, true
+	case player2 > half:
+		return 2, true
+	}
+
+	played := 0
+	var armageddon *database.MatchGame
+	for i, game := range s.games {
+		if game.Armageddon {
+			armageddon = &s.games[i]
+		} else if game.Result.Valid {
+			played++
+		}
+	}
+	if int64(played) < s.match.Games {
+		return 0, false
+	}
+	if !s.match.Armageddon {
+		return 0, true
+	}
+	if armageddon == nil || !armageddon.Result.Valid {
+		return 0, false
+	}
+
+	score := player1Score(*armageddon)
+	switch {
+	case score == 1:
+		return 1, true
+	case score == 0:
+		return 2, true
+	case armageddon.Player1White:
+		return 2, true
+	}
+	return 1, true
+}
+
+// current returns the game being played, which has no result yet.
+func (s *matchState) current() (database.MatchGame, bool) {
+	if n := len(s.games); n > 0 && !s.games[n-1].Result.Valid {
+		return s.games[n-1], true
+	}
+	return database.MatchGame{}, false
+}
+
+func (s *matchState) playerName(player1 bool) string {
+	if player1 {
+		return s.match.Player1Name
+	}
+	return s.match.Player2Name
+}
+
+func (s *matchState) playerUser(player1 bool) *app.User {
+	userID, name := s.match.Player1UserID, s.match.Player1Name
+	if !player1 {
+		userID, name = s.match.Player2UserID, s.match.Player2Name
+	}
+	if !userID.Valid {
+		return nil
+	}
+	return &app.User{ID: userID.String, Username: name}
+}
+
+// scoreLine shows the running score, e.g. "Alice 1½ - ½ Bob".
+func (s *matchState) scoreLine() string {
+	player1, player2 := s.score()
+	return fmt.Sprintf("%s %s - %s %s", s.match.Player1Name, results.FormatPoints(player1), results.FormatPoints(player2), s.match.Player2Name)
+}
+
+// gamePairing seats the players of a match game for the board, with the
+// running score as its title.
+func (s *matchState) gamePairing(game database.MatchGame) *app.Pairing {
+	title := fmt.Sprintf("%s | Game %d of %d", s.scoreLine(), game.Number, s.match.Games)
+	if game.Armageddon {
+		title = fmt.Sprintf("%s | Armageddon: a draw wins the match for Black", s.scoreLine())
+	}
+
+	return &app.Pairing{
+		ID:        game.ID,
+		MatchID:   s.match.ID,
+		White:     s.playerUser(game.Player1White),
+		Black:     s.playerUser(!game.Player1White),
+		WhiteName: s.playerName(game.Player1White),
+		BlackName: s.playerName(!game.Player1White),
+		Title:     title,
+	}
+}
+
+func playerLabel(user *app.User, guest string) (sql.NullString, string) {
+	if user == nil {
+		return sql.NullString{}, guest
+	}
+	return sql.NullString{String: user.ID, Valid: true}, user.Username
+}
+
+// createMatch creates a match of games between two players, nil for a
+// guest.
+func createMatch(ctx *app.Context, player1, player2 *app.User, games int64, armageddon bool, now time.Time) (database.Match, error) {
+	if ctx == nil || ctx.Store == nil {
+		return database.Match{}, fmt.Errorf("context or Store is nil")
+	}
+	if player1 != nil && player2 != nil && player1.ID == player2.ID {
+		return database.Match{}, fmt.Errorf("%s cannot play both sides", player1.Username)
+	}
+	if games < minGames || games > maxGames {
+		return database.Match{}, fmt.Errorf("a match has %d to %d games", minGames, maxGames)
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return database.Match{}, fmt.Errorf("failed to generate match ID: %w", err)
+	}
+
+	player1ID, player1Name := playerLabel(player1, "Guest 1")
+	player2ID, player2Name := playerLabel(player2, "Guest 2")
+	match, err := ctx.Store.CreateMatch(context.Background(), database.CreateMatchParams{
+		ID:            id.String(),
+		Player1UserID: player1ID,
+		Player2UserID: player2ID,
+		Player1Name:   player1Name,
+		Player2Name:   player2Name,
+		Games:         games,
+		Armageddon:    armageddon,
+		CreatedAt:     now.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return database.Match{}, fmt.Errorf("failed to create match: %w", err)
+	}
+
+	return match, nil
+}
+
+// nextGame returns the game to play: the unfinished one, or a new game.
+// Player 1 has white in odd games; the colors of the Armageddon game are
+// decided by coin.
+func nextGame(ctx *app.Context, state *matchState, coin func() bool) (database.MatchGame, error) {
+	if _, over := state.outcome(); over {
+		return database.MatchGame{}, fmt.Errorf("match is over")
+	}
+	if game, ok := state.current(); ok {
+		return game, nil
+	}
+
+	number := int64(len(state.games) + 1)
+	params := database.CreateMatchGameParams{
+		MatchID:      state.match.ID,
+		Number:       number,
+		Player1White: number%2 == 1,
+	}
+	if number > state.match.Games {
+		params.Armageddon = true
+		params.Player1White = coin()
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return database.MatchGame{}, fmt.Errorf("failed to generate game ID: %w", err)
+	}
+	params.ID = id.String()
+
+	game, err := ctx.Store.CreateMatchGame(context.Background(), params)
+	if err != nil {
+		return database.MatchGame{}, fmt.Errorf("failed to create match game: %w", err)
+	}
+	state.games = append(state.games, game)
+
+	return game, nil
+}
+
+// finishMatch marks a match that is over as finished.
+func finishMatch(ctx *app.Context, state *matchState, now time.Time) error {
+	if _, over := state.outcome(); !over || state.match.FinishedAt.Valid {
+		return nil
+	}
+
+	finishedAt := sql.NullString{String: now.Format(time.RFC3339), Valid: true}
+	err := ctx.Store.FinishMatch(context.Background(), database.FinishMatchParams{
+		FinishedAt: finishedAt,
+		ID:         state.match.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish match: %w", err)
+	}
+	state.match.FinishedAt = finishedAt
+
+	return nil
+}
+
+func deleteMatch(ctx *app.Context, id string) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+
+	err := ctx.Store.DeleteMatch(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete match: %w", err)
+	}
+
+	return nil
+}
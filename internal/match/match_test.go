@@ -0,0 +1,181 @@
+package match
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/deskdaniel/GoMate/internal/storage/storagetest"
+)
+
+func TestCreateMatch(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+	alice := storagetest.RegisterUser(t, ctx.Store, "Alice-id", "Alice")
+
+	tests := []struct {
+		name    string
+		player1 *app.User
+		player2 *app.User
+		games   int64
+		wantErr bool
+	}{
+		{"registered player and guest", alice, nil, 3, false},
+		{"two guests", nil, nil, maxGames, false},
+		{"same player twice", alice, &app.User{ID: alice.ID, Username: "Alice"}, 3, true},
+		{"no games", alice, nil, minGames - 1, true},
+		{"too many games", alice, nil, maxGames + 1, true},
+	}
+	for _, test := range tests {
+		match, err := createMatch(ctx, test.player1, test.player2, test.games, true, time.Now())
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: createMatch failed: %v", test.name, err)
+			continue
+		}
+		if match.Player2Name != "Guest 2" || match.Player2UserID.Valid || match.Games != test.games {
+			t.Errorf("%s: unexpected match %+v", test.name, match)
+		}
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name       string
+		games      int64
+		armageddon bool
+		// results are from player 1's side: 1 win, 0 loss, 0.5 draw. The
+		// last one is the Armageddon game when the match has more.
+		results []float64
+		winner  int
+		over    bool
+	}{
+		{"not started", 3, false, nil, 0, false},
+		{"clinched early", 3, false, []float64{1, 1}, 1, true},
+		{"still open", 3, false, []float64{1, 0.5}, 0, false},
+		{"player 2 wins", 4, false, []float64{0, 0.5, 0, 1}, 2, true},
+		{"drawn match", 2, false, []float64{1, 0}, 0, true},
+		{"tie goes to Armageddon", 2, true, []float64{1, 0}, 0, false},
+		{"Armageddon won", 2, true, []float64{1, 0, 0}, 2, true},
+		// Player 1 has white in every game, so a drawn Armageddon game
+		// goes to player 2.
+		{"Armageddon drawn", 2, true, []float64{0.5, 0.5, 0.5}, 2, true},
+	}
+
+	for _, test := range tests {
+		state := &matchState{match: database.Match{Games: test.games, Armageddon: test.armageddon}}
+		for i, score := range test.results {
+			number := int64(i + 1)
+			result := results.WhiteWins
+			switch score {
+			case 0:
+				result = results.BlackWins
+			case 0.5:
+				result = results.Draw
+			}
+			state.games = append(state.games, database.MatchGame{
+				Number:       number,
+				Player1White: true,
+				Armageddon:   number > test.games,
+				Result:       sql.NullString{String: result, Valid: true},
+			})
+		}
+
+		winner, over := state.outcome()
+		if winner != test.winner || over != test.over {
+			t.Errorf("%s: expected winner %d and over %v, got %d and %v", test.name, test.winner, test.over, winner, over)
+		}
+	}
+}
+
+func TestPlayMatch(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+	alice := storagetest.RegisterUser(t, ctx.Store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, ctx.Store, "Bob-id", "Bob")
+
+	match, err := createMatch(ctx, alice, bob, 2, true, time.Now())
+	if err != nil {
+		t.Fatalf("createMatch failed: %v", err)
+	}
+
+	coin := func() bool { return false }
+	outcomes := []string{results.WhiteWins, results.WhiteWins, results.Draw}
+	wantWhite := []string{"Alice", "Bob", "Bob"}
+	for i, outcome := range outcomes {
+		state, err := loadMatch(ctx, match.ID)
+		if err != nil {
+			t.Fatalf("loadMatch failed: %v", err)
+		}
+		game, err := nextGame(ctx, state, coin)
+		if err != nil {
+			t.Fatalf("nextGame failed: %v", err)
+		}
+		again, err := nextGame(ctx, state, coin)
+		if err != nil || again.ID != game.ID {
+			t.Errorf("Expected an unfinished game to be continued, got %+v, %v", again, err)
+		}
+
+		ctx.Pairing = state.gamePairing(game)
+		if ctx.Pairing.WhiteName != wantWhite[i] || ctx.White() == nil || ctx.White().Username != wantWhite[i] {
+			t.Errorf("Game %d: expected %s to have white, got %+v", i+1, wantWhite[i], ctx.Pairing)
+		}
+		if game.Armageddon != (i == 2) || !strings.Contains(ctx.Pairing.Title, "Alice") {
+			t.Errorf("Game %d: unexpected game %+v with title %q", i+1, game, ctx.Pairing.Title)
+		}
+
+		err = results.Record(ctx, database.CreateGameParams{
+			ID:          fmt.Sprintf("game-%d", i),
+			WhiteUserID: sql.NullString{String: ctx.White().ID, Valid: true},
+			BlackUserID: sql.NullString{String: ctx.Black().ID, Valid: true},
+			WhiteName:   ctx.Pairing.WhiteName,
+			BlackName:   ctx.Pairing.BlackName,
+			Result:      outcome,
+			Termination: "checkmate",
+			FinalFen:    "fen",
+		})
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		ctx.Pairing = nil
+	}
+
+	state, err := loadMatch(ctx, match.ID)
+	if err != nil {
+		t.Fatalf("loadMatch failed: %v", err)
+	}
+	if got := state.scoreLine(); got != "Alice 1 - 1 Bob" {
+		t.Errorf("Expected the Armageddon game not to count in the score, got %q", got)
+	}
+	// Alice had black in the drawn Armageddon game.
+	winner, over := state.outcome()
+	if winner != 1 || !over {
+		t.Errorf("Expected Alice to win the match, got winner %d, over %v", winner, over)
+	}
+	_, err = nextGame(ctx, state, coin)
+	if err == nil {
+		t.Error("Expected no game after the match is over")
+	}
+
+	err = finishMatch(ctx, state, time.Now())
+	if err != nil {
+		t.Fatalf("finishMatch failed: %v", err)
+	}
+	stored, err := ctx.Store.GetMatch(context.Background(), match.ID)
+	if err != nil {
+		t.Fatalf("GetMatch failed: %v", err)
+	}
+	if !stored.FinishedAt.Valid {
+		t.Error("Expected the match to be marked finished")
+	}
+}
@@ -4,14 +4,45 @@ type SwitchToMainMenu struct{}
 
 type SwitchToGame struct{}
 
-type SwitchToLoginPlayer struct {
-	Slot int
+type SwitchToNewGame struct{}
+
+type SwitchToResumeGame struct {
+	GameID string
 }
 
+type SwitchToLoginPlayer struct{}
+
+type SwitchToSignOut struct{}
+
 type SwitchToRegisterUser struct{}
 
+type SwitchToAccount struct{}
+
 type SwitchToStats struct{}
 
+type SwitchToLeaderboard struct{}
+
+type SwitchToGameHistory struct {
+	Username string
+}
+
+type SwitchToReplay struct {
+	GameID   string
+	Username string
+}
+
+type SwitchToTournaments struct{}
+
+type SwitchToTournament struct {
+	ID string
+}
+
+type SwitchToMatches struct{}
+
+type SwitchToMatch struct {
+	ID string
+}
+
 type SwitchToHelp struct{}
 
 type SwitchToQuit struct{}
@@ -8,8 +8,10 @@ import (
 	"github.com/deskdaniel/GoMate/internal/board"
 	"github.com/deskdaniel/GoMate/internal/game"
 	"github.com/deskdaniel/GoMate/internal/help"
+	"github.com/deskdaniel/GoMate/internal/match"
 	"github.com/deskdaniel/GoMate/internal/messages"
 	"github.com/deskdaniel/GoMate/internal/player"
+	"github.com/deskdaniel/GoMate/internal/tournament"
 )
 
 type navigationModel struct {
@@ -56,25 +58,85 @@ func (m navigationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentModel = board.NewBoardModel(m.ctx)
 		m.viewport.SetContent(m.renderWrappedContent())
 		return m, nil
+	case messages.SwitchToNewGame:
+		m.currentModel = game.SetupNewGame(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToResumeGame:
+		newModel, err := board.ResumeBoardModel(m.ctx, msg.GameID)
+		if err != nil {
+			var cmd tea.Cmd
+			m.currentModel, cmd = m.currentModel.Update(err)
+			m.viewport.SetContent(m.renderWrappedContent())
+			return m, cmd
+		}
+		m.currentModel = newModel
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
 	case messages.SwitchToMainMenu:
+		m.ctx.Pairing = nil
 		m.currentModel = game.SetupMainMenu(m.ctx)
 		m.viewport.SetContent(m.renderWrappedContent())
 		return m, nil
 	case messages.SwitchToLoginPlayer:
-		newModel := player.SetupLogin(m.ctx, msg.Slot)
-		if newModel != nil {
-			m.currentModel = newModel
-			m.viewport.SetContent(m.renderWrappedContent())
-		}
+		m.currentModel = player.SetupLogin(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToSignOut:
+		m.currentModel = player.SetupSignOut(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
 		return m, nil
 	case messages.SwitchToRegisterUser:
 		m.currentModel = player.SetupRegister(m.ctx)
 		m.viewport.SetContent(m.renderWrappedContent())
 		return m, nil
+	case messages.SwitchToAccount:
+		m.currentModel = player.SetupAccount(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
 	case messages.SwitchToStats:
 		m.currentModel = player.SetupStats(m.ctx)
 		m.viewport.SetContent(m.renderWrappedContent())
 		return m, nil
+	case messages.SwitchToLeaderboard:
+		m.currentModel = player.SetupLeaderboard(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToGameHistory:
+		m.currentModel = player.SetupGameHistory(m.ctx, msg.Username)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToReplay:
+		newModel, err := board.NewReplayModel(m.ctx, msg.GameID, msg.Username)
+		if err != nil {
+			var cmd tea.Cmd
+			m.currentModel, cmd = m.currentModel.Update(err)
+			m.viewport.SetContent(m.renderWrappedContent())
+			return m, cmd
+		}
+		m.currentModel = newModel
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToTournaments:
+		m.ctx.Pairing = nil
+		m.currentModel = tournament.SetupTournaments(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToTournament:
+		m.ctx.Pairing = nil
+		m.currentModel = tournament.SetupTournament(m.ctx, msg.ID)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToMatches:
+		m.ctx.Pairing = nil
+		m.currentModel = match.SetupMatches(m.ctx)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
+	case messages.SwitchToMatch:
+		m.ctx.Pairing = nil
+		m.currentModel = match.SetupMatch(m.ctx, msg.ID)
+		m.viewport.SetContent(m.renderWrappedContent())
+		return m, nil
 	case messages.SwitchToHelp:
 		m.currentModel = help.SetupHelp(m.ctx)
 		m.viewport.SetContent(m.renderWrappedContent())
@@ -0,0 +1,422 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// verifyAccount checks the password of the signed-in user. Failed checks
+// are throttled like failed logins, so a signed-in seat cannot be used to
+// guess the password.
+func verifyAccount(ctx *app.Context, user *app.User, password string) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+	if user == nil || ctx.SignedIn(user.Username) != user {
+		return fmt.Errorf("player is not signed in")
+	}
+
+	now := time.Now()
+	err := checkLoginThrottle(ctx, user.Username, now)
+	if err != nil {
+		return err
+	}
+
+	account, err := ctx.Store.GetUserByName(context.Background(), user.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	err = checkPasswordHash(password, account.HashedPassword)
+	if err != nil {
+		err = fmt.Errorf("invalid password")
+		throttleErr := recordFailedLogin(ctx, user.Username, now)
+		if throttleErr != nil {
+			return fmt.Errorf("%w: %w", err, throttleErr)
+		}
+		return err
+	}
+
+	err = ctx.Store.ClearLoginAttempts(context.Background(), user.Username)
+	if err != nil {
+		return fmt.Errorf("failed to clear login attempts: %w", err)
+	}
+
+	return nil
+}
+
+func changePassword(ctx *app.Context, user *app.User, current, password string) error {
+	err := verifyAccount(ctx, user, current)
+	if err != nil {
+		return err
+	}
+
+	err = checkPassword(password)
+	if err != nil {
+		return err
+	}
+
+	// Remembered logins were made with the old password, so they go with it.
+	return ctx.Store.WithTx(context.Background(), func(store storage.Store) error {
+		err := updatePassword(store, user.ID, password)
+		if err != nil {
+			return err
+		}
+
+		err = store.DeleteSessionsByUserID(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete sessions: %w", err)
+		}
+		return nil
+	})
+}
+
+func changeUsername(ctx *app.Context, user *app.User, password, username string) error {
+	err := verifyAccount(ctx, user, password)
+	if err != nil {
+		return err
+	}
+
+	err = CheckUsername(username)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctx.Store.GetUserByName(context.Background(), username)
+	if err == nil {
+		return fmt.Errorf("username already taken")
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check username availability: %w", err)
+	}
+
+	_, err = ctx.Store.UpdateUsername(context.Background(), database.UpdateUsernameParams{
+		Username:  username,
+		UpdatedAt: sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+		ID:        user.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update username: %w", err)
+	}
+
+	user.Username = username
+	return nil
+}
+
+// deleteAccount removes the account together with its record, rating
+// history, sessions and saved games, and signs the player out. Finished
+// games stay in the opponents' history.
+func deleteAccount(ctx *app.Context, user *app.User, password string) error {
+	err := verifyAccount(ctx, user, password)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Store.DeleteUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	ctx.SignOut(user)
+	return forgetPlayer(ctx, user)
+}
+
+type accountAction int
+
+const (
+	changePasswordAction accountAction = iota
+	changeUsernameAction
+	deleteAccountAction
+)
+
+var accountActions = []accountAction{changePasswordAction, changeUsernameAction, deleteAccountAction}
+
+func (a accountAction) String() string {
+	switch a {
+	case changeUsernameAction:
+		return "Change username"
+	case deleteAccountAction:
+		return "Delete account"
+	}
+	return "Change password"
+}
+
+type accountModel struct {
+	ctx        *app.Context
+	player     int
+	selected   int
+	action     *accountAction
+	inputs     []textinput.Model
+	focusIndex int
+	confirming bool
+	success    string
+	err        error
+}
+
+func SetupAccount(ctx *app.Context) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupAccount called with nil ctx or nil ctx.Store")
+	}
+
+	m := accountModel{
+		ctx: ctx,
+	}
+
+	return &m
+}
+
+// user returns the roster player whose account is managed, or nil.
+func (m *accountModel) user() *app.User {
+	if m.player >= len(m.ctx.Roster) {
+		return nil
+	}
+	return m.ctx.Roster[m.player]
+}
+
+func newAccountInput(prompt string, password bool) textinput.Model {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.CharLimit = 50
+	input.Width = 30
+	if password {
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '*'
+	} else {
+		input.CharLimit = MaxUsernameLength
+	}
+
+	return input
+}
+
+func (m *accountModel) openAction(action accountAction) tea.Cmd {
+	m.action = &action
+	m.focusIndex = 0
+	m.err = nil
+
+	switch action {
+	case changePasswordAction:
+		m.inputs = []textinput.Model{
+			newAccountInput("Current Password: ", true),
+			newAccountInput("New Password: ", true),
+			newAccountInput("Confirm Password: ", true),
+		}
+	case changeUsernameAction:
+		m.inputs = []textinput.Model{
+			newAccountInput("New Username: ", false),
+			newAccountInput("Password: ", true),
+		}
+	case deleteAccountAction:
+		m.inputs = []textinput.Model{
+			newAccountInput("Password: ", true),
+		}
+	}
+
+	return m.focusInputs()
+}
+
+func (m *accountModel) focusInputs() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range m.inputs {
+		if i == m.focusIndex {
+			cmd = m.inputs[i].Focus()
+			m.inputs[i].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("37"))
+			m.inputs[i].TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("37"))
+		} else {
+			m.inputs[i].Blur()
+			m.inputs[i].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			m.inputs[i].TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		}
+	}
+
+	return cmd
+}
+
+func (m *accountModel) submit() error {
+	user := m.user()
+	switch *m.action {
+	case changePasswordAction:
+		if m.inputs[1].Value() != m.inputs[2].Value() {
+			return fmt.Errorf("passwords do not match")
+		}
+		err := changePassword(m.ctx, user, m.inputs[0].Value(), m.inputs[1].Value())
+		if err != nil {
+			return err
+		}
+		m.success = "Password changed successfully!"
+	case changeUsernameAction:
+		err := changeUsername(m.ctx, user, m.inputs[1].Value(), m.inputs[0].Value())
+		if err != nil {
+			return err
+		}
+		m.success = fmt.Sprintf("Username changed to %s successfully!", m.inputs[0].Value())
+	case deleteAccountAction:
+		err := deleteAccount(m.ctx, user, m.inputs[0].Value())
+		if err != nil {
+			return err
+		}
+		m.success = fmt.Sprintf("Account %s deleted.", user.Username)
+	}
+
+	return nil
+}
+
+func (m *accountModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *accountModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.success != "" || m.user() == nil {
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		}
+
+		if msg.String() == "ctrl+c" {
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		}
+
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.err = m.submit()
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		if m.action == nil {
+			switch msg.String() {
+			case "esc", "q":
+				return m, func() tea.Msg {
+					return messages.SwitchToMainMenu{}
+				}
+			case "up":
+				m.selected = (m.selected + len(accountActions) - 1) % len(accountActions)
+			case "down":
+				m.selected = (m.selected + 1) % len(accountActions)
+			case "tab":
+				m.player = (m.player + 1) % len(m.ctx.Roster)
+			case "enter":
+				return m, m.openAction(accountActions[m.selected])
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			m.action = nil
+			m.err = nil
+			return m, nil
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if *m.action == deleteAccountAction {
+					m.confirming = true
+					return m, nil
+				}
+				m.err = m.submit()
+				return m, nil
+			}
+
+			if s == "up" || s == "shift+tab" {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
+			}
+			if m.focusIndex > len(m.inputs) {
+				m.focusIndex = 0
+			} else if m.focusIndex < 0 {
+				m.focusIndex = len(m.inputs)
+			}
+
+			return m, m.focusInputs()
+		}
+
+		var cmds []tea.Cmd
+		for i := range m.inputs {
+			var cmd tea.Cmd
+			m.inputs[i], cmd = m.inputs[i].Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+	case error:
+		m.err = msg
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *accountModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.success != "" {
+		return m.success + "\n\nPress any key to return to main menu.\n"
+	}
+
+	user := m.user()
+	if user == nil {
+		return "Sign in to manage your account.\n\nPress any key to return to main menu.\n"
+	}
+
+	s := fmt.Sprintf("Account - %s\n\n", user.Username)
+
+	if m.action == nil {
+		for i, action := range accountActions {
+			if i == m.selected {
+				s += highlightStyle.Render(action.String()) + "\n"
+			} else {
+				s += buttonStyle.Render(action.String()) + "\n"
+			}
+		}
+		if m.err != nil {
+			s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+		}
+
+		s += "\nUse up/down arrows to navigate, enter to select.\n"
+		if len(m.ctx.Roster) > 1 {
+			s += "Press tab to switch player.\n"
+		}
+		s += "Press esc to return to main menu.\n"
+		return s
+	}
+
+	s += m.action.String() + "\n\n"
+	for i := range m.inputs {
+		s += m.inputs[i].View() + "\n\n"
+	}
+
+	if m.focusIndex == len(m.inputs) {
+		buttonStyle = highlightStyle
+	}
+	s += buttonStyle.Render("[ Submit ]") + "\n"
+
+	if m.confirming {
+		s += "\n" + errStyle.Render(fmt.Sprintf("Delete account %s permanently? Your games stay in your opponents' history. (y/n)", user.Username)) + "\n"
+	}
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nPress Esc to go back.\n"
+
+	return s
+}
@@ -0,0 +1,141 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/results"
+)
+
+const untimed = "-"
+
+// terminationOrder lists terminations in the order they are shown. Unknown
+// ones come last.
+var terminationOrder = results.Terminations
+
+type groupRecord struct {
+	Name   string      `json:"name"`
+	Record colorRecord `json:"record"`
+}
+
+type breakdown struct {
+	Games         int           `json:"games"`
+	AsWhite       colorRecord   `json:"as_white"`
+	AsBlack       colorRecord   `json:"as_black"`
+	Terminations  []groupRecord `json:"terminations"`
+	TimeControls  []groupRecord `json:"time_controls"`
+	CurrentStreak int           `json:"current_streak"`
+	LongestStreak int           `json:"longest_streak"`
+	AverageLength float64       `json:"average_length"`
+}
+
+func (r colorRecord) String() string {
+	return fmt.Sprintf("%dW %dL %dD", r.Wins, r.Losses, r.Draws)
+}
+
+func addToGroup(groups []groupRecord, name, result string, white bool) []groupRecord {
+	for i := range groups {
+		if groups[i].Name == name {
+			groups[i].Record.count(result, white)
+			return groups
+		}
+	}
+
+	group := groupRecord{Name: name}
+	group.Record.count(result, white)
+	return append(groups, group)
+}
+
+func terminationRank(termination string) int {
+	rank := slices.Index(terminationOrder, termination)
+	if rank < 0 {
+		return len(terminationOrder)
+	}
+	return rank
+}
+
+// checkBreakdown summarizes every stored game of the user.
+func checkBreakdown(ctx *app.Context, userID string) (breakdown, error) {
+	if ctx == nil || ctx.Store == nil {
+		return breakdown{}, fmt.Errorf("context or Store is nil")
+	}
+
+	games, err := ctx.Store.ListGamesByUserID(context.Background(), sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		return breakdown{}, fmt.Errorf("failed to get games: %w", err)
+	}
+
+	var result breakdown
+	var fullMoves int
+	streak := 0
+	for i := len(games) - 1; i >= 0; i-- {
+		game := games[i]
+		white := game.WhiteUserID.String == userID
+
+		if white {
+			result.AsWhite.count(game.Result, true)
+		} else {
+			result.AsBlack.count(game.Result, false)
+		}
+		result.Terminations = addToGroup(result.Terminations, game.Termination, game.Result, white)
+		result.TimeControls = addToGroup(result.TimeControls, game.TimeControl, game.Result, white)
+
+		if (game.Result == results.WhiteWins && white) || (game.Result == results.BlackWins && !white) {
+			streak++
+			result.LongestStreak = max(result.LongestStreak, streak)
+		} else {
+			streak = 0
+		}
+
+		fullMoves += (len(strings.Fields(game.Moves)) + 1) / 2
+	}
+
+	result.Games = len(games)
+	result.CurrentStreak = streak
+	if result.Games > 0 {
+		result.AverageLength = float64(fullMoves) / float64(result.Games)
+	}
+
+	slices.SortStableFunc(result.Terminations, func(a, b groupRecord) int {
+		if rank := terminationRank(a.Name) - terminationRank(b.Name); rank != 0 {
+			return rank
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(result.TimeControls, func(a, b groupRecord) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func (b breakdown) view() string {
+	s := "By color:\n"
+	s += fmt.Sprintf("  White: %s\n", b.AsWhite)
+	s += fmt.Sprintf("  Black: %s\n\n", b.AsBlack)
+
+	s += "By termination:\n"
+	for _, group := range b.Terminations {
+		s += fmt.Sprintf("  %s: %s\n", group.Name, group.Record)
+	}
+	s += "\n"
+
+	s += "By time control:\n"
+	for _, group := range b.TimeControls {
+		name := group.Name
+		if name == untimed {
+			name = "untimed"
+		}
+		s += fmt.Sprintf("  %s: %s\n", name, group.Record)
+	}
+	s += "\n"
+
+	s += fmt.Sprintf("Win streak: %d (longest %d)\n", b.CurrentStreak, b.LongestStreak)
+	s += fmt.Sprintf("Average game length: %.1f moves\n\n", b.AverageLength)
+
+	return s
+}
@@ -1,18 +1,166 @@
 package player
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Argon2id parameters for new hashes, following the OWASP recommendation.
+const (
+	argon2Memory  = 19 * 1024
+	argon2Time    = 2
+	argon2Threads = 1
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+const argon2Prefix = "$argon2id$"
+
+// Limits on the parameters of stored hashes, so a crafted hash cannot make
+// a login panic or allocate unbounded memory. Memory is in KiB.
+const (
+	argon2MaxMemory  = 256 * 1024
+	argon2MaxTime    = 16
+	argon2MaxThreads = 16
+	argon2MinSaltLen = 8
+	bcryptMaxCost    = 16
+)
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+var currentArgon2Params = argon2Params{
+	memory:  argon2Memory,
+	time:    argon2Time,
+	threads: argon2Threads,
+}
+
+// hashPassword hashes password with Argon2id and encodes it in the PHC
+// string format, e.g. $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>.
 func hashPassword(password string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	salt := make([]byte, argon2SaltLen)
+	_, err := rand.Read(salt)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to generate salt: %w", err)
 	}
 
-	return string(hashed), nil
+	p := currentArgon2Params
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix,
+		argon2.Version,
+		p.memory,
+		p.time,
+		p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
+func decodeArgon2Hash(hash string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads)
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	if p.memory < 8*uint32(p.threads) || p.memory > argon2MaxMemory {
+		return p, nil, nil, fmt.Errorf("argon2id memory %d KiB is out of range", p.memory)
+	}
+	if p.time < 1 || p.time > argon2MaxTime {
+		return p, nil, nil, fmt.Errorf("argon2id time %d is out of range", p.time)
+	}
+	if p.threads < 1 || p.threads > argon2MaxThreads {
+		return p, nil, nil, fmt.Errorf("argon2id parallelism %d is out of range", p.threads)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+	if len(salt) < argon2MinSaltLen {
+		return p, nil, nil, fmt.Errorf("argon2id salt is too short")
+	}
+	if len(key) != argon2KeyLen {
+		return p, nil, nil, fmt.Errorf("argon2id key must be %d bytes, got %d", argon2KeyLen, len(key))
+	}
+
+	return p, salt, key, nil
+}
+
+// ValidatePasswordHash reports whether hash is an Argon2id or legacy bcrypt
+// hash this build can verify, with parameters within its limits.
+func ValidatePasswordHash(hash string) error {
+	if strings.HasPrefix(hash, argon2Prefix) {
+		_, _, _, err := decodeArgon2Hash(hash)
+		return err
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return fmt.Errorf("invalid password hash: %w", err)
+	}
+	if cost > bcryptMaxCost {
+		return fmt.Errorf("bcrypt cost %d is out of range", cost)
+	}
+	return nil
+}
+
+// checkPasswordHash verifies password against an Argon2id hash or a legacy
+// bcrypt hash.
 func checkPasswordHash(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if !strings.HasPrefix(hash, argon2Prefix) {
+		err := ValidatePasswordHash(hash)
+		if err != nil {
+			return err
+		}
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	p, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyLen)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return fmt.Errorf("password does not match")
+	}
+
+	return nil
+}
+
+// needsRehash reports whether hash was created with bcrypt or with
+// different Argon2id parameters than hashPassword uses now.
+func needsRehash(hash string) bool {
+	p, _, _, err := decodeArgon2Hash(hash)
+	return err != nil || p != currentArgon2Params
 }
@@ -0,0 +1,121 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+)
+
+type colorRecord struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+type headToHeadGame struct {
+	Date        string
+	White       string
+	Black       string
+	Result      string
+	Termination string
+}
+
+type headToHead struct {
+	Player   string
+	Opponent string
+	AsWhite  colorRecord
+	AsBlack  colorRecord
+	Games    []headToHeadGame
+}
+
+func (r colorRecord) add(other colorRecord) colorRecord {
+	return colorRecord{
+		Wins:   r.Wins + other.Wins,
+		Losses: r.Losses + other.Losses,
+		Draws:  r.Draws + other.Draws,
+	}
+}
+
+func (r *colorRecord) count(result string, white bool) {
+	switch {
+	case result == results.WhiteWins && white, result == results.BlackWins && !white:
+		r.Wins++
+	case result == results.WhiteWins, result == results.BlackWins:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+}
+
+// checkHeadToHead returns the record of player against opponent from
+// player's point of view.
+func checkHeadToHead(ctx *app.Context, player, opponent *app.User) (headToHead, error) {
+	if ctx == nil || ctx.Store == nil {
+		return headToHead{}, fmt.Errorf("context or Store is nil")
+	}
+	if player == nil || opponent == nil {
+		return headToHead{}, fmt.Errorf("both players must be logged in")
+	}
+
+	games, err := ctx.Store.ListGamesBetweenUsers(context.Background(), database.ListGamesBetweenUsersParams{
+		UserID:     sql.NullString{String: player.ID, Valid: true},
+		OpponentID: sql.NullString{String: opponent.ID, Valid: true},
+	})
+	if err != nil {
+		return headToHead{}, fmt.Errorf("failed to get games: %w", err)
+	}
+
+	result := headToHead{
+		Player:   player.Username,
+		Opponent: opponent.Username,
+	}
+	for _, game := range games {
+		if game.WhiteUserID.String == player.ID {
+			result.AsWhite.count(game.Result, true)
+		} else {
+			result.AsBlack.count(game.Result, false)
+		}
+
+		date := game.EndedAt
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		result.Games = append(result.Games, headToHeadGame{
+			Date:        date,
+			White:       game.WhiteName,
+			Black:       game.BlackName,
+			Result:      game.Result,
+			Termination: game.Termination,
+		})
+	}
+
+	return result, nil
+}
+
+func (h headToHead) view() string {
+	total := h.AsWhite.add(h.AsBlack)
+
+	s := fmt.Sprintf("%s vs %s:\n\n", h.Player, h.Opponent)
+	if len(h.Games) == 0 {
+		s += "No games played against each other yet.\n\n"
+		s += "Press any key to exit.\n"
+		return s
+	}
+
+	s += fmt.Sprintf("%-12s%6s%8s%7s\n", "", "Wins", "Losses", "Draws")
+	s += fmt.Sprintf("%-12s%6d%8d%7d\n", "As white", h.AsWhite.Wins, h.AsWhite.Losses, h.AsWhite.Draws)
+	s += fmt.Sprintf("%-12s%6d%8d%7d\n", "As black", h.AsBlack.Wins, h.AsBlack.Losses, h.AsBlack.Draws)
+	s += fmt.Sprintf("%-12s%6d%8d%7d\n\n", "Total", total.Wins, total.Losses, total.Draws)
+
+	s += "Games:\n"
+	for _, game := range h.Games {
+		s += fmt.Sprintf("%s  %s - %s  %s (%s)\n", game.Date, game.White, game.Black, game.Result, game.Termination)
+	}
+	s += "\nPress any key to exit.\n"
+
+	return s
+}
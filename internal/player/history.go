@@ -0,0 +1,337 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+const historyPageSize = 10
+
+type historyFilter int
+
+const (
+	allGames historyFilter = iota
+	wonGames
+	lostGames
+	drawnGames
+	whiteGames
+	blackGames
+)
+
+var historyFilters = []historyFilter{allGames, wonGames, lostGames, drawnGames, whiteGames, blackGames}
+
+func (f historyFilter) String() string {
+	switch f {
+	case wonGames:
+		return "Wins"
+	case lostGames:
+		return "Losses"
+	case drawnGames:
+		return "Draws"
+	case whiteGames:
+		return "As white"
+	case blackGames:
+		return "As black"
+	}
+	return "All"
+}
+
+type historyEntry struct {
+	ID          string
+	Date        string
+	Opponent    string
+	Color       string
+	Result      string
+	Moves       int
+	Termination string
+}
+
+func loadHistory(ctx *app.Context, username string) ([]historyEntry, error) {
+	if ctx == nil || ctx.Store == nil {
+		return nil, fmt.Errorf("context or Store is nil")
+	}
+
+	user, err := ctx.Store.GetUserByName(context.Background(), username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	games, err := ctx.Store.ListGamesByUserID(context.Background(), sql.NullString{String: user.ID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get games: %w", err)
+	}
+
+	entries := make([]historyEntry, 0, len(games))
+	for _, game := range games {
+		white := game.WhiteUserID.String == user.ID
+
+		entry := historyEntry{
+			ID:          game.ID,
+			Date:        game.EndedAt,
+			Opponent:    game.BlackName,
+			Color:       "white",
+			Moves:       (len(strings.Fields(game.Moves)) + 1) / 2,
+			Termination: game.Termination,
+		}
+		if len(entry.Date) > 10 {
+			entry.Date = entry.Date[:10]
+		}
+		if !white {
+			entry.Opponent = game.WhiteName
+			entry.Color = "black"
+		}
+
+		var record colorRecord
+		record.count(game.Result, white)
+		switch {
+		case record.Wins > 0:
+			entry.Result = "Win"
+		case record.Losses > 0:
+			entry.Result = "Loss"
+		default:
+			entry.Result = "Draw"
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// filterHistory keeps the games matching filter whose opponent, date or
+// termination contains query.
+func filterHistory(games []historyEntry, filter historyFilter, query string) []historyEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var result []historyEntry
+	for _, game := range games {
+		switch filter {
+		case wonGames:
+			if game.Result != "Win" {
+				continue
+			}
+		case lostGames:
+			if game.Result != "Loss" {
+				continue
+			}
+		case drawnGames:
+			if game.Result != "Draw" {
+				continue
+			}
+		case whiteGames:
+			if game.Color != "white" {
+				continue
+			}
+		case blackGames:
+			if game.Color != "black" {
+				continue
+			}
+		}
+
+		if query != "" &&
+			!strings.Contains(strings.ToLower(game.Opponent), query) &&
+			!strings.Contains(game.Date, query) &&
+			!strings.Contains(game.Termination, query) {
+			continue
+		}
+
+		result = append(result, game)
+	}
+
+	return result
+}
+
+type historyModel struct {
+	ctx         *app.Context
+	username    string
+	userInput   textinput.Model
+	search      textinput.Model
+	filterIndex int
+	games       []historyEntry
+	selected    int
+	err         error
+}
+
+func SetupGameHistory(ctx *app.Context, username string) tea.Model {
+	userInput := textinput.New()
+	userInput.Prompt = "Username: "
+	userInput.Placeholder = "Enter username"
+	userInput.CharLimit = MaxUsernameLength
+	userInput.Width = 30
+	if len(ctx.Roster) > 0 {
+		userInput.SetValue(ctx.Roster[0].Username)
+	}
+	userInput.Focus()
+
+	search := textinput.New()
+	search.Prompt = "Search: "
+	search.Placeholder = "opponent, date or termination"
+	search.CharLimit = 30
+	search.Width = 30
+
+	m := historyModel{
+		ctx:       ctx,
+		userInput: userInput,
+		search:    search,
+	}
+	if username != "" {
+		m.load(username)
+	}
+
+	return &m
+}
+
+func (m *historyModel) load(username string) {
+	games, err := loadHistory(m.ctx, username)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	m.err = nil
+	m.username = username
+	m.games = games
+	m.selected = 0
+	m.userInput.Blur()
+	m.search.Focus()
+}
+
+func (m *historyModel) visibleGames() []historyEntry {
+	return filterHistory(m.games, historyFilters[m.filterIndex], m.search.Value())
+}
+
+func (m *historyModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *historyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		}
+
+		if m.username == "" {
+			if msg.String() == "enter" {
+				m.load(strings.TrimSpace(m.userInput.Value()))
+				return m, nil
+			}
+
+			var cmd tea.Cmd
+			m.userInput, cmd = m.userInput.Update(msg)
+			return m, cmd
+		}
+
+		visible := m.visibleGames()
+		switch msg.String() {
+		case "up":
+			m.selected = max(m.selected-1, 0)
+			return m, nil
+		case "down":
+			m.selected = max(min(m.selected+1, len(visible)-1), 0)
+			return m, nil
+		case "tab", "shift+tab":
+			if msg.String() == "tab" {
+				m.filterIndex++
+			} else {
+				m.filterIndex--
+			}
+			if m.filterIndex >= len(historyFilters) {
+				m.filterIndex = 0
+			} else if m.filterIndex < 0 {
+				m.filterIndex = len(historyFilters) - 1
+			}
+			m.selected = 0
+			return m, nil
+		case "enter":
+			if m.selected >= len(visible) {
+				return m, nil
+			}
+			gameID := visible[m.selected].ID
+			username := m.username
+			return m, func() tea.Msg {
+				return messages.SwitchToReplay{GameID: gameID, Username: username}
+			}
+		}
+
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		m.selected = 0
+		return m, cmd
+	case error:
+		m.err = msg
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *historyModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.username == "" {
+		s := "Game History\n\n"
+		s += m.userInput.View() + "\n"
+		if m.err != nil {
+			s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+		}
+		s += "\nPress enter to show the games of this player, esc to return to the main menu.\n"
+		return s
+	}
+
+	s := fmt.Sprintf("Game History for %s\n\n", m.username)
+	s += m.search.View() + "\n"
+
+	s += "Filter: "
+	for i, filter := range historyFilters {
+		if i == m.filterIndex {
+			s += highlightStyle.Render(filter.String())
+		} else {
+			s += buttonStyle.Render(filter.String())
+		}
+		s += "  "
+	}
+	s += "\n\n"
+
+	visible := m.visibleGames()
+	if len(visible) == 0 {
+		s += "No games found.\n"
+	} else {
+		start := m.selected / historyPageSize * historyPageSize
+		end := min(start+historyPageSize, len(visible))
+
+		s += fmt.Sprintf("  %-11s%-21s%-7s%-6s%6s  %s\n", "Date", "Opponent", "Color", "Result", "Moves", "Termination")
+		for i := start; i < end; i++ {
+			game := visible[i]
+			line := fmt.Sprintf("%-11s%-21s%-7s%-6s%6d  %s", game.Date, game.Opponent, game.Color, game.Result, game.Moves, game.Termination)
+			if i == m.selected {
+				s += highlightStyle.Render("> "+line) + "\n"
+			} else {
+				s += "  " + line + "\n"
+			}
+		}
+		s += fmt.Sprintf("\nGame %d of %d\n", m.selected+1, len(visible))
+	}
+
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nType to search, tab to change filter, up/down to select, enter to replay.\n"
+	s += "Press esc or ctrl+c to return to the main menu.\n"
+
+	return s
+}
@@ -0,0 +1,261 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/config"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+const leaderboardPageSize = 10
+
+type leaderboardColumn int
+
+const (
+	playerColumn leaderboardColumn = iota
+	ratingColumn
+	gamesColumn
+	winsColumn
+	lossesColumn
+	drawsColumn
+	winPercentageColumn
+)
+
+var leaderboardColumns = []leaderboardColumn{
+	playerColumn,
+	ratingColumn,
+	gamesColumn,
+	winsColumn,
+	lossesColumn,
+	drawsColumn,
+	winPercentageColumn,
+}
+
+type leaderboardEntry struct {
+	Rank          int
+	Username      string
+	Rating        int
+	Games         int
+	Wins          int
+	Losses        int
+	Draws         int
+	WinPercentage float64
+}
+
+type leaderboardPage struct {
+	Entries []leaderboardEntry
+	Page    int
+	Pages   int
+}
+
+func sortKey(column leaderboardColumn, ratingSystem string) string {
+	switch column {
+	case ratingColumn:
+		if ratingSystem == config.RatingSystemGlicko2 {
+			return "glicko_rating"
+		}
+		return "rating"
+	case gamesColumn:
+		return "games"
+	case winsColumn:
+		return "wins"
+	case lossesColumn:
+		return "losses"
+	case drawsColumn:
+		return "draws"
+	case winPercentageColumn:
+		return "win_percentage"
+	}
+
+	return "username"
+}
+
+func loadLeaderboard(ctx *app.Context, column leaderboardColumn, page int) (leaderboardPage, error) {
+	if ctx == nil || ctx.Store == nil {
+		return leaderboardPage{}, fmt.Errorf("context or Store is nil")
+	}
+
+	count, err := ctx.Store.CountUsers(context.Background())
+	if err != nil {
+		return leaderboardPage{}, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	pages := max(int((count+leaderboardPageSize-1)/leaderboardPageSize), 1)
+	page = min(max(page, 0), pages-1)
+
+	settings := ctx.Settings()
+	rows, err := ctx.Store.ListLeaderboard(context.Background(), database.ListLeaderboardParams{
+		SortBy:     sortKey(column, settings.RatingSystem),
+		PageSize:   leaderboardPageSize,
+		PageOffset: int64(page * leaderboardPageSize),
+	})
+	if err != nil {
+		return leaderboardPage{}, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	result := leaderboardPage{
+		Page:  page,
+		Pages: pages,
+	}
+	for i, row := range rows {
+		entry := leaderboardEntry{
+			Rank:     page*leaderboardPageSize + i + 1,
+			Username: row.Username,
+			Rating:   int(row.Rating),
+			Games:    int(row.Wins + row.Losses + row.Draws),
+			Wins:     int(row.Wins),
+			Losses:   int(row.Losses),
+			Draws:    int(row.Draws),
+		}
+		if settings.RatingSystem == config.RatingSystemGlicko2 {
+			entry.Rating = int(math.Round(row.GlickoRating))
+		}
+		if entry.Games > 0 {
+			entry.WinPercentage = float64(entry.Wins) / float64(entry.Games) * 100
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+type leaderboardModel struct {
+	ctx         *app.Context
+	columnIndex int
+	page        int
+	leaderboard leaderboardPage
+	err         error
+}
+
+func SetupLeaderboard(ctx *app.Context) tea.Model {
+	m := leaderboardModel{
+		ctx:         ctx,
+		columnIndex: int(ratingColumn),
+	}
+	m.reload()
+
+	return &m
+}
+
+func (m *leaderboardModel) reload() {
+	leaderboard, err := loadLeaderboard(m.ctx, leaderboardColumns[m.columnIndex], m.page)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.err = nil
+	m.leaderboard = leaderboard
+	m.page = leaderboard.Page
+}
+
+func (m *leaderboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *leaderboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		case "left", "right":
+			if msg.String() == "left" {
+				m.columnIndex--
+			} else {
+				m.columnIndex++
+			}
+			if m.columnIndex >= len(leaderboardColumns) {
+				m.columnIndex = 0
+			} else if m.columnIndex < 0 {
+				m.columnIndex = len(leaderboardColumns) - 1
+			}
+			m.page = 0
+			m.reload()
+		case "up", "pgup":
+			if m.page > 0 {
+				m.page--
+				m.reload()
+			}
+		case "down", "pgdown":
+			if m.page < m.leaderboard.Pages-1 {
+				m.page++
+				m.reload()
+			}
+		}
+	case error:
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *leaderboardModel) View() string {
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	s := "Leaderboard\n\n"
+
+	s += "   # "
+	for i, column := range leaderboardColumns {
+		var label string
+		switch column {
+		case playerColumn:
+			label = fmt.Sprintf("%-20s", "Player")
+		case ratingColumn:
+			label = fmt.Sprintf("%7s", "Rating")
+		case gamesColumn:
+			label = fmt.Sprintf("%6s", "Games")
+		case winsColumn:
+			label = fmt.Sprintf("%5s", "W")
+		case lossesColumn:
+			label = fmt.Sprintf("%5s", "L")
+		case drawsColumn:
+			label = fmt.Sprintf("%5s", "D")
+		case winPercentageColumn:
+			label = fmt.Sprintf("%7s", "Win %")
+		}
+
+		if i == m.columnIndex {
+			s += highlightStyle.Render(label)
+		} else {
+			s += headerStyle.Render(label)
+		}
+	}
+	s += "\n"
+
+	if len(m.leaderboard.Entries) == 0 {
+		s += "\nNo registered players yet.\n"
+	}
+	for _, entry := range m.leaderboard.Entries {
+		s += fmt.Sprintf("%4d %-20s%7d%6d%5d%5d%5d%6.1f%%\n",
+			entry.Rank,
+			entry.Username,
+			entry.Rating,
+			entry.Games,
+			entry.Wins,
+			entry.Losses,
+			entry.Draws,
+			entry.WinPercentage,
+		)
+	}
+
+	s += fmt.Sprintf("\nPage %d of %d\n", m.leaderboard.Page+1, max(m.leaderboard.Pages, 1))
+
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nUse left/right arrows to sort by a column, up/down to change page.\n"
+	s += "Press q, esc or ctrl+c to return to the main menu.\n"
+
+	return s
+}
@@ -2,154 +2,165 @@ package player
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
 	"github.com/deskdaniel/GoMate/internal/messages"
+	"github.com/deskdaniel/GoMate/internal/storage"
 )
 
-func checkLogin(ctx *app.Context, slot int) error {
-	if ctx == nil || ctx.Queries == nil {
-		return fmt.Errorf("context or Queries is nil")
+func checkLogin(ctx *app.Context) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
 	}
 
-	switch slot {
-	case 1:
-		if ctx.User1 != nil {
-			return fmt.Errorf("player 1 is already logged in")
-		}
-		if ctx.User2 != nil && ctx.User2.Username == ctx.Username {
-			return fmt.Errorf("username already logged in as player 2")
-		}
-	case 2:
-		if ctx.User2 != nil {
-			return fmt.Errorf("player 2 is already logged in")
-		}
-		if ctx.User1 != nil && ctx.User1.Username == ctx.Username {
-			return fmt.Errorf("username already logged in as player 1")
-		}
-	default:
-		return fmt.Errorf("invalid slot number")
+	if ctx.SignedIn(ctx.Username) != nil {
+		return fmt.Errorf("username already signed in")
 	}
 
 	return nil
 }
 
-func loginPlayer(ctx *app.Context, slot int) error {
-	if ctx == nil || ctx.Queries == nil {
-		return fmt.Errorf("context or Queries is nil")
+func loginPlayer(ctx *app.Context) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
 	}
 
-	err := checkLogin(ctx, slot)
+	err := checkLogin(ctx)
 	if err != nil {
 		return err
 	}
 
-	user, err := ctx.Queries.GetUserByName(context.Background(), ctx.Username)
+	now := time.Now()
+	err = checkLoginThrottle(ctx, ctx.Username, now)
 	if err != nil {
+		return err
+	}
+
+	user, err := ctx.Store.GetUserByName(context.Background(), ctx.Username)
+	if err == sql.ErrNoRows {
+		return failLogin(ctx, now, fmt.Errorf("failed to get user: %w", err))
+	} else if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
 	err = checkPasswordHash(ctx.Password, user.HashedPassword)
 	if err != nil {
-		return fmt.Errorf("invalid password")
+		return failLogin(ctx, now, fmt.Errorf("invalid password"))
 	}
 
-	switch slot {
-	case 1:
-		ctx.User1 = &app.User{
-			ID:       user.ID,
-			Username: user.Username,
-			Slot:     1,
-		}
-	case 2:
-		ctx.User2 = &app.User{
-			ID:       user.ID,
-			Username: user.Username,
-			Slot:     2,
-		}
-	default:
-		return fmt.Errorf("invalid slot number")
+	err = ctx.Store.ClearLoginAttempts(context.Background(), ctx.Username)
+	if err != nil {
+		return fmt.Errorf("failed to clear login attempts: %w", err)
+	}
+
+	if needsRehash(user.HashedPassword) {
+		// The old hash keeps working, so a failed upgrade is simply
+		// retried on the next login.
+		_ = updatePassword(ctx.Store, user.ID, ctx.Password)
 	}
 
+	ctx.SignIn(&app.User{
+		ID:       user.ID,
+		Username: user.Username,
+	})
+
 	return nil
 }
 
-func logoutPlayer(ctx *app.Context, slot int) error {
-	if ctx == nil || ctx.Queries == nil {
-		return fmt.Errorf("context or Queries is nil")
+// failLogin counts the failed login and adds the wait before the next
+// attempt to err.
+func failLogin(ctx *app.Context, now time.Time, err error) error {
+	throttleErr := recordFailedLogin(ctx, ctx.Username, now)
+	if throttleErr != nil {
+		return fmt.Errorf("%w: %w", err, throttleErr)
 	}
 
-	switch slot {
-	case 1:
-		if ctx.User1 == nil {
-			return fmt.Errorf("player 1 is not logged in")
-		}
-		ctx.User1 = nil
-	case 2:
-		if ctx.User2 == nil {
-			return fmt.Errorf("player 2 is not logged in")
-		}
-		ctx.User2 = nil
-	default:
-		return fmt.Errorf("invalid slot number")
+	return err
+}
+
+// updatePassword stores a new hash of password for the user.
+func updatePassword(store storage.Store, userID, password string) error {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	_, err = store.UpdatePassword(context.Background(), database.UpdatePasswordParams{
+		HashedPassword: hashedPassword,
+		UpdatedAt:      sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
+		ID:             userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
 	}
 
 	return nil
 }
 
+func logoutPlayer(ctx *app.Context, user *app.User) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+
+	if user == nil || ctx.SignedIn(user.Username) != user {
+		return fmt.Errorf("player is not signed in")
+	}
+	ctx.SignOut(user)
+
+	return forgetPlayer(ctx, user)
+}
+
+type loginField int
+
+const (
+	loginUsernameField loginField = iota
+	loginPasswordField
+	loginRememberField
+	loginSubmitField
+)
+
 type loginModel struct {
-	focusIndex field
+	focusIndex loginField
 	inputs     []textinput.Model
 	ctx        *app.Context
 	err        error
 	success    bool
-	slot       int
+	countdown  bool
+	remember   bool
+}
+
+type loginCountdownMsg struct{}
+
+// loginCountdown refreshes the wait time shown after too many failed
+// logins every second.
+func loginCountdown() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return loginCountdownMsg{}
+	})
 }
 
 func (m *loginModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-func SetupLogin(ctx *app.Context, slot int) tea.Model {
-	if ctx == nil || ctx.Queries == nil {
-		panic("SetupLogin called with nil ctx or nil ctx.Queries")
-	}
-
-	m := loginModel{}
-
-	switch slot {
-	case 1:
-		if ctx.User1 != nil {
-			err := logoutPlayer(ctx, slot)
-			if err != nil {
-				fmt.Println("Warning: failed to logout existing player 1:", err)
-				return nil
-			}
-			return nil
-		}
-	case 2:
-		if ctx.User2 != nil {
-			err := logoutPlayer(ctx, slot)
-			if err != nil {
-				fmt.Println("Warning: failed to logout existing player 2:", err)
-				return nil
-			}
-			return nil
-		}
-	default:
-		fmt.Println("SetupLogin called with invalid slot number")
-		return nil
+func SetupLogin(ctx *app.Context) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupLogin called with nil ctx or nil ctx.Store")
 	}
 
 	username := textinput.New()
 	username.Prompt = "Username: "
 	username.Placeholder = "username"
 	username.Focus()
-	username.CharLimit = 20
+	username.CharLimit = MaxUsernameLength
 	username.Width = 30
 
 	password := textinput.New()
@@ -160,20 +171,19 @@ func SetupLogin(ctx *app.Context, slot int) tea.Model {
 	password.CharLimit = 50
 	password.Width = 30
 
-	m = loginModel{
+	m := loginModel{
 		inputs: []textinput.Model{
 			username,
 			password,
 		},
-		focusIndex: 0,
+		focusIndex: loginUsernameField,
 		ctx:        ctx,
-		slot:       slot,
 	}
 
-	m.inputs[usernameField].PromptStyle = m.inputs[usernameField].PromptStyle.Foreground(lipgloss.Color("37"))
-	m.inputs[usernameField].TextStyle = m.inputs[usernameField].TextStyle.Foreground(lipgloss.Color("37"))
-	m.inputs[passwordField].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	m.inputs[passwordField].TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	m.inputs[loginUsernameField].PromptStyle = m.inputs[loginUsernameField].PromptStyle.Foreground(lipgloss.Color("37"))
+	m.inputs[loginUsernameField].TextStyle = m.inputs[loginUsernameField].TextStyle.Foreground(lipgloss.Color("37"))
+	m.inputs[loginPasswordField].PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	m.inputs[loginPasswordField].TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 
 	return &m
 }
@@ -202,12 +212,22 @@ func (m *loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return messages.SwitchToMainMenu{}
 			}
+		case " ":
+			if m.focusIndex == loginRememberField {
+				m.remember = !m.remember
+				return m, nil
+			}
 		case "tab", "shift+tab", "enter", "up", "down":
 			s := msg.String()
 
-			if s == "enter" && m.focusIndex == submitField {
-				username := m.inputs[usernameField].Value()
-				password := m.inputs[passwordField].Value()
+			if s == "enter" && m.focusIndex == loginRememberField {
+				m.remember = !m.remember
+				return m, nil
+			}
+
+			if s == "enter" && m.focusIndex == loginSubmitField {
+				username := m.inputs[loginUsernameField].Value()
+				password := m.inputs[loginPasswordField].Value()
 				return m, func() tea.Msg {
 					return loginMsg{
 						Username: username,
@@ -222,18 +242,19 @@ func (m *loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.focusIndex++
 			}
 
-			if m.focusIndex == confirmPasswordField {
+			// Without a session file there is nothing to remember logins in.
+			if m.focusIndex == loginRememberField && m.ctx.SessionPath == "" {
 				if s == "up" || s == "shift+tab" {
 					m.focusIndex--
 				} else {
 					m.focusIndex++
 				}
 			}
-			if m.focusIndex > submitField {
-				m.focusIndex = usernameField
+			if m.focusIndex > loginSubmitField {
+				m.focusIndex = loginUsernameField
 			}
-			if m.focusIndex < usernameField {
-				m.focusIndex = submitField
+			if m.focusIndex < loginUsernameField {
+				m.focusIndex = loginSubmitField
 			}
 
 			for i := 0; i < len(m.inputs); i++ {
@@ -254,13 +275,36 @@ func (m *loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ctx.Username = msg.Username
 		m.ctx.Password = msg.Password
 
-		err := loginPlayer(m.ctx, m.slot)
+		err := loginPlayer(m.ctx)
 		if err != nil {
 			m.err = err
+			var throttled *loginThrottledError
+			if errors.As(err, &throttled) && !m.countdown {
+				m.countdown = true
+				return m, loginCountdown()
+			}
 			return m, nil
 		}
 
 		m.success = true
+		if m.remember {
+			err = rememberPlayer(m.ctx, m.ctx.SignedIn(msg.Username), time.Now())
+			if err != nil {
+				m.err = fmt.Errorf("failed to remember login: %w", err)
+			}
+		}
+		return m, nil
+	case loginCountdownMsg:
+		var throttled *loginThrottledError
+		if !errors.As(m.err, &throttled) {
+			m.countdown = false
+			return m, nil
+		}
+		if time.Now().Before(throttled.until) {
+			return m, loginCountdown()
+		}
+		m.err = nil
+		m.countdown = false
 		return m, nil
 	case error:
 		m.err = msg
@@ -278,6 +322,10 @@ func (m *loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *loginModel) View() string {
 	if m.success {
 		s := fmt.Sprintf("User %s logged in successfully!\n\n", m.ctx.Username)
+		if m.err != nil {
+			errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+			s += errStyle.Render(m.err.Error()) + "\n"
+		}
 
 		m.ctx.Username = ""
 		m.ctx.Password = ""
@@ -286,13 +334,25 @@ func (m *loginModel) View() string {
 		return s
 	}
 
-	s := fmt.Sprintf("Logging in as Player%d\n\n", m.slot)
+	s := "Sign In\n\n"
 	for i := range m.inputs {
 		s += m.inputs[i].View() + "\n\n"
 	}
 
+	if m.ctx.SessionPath != "" {
+		checkbox := "[ ] Remember me"
+		if m.remember {
+			checkbox = "[x] Remember me"
+		}
+		rememberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		if m.focusIndex == loginRememberField {
+			rememberStyle = rememberStyle.Foreground(lipgloss.Color("37")).Bold(true)
+		}
+		s += rememberStyle.Render(checkbox) + "\n\n"
+	}
+
 	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	if m.focusIndex == submitField {
+	if m.focusIndex == loginSubmitField {
 		buttonStyle = buttonStyle.Foreground(lipgloss.Color("37")).Bold(true)
 	}
 	s += buttonStyle.Render("[ Submit ]") + "\n"
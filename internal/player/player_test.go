@@ -1,14 +1,24 @@
 package player
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/deskdaniel/GoMate/internal/app"
 	"github.com/deskdaniel/GoMate/internal/database"
-	_ "github.com/mattn/go-sqlite3"
-	"github.com/pressly/goose/v3"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestCheckPassword(t *testing.T) {
@@ -49,7 +59,7 @@ func TestCheckUserName(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkUsername(test.username)
+			err := CheckUsername(test.username)
 			if (err != nil) != test.wantErr {
 				t.Errorf("checkUserName(%q) error = %v, wantErr %v", test.username, err, test.wantErr)
 			}
@@ -73,32 +83,113 @@ func TestHashAndCheckPassword(t *testing.T) {
 	if err == nil {
 		t.Error("CheckHash did not fail for incorrect password")
 	}
-}
 
-func setupTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
+	if !strings.HasPrefix(hashed, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("Expected a PHC encoded Argon2id hash, got %q", hashed)
+	}
+	if needsRehash(hashed) {
+		t.Error("Expected a new hash not to need a rehash")
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
 	if err != nil {
-		t.Fatalf("Failed to open in-memory database: %v", err)
+		t.Fatalf("GenerateFromPassword error: %v", err)
+	}
+	err = checkPasswordHash(password, string(legacy))
+	if err != nil {
+		t.Errorf("CheckHash failed for legacy bcrypt hash: %v", err)
+	}
+	if !needsRehash(string(legacy)) {
+		t.Error("Expected a bcrypt hash to need a rehash")
+	}
+
+	// A cost this high would stall every login.
+	expensive := strings.Replace(string(legacy), "$04$", "$31$", 1)
+	if ValidatePasswordHash(expensive) == nil || checkPasswordHash(password, expensive) == nil {
+		t.Error("Expected a bcrypt hash with a huge cost to be rejected")
 	}
 
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		t.Fatalf("Failed to set goose dialect: %v", err)
+	if !needsRehash("$argon2id$v=19$m=65536,t=3,p=4$c2FsdHNhbHQ$" + argon2TestKey) {
+		t.Error("Expected a hash with other parameters to need a rehash")
 	}
+}
+
+// argon2TestKey is a base64 encoded key of the length hashPassword uses.
+const argon2TestKey = "a2tra2tra2tra2tra2tra2tra2tra2tra2tra2tra2s"
 
-	if err := goose.Up(db, "../../sql/schema"); err != nil {
-		t.Fatalf("Failed to apply migrations: %v", err)
+func TestInvalidArgon2Hash(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"no salt or key", "$argon2id$v=19$m=19456,t=2,p=1$$"},
+		{"other version", "$argon2id$v=18$m=19456,t=2,p=1$c2FsdHNhbHQ$" + argon2TestKey},
+		{"unparsable parameters", "$argon2id$v=19$m=x$c2FsdHNhbHQ$" + argon2TestKey},
+		{"no passes", "$argon2id$v=19$m=19456,t=0,p=1$c2FsdHNhbHQ$" + argon2TestKey},
+		{"too many passes", "$argon2id$v=19$m=19456,t=1000,p=1$c2FsdHNhbHQ$" + argon2TestKey},
+		{"no parallelism", "$argon2id$v=19$m=19456,t=2,p=0$c2FsdHNhbHQ$" + argon2TestKey},
+		{"too much parallelism", "$argon2id$v=19$m=19456,t=2,p=255$c2FsdHNhbHQ$" + argon2TestKey},
+		{"no memory", "$argon2id$v=19$m=0,t=2,p=1$c2FsdHNhbHQ$" + argon2TestKey},
+		{"too much memory", "$argon2id$v=19$m=4294967295,t=2,p=1$c2FsdHNhbHQ$" + argon2TestKey},
+		{"short salt", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdA$" + argon2TestKey},
+		{"empty key", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$"},
+		{"short key", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$a2tra2tra2tra2tra2traw"},
 	}
 
-	return db
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if checkPasswordHash("SecurePass123!", test.hash) == nil {
+				t.Errorf("CheckHash did not fail for %q", test.hash)
+			}
+			if !needsRehash(test.hash) {
+				t.Errorf("Expected %q to need a rehash", test.hash)
+			}
+		})
+	}
 }
 
-func TestRegisterUser(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+func TestLoginRehashesLegacyPassword(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store:    store,
+		Username: "LegacyUser",
+		Password: "LegacyPass123!",
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte(ctx.Password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword error: %v", err)
+	}
+	_, err = store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             "legacy-id",
+		Username:       ctx.Username,
+		HashedPassword: string(legacy),
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	err = loginPlayer(ctx)
+	if err != nil {
+		t.Fatalf("LoginPlayer failed: %v", err)
+	}
+
+	user, err := store.GetUserByName(context.Background(), ctx.Username)
+	if err != nil {
+		t.Fatalf("GetUserByName failed: %v", err)
+	}
+	if needsRehash(user.HashedPassword) {
+		t.Errorf("Expected the password to be rehashed with Argon2id, got %q", user.HashedPassword)
+	}
+	if checkPasswordHash(ctx.Password, user.HashedPassword) != nil {
+		t.Error("Expected the rehashed password to verify")
+	}
+}
 
-	queries := database.New(db)
+func TestRegisterUser(t *testing.T) {
+	store := storage.NewMemory()
 	ctx := &app.Context{
-		Queries:  queries,
+		Store:    store,
 		Username: "TestUser",
 		Password: "TestPass123!",
 	}
@@ -108,7 +199,7 @@ func TestRegisterUser(t *testing.T) {
 		t.Fatalf("RegisterPlayer failed: %v", err)
 	}
 
-	user, err := queries.GetUserByName(context.Background(), "TestUser")
+	user, err := store.GetUserByName(context.Background(), "TestUser")
 	if err != nil {
 		t.Fatalf("GetUserByName failed: %v", err)
 	}
@@ -128,12 +219,9 @@ func TestRegisterUser(t *testing.T) {
 }
 
 func TestLoginUser(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
-
-	queries := database.New(db)
+	store := storage.NewMemory()
 	ctx := &app.Context{
-		Queries:  queries,
+		Store:    store,
 		Username: "LoginUser",
 		Password: "LoginPass123!",
 	}
@@ -144,13 +232,13 @@ func TestLoginUser(t *testing.T) {
 	}
 
 	ctx.Password = "WrongPass!"
-	err = loginPlayer(ctx, 1)
+	err = loginPlayer(ctx)
 	if err == nil {
 		t.Fatal("LoginPlayer did not fail for incorrect password")
 	}
 
 	ctx.Password = "LoginPass123!"
-	err = loginPlayer(ctx, 1)
+	err = loginPlayer(ctx)
 	if err != nil {
 		t.Fatalf("LoginPlayer failed: %v", err)
 	}
@@ -159,115 +247,874 @@ func TestLoginUser(t *testing.T) {
 		t.Errorf("Expected User1 to be set with username 'LoginUser', got %+v", ctx.User1)
 	}
 
-	err = loginPlayer(ctx, 2)
+	err = loginPlayer(ctx)
 	if err == nil {
 		t.Fatalf("LoginPlayer did not fail for already logged in username")
 	}
 
-	ctx2 := &app.Context{
-		Queries:  queries,
-		Username: "LoginUser2",
-		Password: "LoginPass123!",
+	for _, username := range []string{"LoginUser2", "LoginUser3"} {
+		ctx.Username = username
+		err = registerPlayer(ctx)
+		if err != nil {
+			t.Fatalf("RegisterPlayer for %s failed: %v", username, err)
+		}
+		err = loginPlayer(ctx)
+		if err != nil {
+			t.Fatalf("LoginPlayer for %s failed: %v", username, err)
+		}
 	}
-	err = registerPlayer(ctx2)
+
+	if len(ctx.Roster) != 3 || ctx.Roster[2].Username != "LoginUser3" {
+		t.Errorf("Expected three players on the roster, got %+v", ctx.Roster)
+	}
+	if ctx.User2 == nil || ctx.User2.Username != "LoginUser2" {
+		t.Errorf("Expected User2 to be set with username 'LoginUser2', got %+v", ctx.User2)
+	}
+
+	err = logoutPlayer(ctx, ctx.User1)
 	if err != nil {
-		t.Fatalf("RegisterPlayer for second user failed: %v", err)
+		t.Fatalf("logoutPlayer failed: %v", err)
+	}
+	if ctx.User1 != nil || len(ctx.Roster) != 2 || ctx.SignedIn("LoginUser") != nil {
+		t.Errorf("Expected LoginUser to be signed out, got %+v", ctx.Roster)
+	}
+	err = logoutPlayer(ctx, &app.User{ID: "LoginUser", Username: "LoginUser"})
+	if err == nil {
+		t.Error("Expected signing out a player who is not signed in to fail")
+	}
+}
+
+func TestLoadLeaderboard(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	for i := 0; i < 12; i++ {
+		username := fmt.Sprintf("Player%02d", i)
+		_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             username,
+			Username:       username,
+			HashedPassword: "hash",
+		})
+		if err != nil {
+			t.Fatalf("RegisterUser failed: %v", err)
+		}
+		_, err = store.AddResult(context.Background(), database.AddResultParams{
+			ID:     username + "-record",
+			UserID: username,
+			Wins:   sql.NullInt64{Int64: int64(i), Valid: true},
+			Losses: sql.NullInt64{Int64: int64(12 - i), Valid: true},
+			Draws:  sql.NullInt64{Int64: 0, Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("AddResult failed: %v", err)
+		}
+		_, err = store.UpdateRating(context.Background(), database.UpdateRatingParams{
+			Rating: int64(1500 + 100*((i*5)%12)),
+			UserID: username,
+		})
+		if err != nil {
+			t.Fatalf("UpdateRating failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		column leaderboardColumn
+		page   int
+		first  string
+		count  int
+	}{
+		{"by rating", ratingColumn, 0, "Player07", 10},
+		{"by wins", winsColumn, 0, "Player11", 10},
+		{"by losses", lossesColumn, 0, "Player00", 10},
+		{"by win percentage", winPercentageColumn, 0, "Player11", 10},
+		{"by name", playerColumn, 0, "Player00", 10},
+		{"second page", playerColumn, 1, "Player10", 2},
+		{"page past the end", playerColumn, 5, "Player10", 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			leaderboard, err := loadLeaderboard(ctx, test.column, test.page)
+			if err != nil {
+				t.Fatalf("loadLeaderboard failed: %v", err)
+			}
+			if leaderboard.Pages != 2 {
+				t.Errorf("Expected 2 pages, got %d", leaderboard.Pages)
+			}
+			if len(leaderboard.Entries) != test.count {
+				t.Fatalf("Expected %d entries, got %d", test.count, len(leaderboard.Entries))
+			}
+			if leaderboard.Entries[0].Username != test.first {
+				t.Errorf("Expected %s first, got %s", test.first, leaderboard.Entries[0].Username)
+			}
+			if leaderboard.Entries[0].Rank != leaderboard.Page*leaderboardPageSize+1 {
+				t.Errorf("Unexpected rank %d on page %d", leaderboard.Entries[0].Rank, leaderboard.Page)
+			}
+		})
 	}
+}
 
-	err = loginPlayer(ctx2, 2)
+func createTestGame(t *testing.T, store storage.Store, params database.CreateGameParams) {
+	t.Helper()
+
+	if params.StartedAt == "" {
+		params.StartedAt = params.EndedAt
+	}
+	if params.TimeControl == "" {
+		params.TimeControl = "-"
+	}
+	_, err := store.CreateGame(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateGame failed: %v", err)
+	}
+}
+
+func TestCheckHeadToHead(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	users := map[string]*app.User{}
+	for _, username := range []string{"Alice", "Bob", "Carol"} {
+		_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:             username + "-id",
+			Username:       username,
+			HashedPassword: "hash",
+		})
+		if err != nil {
+			t.Fatalf("RegisterUser failed: %v", err)
+		}
+		users[username] = &app.User{ID: username + "-id", Username: username}
+	}
+
+	games := []struct {
+		white  string
+		black  string
+		result string
+	}{
+		{"Alice", "Bob", "1-0"},
+		{"Alice", "Bob", "1/2-1/2"},
+		{"Bob", "Alice", "1-0"},
+		{"Bob", "Alice", "0-1"},
+		{"Bob", "Alice", "0-1"},
+		{"Alice", "Carol", "1-0"},
+	}
+	for i, game := range games {
+		createTestGame(t, store, database.CreateGameParams{
+			ID:          fmt.Sprintf("game-%d", i),
+			WhiteUserID: sql.NullString{String: users[game.white].ID, Valid: true},
+			BlackUserID: sql.NullString{String: users[game.black].ID, Valid: true},
+			WhiteName:   game.white,
+			BlackName:   game.black,
+			Result:      game.result,
+			Termination: "checkmate",
+			EndedAt:     fmt.Sprintf("2024-01-0%dT11:00:00Z", i+1),
+		})
+	}
+
+	record, err := checkHeadToHead(ctx, users["Alice"], users["Bob"])
 	if err != nil {
-		t.Fatalf("LoginPlayer for second user failed: %v", err)
+		t.Fatalf("checkHeadToHead failed: %v", err)
+	}
+	if record.AsWhite != (colorRecord{Wins: 1, Draws: 1}) {
+		t.Errorf("Unexpected record as white: %+v", record.AsWhite)
+	}
+	if record.AsBlack != (colorRecord{Wins: 2, Losses: 1}) {
+		t.Errorf("Unexpected record as black: %+v", record.AsBlack)
+	}
+	if len(record.Games) != 5 || record.Games[0].Date != "2024-01-05" {
+		t.Errorf("Expected 5 games, newest first, got %+v", record.Games)
 	}
 
-	if ctx2.User2 == nil || ctx2.User2.Username != "LoginUser2" {
-		t.Errorf("Expected User2 to be set with username 'LoginUser2', got %+v", ctx2.User2)
+	reverse, err := checkHeadToHead(ctx, users["Bob"], users["Alice"])
+	if err != nil {
+		t.Fatalf("checkHeadToHead failed: %v", err)
+	}
+	if reverse.AsWhite != (colorRecord{Wins: 1, Losses: 2}) || reverse.AsBlack != (colorRecord{Losses: 1, Draws: 1}) {
+		t.Errorf("Unexpected reversed record: %+v %+v", reverse.AsWhite, reverse.AsBlack)
+	}
+
+	_, err = checkHeadToHead(ctx, users["Alice"], nil)
+	if err == nil {
+		t.Error("Expected error without a second player")
 	}
 }
 
-func TestUpdateUserRecord(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+func TestCheckBreakdown(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	player := sql.NullString{String: "player-id", Valid: true}
+	_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             player.String,
+		Username:       "Player",
+		HashedPassword: "hash",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	games := []struct {
+		white       bool
+		result      string
+		termination string
+		timeControl string
+		moves       string
+	}{
+		{true, "1-0", "checkmate", "-", "f3 e5 g4 Qh4#"},
+		{false, "0-1", "resignation", "5+0", "e4"},
+		{true, "1-0", "checkmate", "-", "e4 e5"},
+		{false, "1-0", "checkmate", "-", "f3 e5 g4"},
+		{true, "1/2-1/2", "agreement", "5+0", "e4 e5 Nf3 Nc6"},
+		{false, "0-1", "timeout", "5+0", "d4 d5"},
+	}
+	for i, game := range games {
+		params := database.CreateGameParams{
+			ID:          fmt.Sprintf("game-%d", i),
+			WhiteName:   "Guest 1",
+			BlackName:   "Guest 2",
+			Result:      game.result,
+			Termination: game.termination,
+			EndedAt:     fmt.Sprintf("2024-01-0%dT11:00:00Z", i+1),
+			Moves:       game.moves,
+			TimeControl: game.timeControl,
+		}
+		if game.white {
+			params.WhiteUserID = player
+			params.WhiteName = "Player"
+		} else {
+			params.BlackUserID = player
+			params.BlackName = "Player"
+		}
+		createTestGame(t, store, params)
+	}
+
+	result, err := checkBreakdown(ctx, player.String)
+	if err != nil {
+		t.Fatalf("checkBreakdown failed: %v", err)
+	}
+
+	if result.Games != 6 {
+		t.Errorf("Expected 6 games, got %d", result.Games)
+	}
+	if result.AsWhite != (colorRecord{Wins: 2, Draws: 1}) || result.AsBlack != (colorRecord{Wins: 2, Losses: 1}) {
+		t.Errorf("Unexpected color breakdown: white %+v, black %+v", result.AsWhite, result.AsBlack)
+	}
+
+	expectedTerminations := []groupRecord{
+		{"checkmate", colorRecord{Wins: 2, Losses: 1}},
+		{"resignation", colorRecord{Wins: 1}},
+		{"agreement", colorRecord{Draws: 1}},
+		{"timeout", colorRecord{Wins: 1}},
+	}
+	if !slices.Equal(result.Terminations, expectedTerminations) {
+		t.Errorf("Expected terminations %+v, got %+v", expectedTerminations, result.Terminations)
+	}
+
+	expectedTimeControls := []groupRecord{
+		{"-", colorRecord{Wins: 2, Losses: 1}},
+		{"5+0", colorRecord{Wins: 2, Draws: 1}},
+	}
+	if !slices.Equal(result.TimeControls, expectedTimeControls) {
+		t.Errorf("Expected time controls %+v, got %+v", expectedTimeControls, result.TimeControls)
+	}
+
+	if result.CurrentStreak != 1 || result.LongestStreak != 3 {
+		t.Errorf("Expected streaks 1 and 3, got %d and %d", result.CurrentStreak, result.LongestStreak)
+	}
+	if result.AverageLength != 1.5 {
+		t.Errorf("Expected average length 1.5 moves, got %v", result.AverageLength)
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             "player-id",
+		Username:       "Player",
+		HashedPassword: "hash",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	var b bytes.Buffer
+	err = WriteStats(ctx, "Player", &b, false)
+	if err != nil {
+		t.Fatalf("WriteStats failed: %v", err)
+	}
+	expected := "Stats for Player:\n\nNo games played yet.\n\nRating: 1500 (provisional)\n"
+	if b.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	err = WriteStats(ctx, "Player", &b, true)
+	if err != nil {
+		t.Fatalf("WriteStats failed: %v", err)
+	}
+	var decoded map[string]any
+	err = json.Unmarshal(b.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", b.String(), err)
+	}
+	if decoded["username"] != "Player" || decoded["rating"] != 1500.0 || decoded["glicko2"].(map[string]any)["deviation"] != 350.0 {
+		t.Errorf("Unexpected stats %v", decoded)
+	}
+	if achievements, ok := decoded["achievements"].([]any); !ok || len(achievements) != 0 {
+		t.Errorf("Expected no achievements, got %v", decoded["achievements"])
+	}
+
+	err = WriteStats(ctx, "Nobody", &b, false)
+	if err == nil || !strings.Contains(err.Error(), "no player named Nobody") {
+		t.Errorf("Expected an unknown player to fail, got %v", err)
+	}
+}
 
-	queries := database.New(db)
+func TestGameHistory(t *testing.T) {
+	store := storage.NewMemory()
 	ctx := &app.Context{
-		Queries:  queries,
-		Username: "RecordUser",
-		Password: "RecordPass123!",
+		Store: store,
 	}
 
+	player := sql.NullString{String: "player-id", Valid: true}
+	_, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             player.String,
+		Username:       "Player",
+		HashedPassword: "hash",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	createTestGame(t, store, database.CreateGameParams{
+		ID:          "game-1",
+		WhiteUserID: player,
+		WhiteName:   "Player",
+		BlackName:   "Guest 2",
+		Result:      "1-0",
+		Termination: "checkmate",
+		EndedAt:     "2024-01-01T11:00:00Z",
+		Moves:       "e4 e5 Bc4 Nc6 Qh5 Nf6 Qxf7#",
+	})
+	createTestGame(t, store, database.CreateGameParams{
+		ID:          "game-2",
+		BlackUserID: player,
+		WhiteName:   "Guest 1",
+		BlackName:   "Player",
+		Result:      "1-0",
+		Termination: "resignation",
+		EndedAt:     "2024-02-01T11:00:00Z",
+		Moves:       "d4",
+	})
+	createTestGame(t, store, database.CreateGameParams{
+		ID:          "game-3",
+		BlackUserID: player,
+		WhiteName:   "Someone",
+		BlackName:   "Player",
+		Result:      "1/2-1/2",
+		Termination: "agreement",
+		EndedAt:     "2024-03-01T11:00:00Z",
+	})
+
+	games, err := loadHistory(ctx, "Player")
+	if err != nil {
+		t.Fatalf("loadHistory failed: %v", err)
+	}
+	expected := []historyEntry{
+		{"game-3", "2024-03-01", "Someone", "black", "Draw", 0, "agreement"},
+		{"game-2", "2024-02-01", "Guest 1", "black", "Loss", 1, "resignation"},
+		{"game-1", "2024-01-01", "Guest 2", "white", "Win", 4, "checkmate"},
+	}
+	if !slices.Equal(games, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, games)
+	}
+
+	tests := []struct {
+		name     string
+		filter   historyFilter
+		query    string
+		expected []string
+	}{
+		{"all", allGames, "", []string{"game-3", "game-2", "game-1"}},
+		{"wins", wonGames, "", []string{"game-1"}},
+		{"losses", lostGames, "", []string{"game-2"}},
+		{"draws", drawnGames, "", []string{"game-3"}},
+		{"as black", blackGames, "", []string{"game-3", "game-2"}},
+		{"search opponent", allGames, "guest", []string{"game-2", "game-1"}},
+		{"search date", allGames, "2024-02", []string{"game-2"}},
+		{"search termination with filter", whiteGames, "mate", []string{"game-1"}},
+		{"no match", drawnGames, "guest", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ids []string
+			for _, game := range filterHistory(games, test.filter, test.query) {
+				ids = append(ids, game.ID)
+			}
+			if !slices.Equal(ids, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, ids)
+			}
+		})
+	}
+
+	_, err = loadHistory(ctx, "Nobody")
+	if err == nil {
+		t.Error("Expected error for unknown user")
+	}
+}
+
+func TestAccount(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	for _, username := range []string{"Owner", "Other"} {
+		ctx.Username = username
+		ctx.Password = "OwnerPass123!"
+		err := registerPlayer(ctx)
+		if err != nil {
+			t.Fatalf("RegisterPlayer failed: %v", err)
+		}
+		err = loginPlayer(ctx)
+		if err != nil {
+			t.Fatalf("LoginPlayer failed: %v", err)
+		}
+	}
+
+	owner := ctx.SignedIn("Owner")
+
+	t.Run("change password", func(t *testing.T) {
+		err := changePassword(ctx, owner, "WrongPass123!", "NewPass123!")
+		if err == nil {
+			t.Error("Expected wrong current password to fail")
+		}
+		err = changePassword(ctx, owner, "OwnerPass123!", "short")
+		if err == nil {
+			t.Error("Expected invalid new password to fail")
+		}
+		err = changePassword(ctx, owner, "OwnerPass123!", "NewPass123!")
+		if err != nil {
+			t.Fatalf("changePassword failed: %v", err)
+		}
+
+		user, err := store.GetUserByName(context.Background(), "Owner")
+		if err != nil {
+			t.Fatalf("GetUserByName failed: %v", err)
+		}
+		if checkPasswordHash("NewPass123!", user.HashedPassword) != nil {
+			t.Error("Expected the new password to be stored")
+		}
+	})
+
+	t.Run("change username", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			password string
+			username string
+		}{
+			{"wrong password", "OwnerPass123!", "Renamed"},
+			{"invalid username", "NewPass123!", "no"},
+			{"taken username", "NewPass123!", "Other"},
+		}
+		for _, test := range tests {
+			err := changeUsername(ctx, owner, test.password, test.username)
+			if err == nil {
+				t.Errorf("Expected %s to fail", test.name)
+			}
+		}
+
+		err := changeUsername(ctx, owner, "NewPass123!", "Renamed")
+		if err != nil {
+			t.Fatalf("changeUsername failed: %v", err)
+		}
+		if ctx.User1.Username != "Renamed" {
+			t.Errorf("Expected player 1 to be renamed, got %s", ctx.User1.Username)
+		}
+		_, err = store.GetUserByName(context.Background(), "Owner")
+		if err != sql.ErrNoRows {
+			t.Errorf("Expected the old username to be free, got %v", err)
+		}
+	})
+
+	t.Run("delete account", func(t *testing.T) {
+		err := deleteAccount(ctx, owner, "OwnerPass123!")
+		if err == nil {
+			t.Error("Expected wrong password to fail")
+		}
+		err = deleteAccount(ctx, owner, "NewPass123!")
+		if err != nil {
+			t.Fatalf("deleteAccount failed: %v", err)
+		}
+		if ctx.User1 != nil || ctx.SignedIn("Renamed") != nil {
+			t.Error("Expected the owner to be signed out")
+		}
+		_, err = store.GetUserByName(context.Background(), "Renamed")
+		if err != sql.ErrNoRows {
+			t.Errorf("Expected the account to be deleted, got %v", err)
+		}
+		if ctx.User2 == nil || len(ctx.Roster) != 1 {
+			t.Error("Expected the other player to stay signed in")
+		}
+
+		err = deleteAccount(ctx, owner, "NewPass123!")
+		if err == nil {
+			t.Error("Expected deleting without a signed in player to fail")
+		}
+	})
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failed int64
+		delay  time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{9, 64 * time.Second},
+		{10, loginLockout},
+		{25, loginLockout},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d failed", test.failed), func(t *testing.T) {
+			delay := loginDelay(test.failed)
+			if delay != test.delay {
+				t.Errorf("Expected %s, got %s", test.delay, delay)
+			}
+		})
+	}
+}
+
+func TestAccountThrottle(t *testing.T) {
+	ctx := &app.Context{
+		Store:    storage.NewMemory(),
+		Username: "Guessed",
+		Password: "GuessedPass1!",
+	}
 	err := registerPlayer(ctx)
 	if err != nil {
 		t.Fatalf("RegisterPlayer failed: %v", err)
 	}
+	err = loginPlayer(ctx)
+	if err != nil {
+		t.Fatalf("LoginPlayer failed: %v", err)
+	}
+	user := ctx.SignedIn("Guessed")
 
-	user, err := queries.GetUserByName(context.Background(), "RecordUser")
+	var throttled *loginThrottledError
+	for i := 1; i <= freeLoginAttempts; i++ {
+		err = changePassword(ctx, user, fmt.Sprintf("Guess%dPass!", i), "NewPass123!")
+		if err == nil || (i >= freeLoginAttempts) != errors.As(err, &throttled) {
+			t.Fatalf("Unexpected result after %d wrong passwords: %v", i, err)
+		}
+	}
+
+	// Even the right password has to wait now.
+	err = deleteAccount(ctx, user, ctx.Password)
+	if !errors.As(err, &throttled) {
+		t.Errorf("Expected the account to be throttled, got %v", err)
+	}
+	err = loginPlayer(&app.Context{Store: ctx.Store, Username: "Guessed", Password: ctx.Password})
+	if !errors.As(err, &throttled) {
+		t.Errorf("Expected logins to be throttled too, got %v", err)
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	ctx := &app.Context{
+		Store:    storage.NewMemory(),
+		Username: "Throttled",
+		Password: "ThrottledPass1!",
+	}
+	err := registerPlayer(ctx)
 	if err != nil {
-		t.Fatalf("GetUserByName failed: %v", err)
+		t.Fatalf("RegisterPlayer failed: %v", err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var throttled *loginThrottledError
+	for i := 1; i <= lockoutLoginAttempts; i++ {
+		now := start.Add(time.Duration(i) * time.Hour)
+		err = recordFailedLogin(ctx, ctx.Username, now)
+		if (i >= freeLoginAttempts) != errors.As(err, &throttled) {
+			t.Fatalf("Unexpected result after %d failed logins: %v", i, err)
+		}
+		if err != nil && !throttled.until.Equal(now.Add(loginDelay(int64(i)))) {
+			t.Errorf("Expected to wait %s after %d failed logins, got until %s", loginDelay(int64(i)), i, throttled.until)
+		}
+	}
+	if !strings.Contains(logs.String(), `"Throttled" locked out`) {
+		t.Errorf("Expected the lockout to be logged, got %q", logs.String())
+	}
+
+	locked := start.Add(lockoutLoginAttempts * time.Hour)
+	err = checkLoginThrottle(ctx, ctx.Username, locked.Add(time.Minute))
+	if !errors.As(err, &throttled) {
+		t.Errorf("Expected the username to be locked, got %v", err)
+	}
+	err = checkLoginThrottle(ctx, ctx.Username, locked.Add(loginLockout))
+	if err != nil {
+		t.Errorf("Expected the lockout to end, got %v", err)
+	}
+	err = checkLoginThrottle(ctx, "Other", locked)
+	if err != nil {
+		t.Errorf("Expected other usernames not to be throttled, got %v", err)
 	}
 
-	err = updateUserRecord(user.Username, ctx, true, false, false)
+	err = recordFailedLogin(ctx, ctx.Username, locked.Add(loginAttemptsExpire+time.Hour))
 	if err != nil {
-		t.Fatalf("UpdateUserRecord failed: %v", err)
+		t.Errorf("Expected old failed logins to be forgotten, got %v", err)
 	}
+}
 
-	stats, err := checkStats(user.Username, ctx)
+func TestLoginThrottleSubSecond(t *testing.T) {
+	ctx := &app.Context{
+		Store:    storage.NewMemory(),
+		Username: "Throttled",
+		Password: "ThrottledPass1!",
+	}
+	err := registerPlayer(ctx)
 	if err != nil {
-		t.Fatalf("CheckStats failed: %v", err)
+		t.Fatalf("RegisterPlayer failed: %v", err)
 	}
 
-	if stats.Wins != 1 || stats.Losses != 0 || stats.Draws != 0 {
-		t.Errorf("Unexpected stats after win: %+v", stats)
+	// The lockout deadline keeps its fraction of a second, so the wait is
+	// neither cut short nor stretched.
+	now := time.Date(2024, 1, 1, 10, 0, 0, 600*int(time.Millisecond), time.UTC)
+	for i := 1; i <= freeLoginAttempts; i++ {
+		err = recordFailedLogin(ctx, ctx.Username, now)
+	}
+	var throttled *loginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Expected to be throttled after %d failed logins, got %v", freeLoginAttempts, err)
 	}
 
-	err = updateUserRecord(user.Username, ctx, false, true, false)
+	until := now.Add(loginDelay(freeLoginAttempts))
+	err = checkLoginThrottle(ctx, ctx.Username, until.Add(-time.Millisecond))
+	if !errors.As(err, &throttled) || !throttled.until.Equal(until) {
+		t.Errorf("Expected to wait until %s, got %v", until, err)
+	}
+	err = checkLoginThrottle(ctx, ctx.Username, until)
 	if err != nil {
-		t.Fatalf("UpdateUserRecord failed: %v", err)
+		t.Errorf("Expected the wait to end at %s, got %v", until, err)
 	}
+}
 
-	stats, err = checkStats(user.Username, ctx)
+func TestLoginPlayerThrottled(t *testing.T) {
+	ctx := &app.Context{
+		Store:    storage.NewMemory(),
+		Username: "Throttled",
+		Password: "ThrottledPass1!",
+	}
+	err := registerPlayer(ctx)
 	if err != nil {
-		t.Fatalf("CheckStats failed: %v", err)
+		t.Fatalf("RegisterPlayer failed: %v", err)
 	}
 
-	if stats.Wins != 1 || stats.Losses != 1 || stats.Draws != 0 {
-		t.Errorf("Unexpected stats after loss: %+v", stats)
+	ctx.Password = "WrongPass123!"
+	for i := 1; i < freeLoginAttempts; i++ {
+		err = loginPlayer(ctx)
+		if err == nil || err.Error() != "invalid password" {
+			t.Fatalf("Expected invalid password without waiting, got %v", err)
+		}
 	}
 
-	err = updateUserRecord(user.Username, ctx, false, false, true)
+	// Start from more failures so that the wait outlasts the test.
+	_, err = ctx.Store.SaveLoginAttempt(context.Background(), database.SaveLoginAttemptParams{
+		Username:       ctx.Username,
+		FailedAttempts: lockoutLoginAttempts - 2,
+		LastFailedAt:   time.Now().Format(time.RFC3339),
+	})
 	if err != nil {
-		t.Fatalf("UpdateUserRecord failed: %v", err)
+		t.Fatalf("SaveLoginAttempt failed: %v", err)
+	}
+	err = loginPlayer(ctx)
+	var throttled *loginThrottledError
+	if !errors.As(err, &throttled) || !strings.HasPrefix(err.Error(), "invalid password: too many failed attempts, try again in ") {
+		t.Errorf("Expected the wait time after a failed login, got %v", err)
+	}
+
+	ctx.Password = "ThrottledPass1!"
+	err = loginPlayer(ctx)
+	if !errors.As(err, &throttled) || ctx.User1 != nil {
+		t.Errorf("Expected the correct password to wait too, got %v", err)
 	}
 
-	stats, err = checkStats(user.Username, ctx)
+	err = ctx.Store.ClearLoginAttempts(context.Background(), ctx.Username)
+	if err != nil {
+		t.Fatalf("ClearLoginAttempts failed: %v", err)
+	}
+	err = loginPlayer(ctx)
 	if err != nil {
-		t.Fatalf("CheckStats failed: %v", err)
+		t.Fatalf("LoginPlayer failed: %v", err)
+	}
+	_, err = ctx.Store.GetLoginAttempt(context.Background(), ctx.Username)
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected failed logins to be cleared after logging in, got %v", err)
 	}
 
-	if stats.Wins != 1 || stats.Losses != 1 || stats.Draws != 1 {
-		t.Errorf("Unexpected stats after draw: %+v", stats)
+	ctx.Username = "Unknown"
+	for i := 0; i < freeLoginAttempts; i++ {
+		err = loginPlayer(ctx)
 	}
+	if !errors.As(err, &throttled) {
+		t.Errorf("Expected unknown usernames to be throttled too, got %v", err)
+	}
+}
 
-	err = updateUserRecord(user.Username, ctx, true, true, false)
-	if err == nil {
-		t.Fatal("UpdateUserRecord did not fail for multiple outcomes")
+func TestRememberedSessions(t *testing.T) {
+	store := storage.NewMemory()
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+	newContext := func() *app.Context {
+		return &app.Context{
+			Store:       store,
+			SessionPath: sessionPath,
+		}
 	}
 
-	err = updateUserRecord("NonExistentUser", ctx, true, false, false)
-	if err == nil {
-		t.Fatal("UpdateUserRecord did not fail for non-existent user")
+	ctx := newContext()
+	for _, username := range []string{"First", "Second"} {
+		ctx.Username = username
+		ctx.Password = "SessionPass1!"
+		err := registerPlayer(ctx)
+		if err != nil {
+			t.Fatalf("RegisterPlayer failed: %v", err)
+		}
+		err = loginPlayer(ctx)
+		if err != nil {
+			t.Fatalf("LoginPlayer failed: %v", err)
+		}
+		err = rememberPlayer(ctx, ctx.SignedIn(username), time.Now())
+		if err != nil {
+			t.Fatalf("rememberPlayer failed: %v", err)
+		}
 	}
 
-	err = updateUserRecord(user.Username, ctx, true, false, false)
+	info, err := os.Stat(sessionPath)
 	if err != nil {
-		t.Fatalf("UpdateUserRecord failed: %v", err)
+		t.Fatalf("Expected the session file to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the session file to be private, got %v", info.Mode().Perm())
+	}
+	file, err := readSessionFile(sessionPath)
+	if err != nil {
+		t.Fatalf("readSessionFile failed: %v", err)
+	}
+	if len(file.Sessions) != 2 || file.Sessions[0].UserID != ctx.User1.ID {
+		t.Fatalf("Expected both sessions in sign-in order, got %+v", file.Sessions)
+	}
+	_, err = store.GetSessionUser(context.Background(), file.Sessions[0].Token)
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected only the token hash to be stored, got %v", err)
 	}
 
-	stats, err = checkStats(user.Username, ctx)
+	restored := newContext()
+	err = RestoreSessions(restored)
 	if err != nil {
-		t.Fatalf("CheckStats failed: %v", err)
+		t.Fatalf("RestoreSessions failed: %v", err)
+	}
+	if restored.User1 == nil || restored.User1.Username != "First" || restored.User2 == nil || restored.User2.Username != "Second" {
+		t.Fatalf("Expected both players to be restored, got %+v and %+v", restored.User1, restored.User2)
 	}
 
-	if stats.Wins != 2 || stats.Losses != 1 || stats.Draws != 1 {
-		t.Errorf("Unexpected stats after win: %+v", stats)
+	err = logoutPlayer(restored, restored.User1)
+	if err != nil {
+		t.Fatalf("logoutPlayer failed: %v", err)
+	}
+	restored = newContext()
+	err = RestoreSessions(restored)
+	if err != nil {
+		t.Fatalf("RestoreSessions failed: %v", err)
+	}
+	if len(restored.Roster) != 1 || restored.User1 == nil || restored.User1.Username != "Second" {
+		t.Errorf("Expected only Second to be restored after First signed out, got %+v", restored.Roster)
+	}
+
+	err = rememberPlayer(ctx, ctx.SignedIn("First"), time.Now().Add(-sessionLifetime-time.Hour))
+	if err != nil {
+		t.Fatalf("rememberPlayer failed: %v", err)
+	}
+	restored = newContext()
+	err = RestoreSessions(restored)
+	if err != nil {
+		t.Fatalf("RestoreSessions failed: %v", err)
+	}
+	if restored.SignedIn("First") != nil {
+		t.Errorf("Expected an expired session not to be restored, got %+v", restored.Roster)
+	}
+	file, err = readSessionFile(sessionPath)
+	if err != nil {
+		t.Fatalf("readSessionFile failed: %v", err)
+	}
+	if len(file.Sessions) != 1 || file.Sessions[0].UserID == ctx.User1.ID {
+		t.Error("Expected the expired token to be dropped from the session file")
+	}
+
+	err = deleteAccount(restored, restored.SignedIn("Second"), "SessionPass1!")
+	if err != nil {
+		t.Fatalf("deleteAccount failed: %v", err)
+	}
+	_, err = os.Stat(sessionPath)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the session file to be removed without sessions, got %v", err)
+	}
+}
+
+func TestChangePasswordRevokesSessions(t *testing.T) {
+	store := storage.NewMemory()
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+
+	ctx := &app.Context{
+		Store:       store,
+		SessionPath: sessionPath,
+		Username:    "Changer",
+		Password:    "SessionPass1!",
+	}
+	err := registerPlayer(ctx)
+	if err != nil {
+		t.Fatalf("RegisterPlayer failed: %v", err)
+	}
+	err = loginPlayer(ctx)
+	if err != nil {
+		t.Fatalf("LoginPlayer failed: %v", err)
+	}
+	user := ctx.SignedIn("Changer")
+	err = rememberPlayer(ctx, user, time.Now())
+	if err != nil {
+		t.Fatalf("rememberPlayer failed: %v", err)
+	}
+
+	err = changePassword(ctx, user, "SessionPass1!", "ChangedPass2@")
+	if err != nil {
+		t.Fatalf("changePassword failed: %v", err)
+	}
+
+	restored := &app.Context{Store: store, SessionPath: sessionPath}
+	err = RestoreSessions(restored)
+	if err != nil {
+		t.Fatalf("RestoreSessions failed: %v", err)
+	}
+	if restored.SignedIn("Changer") != nil {
+		t.Errorf("Expected the remembered login to be revoked by the password change, got %+v", restored.Roster)
 	}
 }
@@ -17,17 +17,17 @@ import (
 )
 
 func registerPlayer(ctx *app.Context) error {
-	if ctx == nil || ctx.Queries == nil {
-		return fmt.Errorf("context or Queries is nil")
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
 	}
 
 	userName := ctx.Username
-	err := checkUsername(userName)
+	err := CheckUsername(userName)
 	if err != nil {
 		return err
 	}
 
-	_, err = ctx.Queries.GetUserByName(context.Background(), userName)
+	_, err = ctx.Store.GetUserByName(context.Background(), userName)
 	if err == nil {
 		return fmt.Errorf("username already taken")
 	} else if err != sql.ErrNoRows {
@@ -57,7 +57,7 @@ func registerPlayer(ctx *app.Context) error {
 		HashedPassword: hashedPassword,
 	}
 
-	_, err = ctx.Queries.RegisterUser(context.Background(), userParams)
+	_, err = ctx.Store.RegisterUser(context.Background(), userParams)
 	if err != nil {
 		return fmt.Errorf("failed to register user: %w", err)
 	}
@@ -65,12 +65,16 @@ func registerPlayer(ctx *app.Context) error {
 	return nil
 }
 
-func checkUsername(username string) error {
+// MaxUsernameLength is the longest username a player can register.
+const MaxUsernameLength = 20
+
+// CheckUsername reports why username cannot be registered, if it cannot.
+func CheckUsername(username string) error {
 	if username == "" {
 		return fmt.Errorf("username cannot be empty")
 	}
-	if len(username) > 20 {
-		return fmt.Errorf("username cannot exceed 20 characters")
+	if len(username) > MaxUsernameLength {
+		return fmt.Errorf("username cannot exceed %d characters", MaxUsernameLength)
 	}
 	if len(username) < 3 {
 		return fmt.Errorf("username must be at least 3 characters")
@@ -119,15 +123,15 @@ type registerModel struct {
 }
 
 func SetupRegister(ctx *app.Context) tea.Model {
-	if ctx == nil || ctx.Queries == nil {
-		panic("SetupRegister called with nil ctx or nil ctx.Queries")
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupRegister called with nil ctx or nil ctx.Store")
 	}
 
 	username := textinput.New()
 	username.Prompt = "Username: "
 	username.Placeholder = "username"
 	username.Focus()
-	username.CharLimit = 20
+	username.CharLimit = MaxUsernameLength
 	username.Width = 30
 
 	password := textinput.New()
@@ -0,0 +1,102 @@
+package player
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+// seatLabel describes the color a roster player takes in the next game.
+func seatLabel(ctx *app.Context, user *app.User) string {
+	switch user {
+	case ctx.User1:
+		return " (white)"
+	case ctx.User2:
+		return " (black)"
+	}
+	return ""
+}
+
+type signOutModel struct {
+	ctx        *app.Context
+	focusIndex int
+	err        error
+}
+
+func SetupSignOut(ctx *app.Context) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupSignOut called with nil ctx or nil ctx.Store")
+	}
+
+	return &signOutModel{ctx: ctx}
+}
+
+func (m *signOutModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *signOutModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if len(m.ctx.Roster) == 0 {
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		case "up":
+			m.focusIndex = (m.focusIndex + len(m.ctx.Roster) - 1) % len(m.ctx.Roster)
+		case "down":
+			m.focusIndex = (m.focusIndex + 1) % len(m.ctx.Roster)
+		case "enter":
+			m.err = logoutPlayer(m.ctx, m.ctx.Roster[m.focusIndex])
+			if len(m.ctx.Roster) == 0 && m.err == nil {
+				return m, func() tea.Msg {
+					return messages.SwitchToMainMenu{}
+				}
+			}
+			m.focusIndex = min(m.focusIndex, len(m.ctx.Roster)-1)
+		}
+	case error:
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *signOutModel) View() string {
+	if len(m.ctx.Roster) == 0 {
+		return "Nobody is signed in.\n\nPress any key to return to main menu.\n"
+	}
+
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	s := "Sign Out\n\n"
+	for i, user := range m.ctx.Roster {
+		label := user.Username + seatLabel(m.ctx, user)
+		if i == m.focusIndex {
+			s += highlightStyle.Render(label) + "\n"
+		} else {
+			s += buttonStyle.Render(label) + "\n"
+		}
+	}
+
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += fmt.Sprintf("\n%d signed in. Use up/down arrows to navigate, enter to sign out.\n", len(m.ctx.Roster))
+	s += "Press esc to return to main menu.\n"
+
+	return s
+}
@@ -0,0 +1,209 @@
+package player
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+)
+
+const sessionLifetime = 30 * 24 * time.Hour
+
+// sessionFile holds the remembered session tokens in sign-in order. Only
+// the hashes of the tokens are stored in the database.
+type sessionFile struct {
+	Sessions []rememberedSession `json:"sessions"`
+}
+
+type rememberedSession struct {
+	UserID string `json:"user_id"`
+	Token  string `json:"token"`
+}
+
+func readSessionFile(path string) (sessionFile, error) {
+	var file sessionFile
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	} else if err != nil {
+		return file, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return file, fmt.Errorf("failed to parse session file: %w", err)
+	}
+
+	return file, nil
+}
+
+func writeSessionFile(path string, file sessionFile) error {
+	if len(file.Sessions) == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove session file: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session file: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+
+	return nil
+}
+
+// remove drops the remembered session of userID and returns its token.
+func (f *sessionFile) remove(userID string) (string, bool) {
+	for i, session := range f.Sessions {
+		if session.UserID == userID {
+			f.Sessions = slices.Delete(f.Sessions, i, i+1)
+			return session.Token, true
+		}
+	}
+	return "", false
+}
+
+func hashSessionToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// rememberPlayer creates a session for the signed-in user and keeps its
+// token in the session file.
+func rememberPlayer(ctx *app.Context, user *app.User, now time.Time) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+	if ctx.SessionPath == "" {
+		return fmt.Errorf("no session file configured")
+	}
+	if user == nil || ctx.SignedIn(user.Username) != user {
+		return fmt.Errorf("player is not signed in")
+	}
+
+	file, err := readSessionFile(ctx.SessionPath)
+	if err != nil {
+		return err
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	_, err = ctx.Store.CreateSession(context.Background(), database.CreateSessionParams{
+		TokenHash: hashSessionToken(token),
+		UserID:    user.ID,
+		CreatedAt: sql.NullString{String: now.UTC().Format(time.RFC3339), Valid: true},
+		ExpiresAt: now.UTC().Add(sessionLifetime).Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if old, ok := file.remove(user.ID); ok {
+		err = ctx.Store.DeleteSession(context.Background(), hashSessionToken(old))
+		if err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+	}
+	file.Sessions = append(file.Sessions, rememberedSession{UserID: user.ID, Token: token})
+
+	return writeSessionFile(ctx.SessionPath, file)
+}
+
+// forgetPlayer removes the remembered session of user, if any.
+func forgetPlayer(ctx *app.Context, user *app.User) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+	if ctx.SessionPath == "" {
+		return nil
+	}
+
+	file, err := readSessionFile(ctx.SessionPath)
+	if err != nil {
+		return err
+	}
+
+	token, ok := file.remove(user.ID)
+	if !ok {
+		return nil
+	}
+
+	err = ctx.Store.DeleteSession(context.Background(), hashSessionToken(token))
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return writeSessionFile(ctx.SessionPath, file)
+}
+
+// RestoreSessions signs in the players remembered in the session file.
+// Expired or revoked tokens are dropped from the file.
+func RestoreSessions(ctx *app.Context) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+	if ctx.SessionPath == "" {
+		return nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := ctx.Store.DeleteExpiredSessions(context.Background(), now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	file, err := readSessionFile(ctx.SessionPath)
+	if err != nil {
+		return err
+	}
+
+	valid := file.Sessions[:0]
+	for _, session := range file.Sessions {
+		user, err := ctx.Store.GetSessionUser(context.Background(), hashSessionToken(session.Token))
+		if err == sql.ErrNoRows || (err == nil && (user.ExpiresAt <= now || user.ID != session.UserID)) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get session: %w", err)
+		}
+		valid = append(valid, session)
+
+		ctx.Username = user.Username
+		err = checkLogin(ctx)
+		ctx.Username = ""
+		if err != nil {
+			continue
+		}
+
+		ctx.SignIn(&app.User{ID: user.ID, Username: user.Username})
+	}
+
+	if len(valid) == len(file.Sessions) {
+		return nil
+	}
+	file.Sessions = valid
+	return writeSessionFile(ctx.SessionPath, file)
+}
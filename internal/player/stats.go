@@ -3,142 +3,179 @@ package player
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/achievement"
 	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/config"
 	"github.com/deskdaniel/GoMate/internal/database"
 	"github.com/deskdaniel/GoMate/internal/messages"
-	"github.com/google/uuid"
+	"github.com/deskdaniel/GoMate/internal/rating"
 )
 
-type stats struct {
-	Username string
-	Wins     int
-	Losses   int
-	Draws    int
-}
-
-func updateUserRecord(username string, ctx *app.Context, win, loss, draw bool) error {
-	if ctx == nil || ctx.Queries == nil {
-		return fmt.Errorf("context or Queries is nil")
-	}
-
-	trueCount := 0
-	if win {
-		trueCount++
-	}
-	if loss {
-		trueCount++
-	}
-	if draw {
-		trueCount++
-	}
-	if trueCount != 1 {
-		return fmt.Errorf("exactly one of win, loss, or draw must be true")
-	}
+const ratingHistoryLength = 10
 
-	user, err := ctx.Queries.GetUserByName(context.Background(), username)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
-	}
-
-	stats, err := ctx.Queries.GetRecordsByUserID(context.Background(), user.ID)
-	if err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("failed to get stats: %w", err)
-	} else if err == sql.ErrNoRows {
-		id, err := uuid.NewUUID()
-		if err != nil {
-			return fmt.Errorf("failed to generate record ID: %w", err)
-		}
-
-		statsParams := database.RegisterRecordParams{
-			ID:        id.String(),
-			UserID:    user.ID,
-			CreatedAt: sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
-			UpdatedAt: sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true},
-			Wins:      sql.NullInt64{Int64: 0, Valid: true},
-			Losses:    sql.NullInt64{Int64: 0, Valid: true},
-			Draws:     sql.NullInt64{Int64: 0, Valid: true},
-		}
-
-		if win {
-			statsParams.Wins.Int64 = 1
-		} else if loss {
-			statsParams.Losses.Int64 = 1
-		} else if draw {
-			statsParams.Draws.Int64 = 1
-		}
-
-		_, err = ctx.Queries.RegisterRecord(context.Background(), statsParams)
-		if err != nil {
-			return fmt.Errorf("failed to create stats record: %w", err)
-		}
-		return nil
-	}
-
-	if win {
-		stats.Wins.Int64++
-	} else if loss {
-		stats.Losses.Int64++
-	} else if draw {
-		stats.Draws.Int64++
-	}
-	stats.UpdatedAt = sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true}
+type ratingChange struct {
+	Date   string `json:"date"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
 
-	updateParams := database.UpdateRecordParams{
-		UpdatedAt: stats.UpdatedAt,
-		Wins:      stats.Wins,
-		Losses:    stats.Losses,
-		Draws:     stats.Draws,
-		UserID:    user.ID,
-	}
-	_, err = ctx.Queries.UpdateRecord(context.Background(), updateParams)
-	if err != nil {
-		return fmt.Errorf("failed to update stats record: %w", err)
-	}
+type unlockedAchievement struct {
+	Date        string `json:"date"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
 
-	return nil
+type stats struct {
+	Username      string                `json:"username"`
+	Wins          int                   `json:"wins"`
+	Losses        int                   `json:"losses"`
+	Draws         int                   `json:"draws"`
+	RatingSystem  string                `json:"rating_system"`
+	Rating        int                   `json:"rating"`
+	Provisional   bool                  `json:"provisional"`
+	Glicko2       rating.Glicko2        `json:"glicko2"`
+	RatingHistory []ratingChange        `json:"rating_history"`
+	Breakdown     breakdown             `json:"breakdown"`
+	Achievements  []unlockedAchievement `json:"achievements"`
 }
 
 func checkStats(username string, ctx *app.Context) (stats, error) {
-	if ctx == nil || ctx.Queries == nil {
-		return stats{}, fmt.Errorf("context or Queries is nil")
+	if ctx == nil || ctx.Store == nil {
+		return stats{}, fmt.Errorf("context or Store is nil")
 	}
 
 	var statistics stats
 
-	user, err := ctx.Queries.GetUserByName(context.Background(), username)
+	user, err := ctx.Store.GetUserByName(context.Background(), username)
 	if err != nil {
 		return stats{}, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	statistics.Username = user.Username
+	statistics.RatingSystem = ctx.Settings().RatingSystem
 
-	sqlStats, err := ctx.Queries.GetRecordsByUserID(context.Background(), user.ID)
+	sqlStats, err := ctx.Store.GetRecordsByUserID(context.Background(), user.ID)
 	if err == sql.ErrNoRows {
 		statistics.Wins = 0
 		statistics.Losses = 0
 		statistics.Draws = 0
+		statistics.Rating = rating.InitialElo
+		statistics.Provisional = rating.IsProvisional(ctx.Settings().Elo, 0)
+		statistics.Glicko2 = rating.NewGlicko2()
 	} else if err != nil {
 		return stats{}, fmt.Errorf("failed to get stats: %w", err)
 	} else {
 		statistics.Wins = int(sqlStats.Wins.Int64)
 		statistics.Losses = int(sqlStats.Losses.Int64)
 		statistics.Draws = int(sqlStats.Draws.Int64)
+		statistics.Rating = int(sqlStats.Rating)
+		statistics.Provisional = rating.IsProvisional(ctx.Settings().Elo, sqlStats.RatedGames)
+		statistics.Glicko2 = rating.Glicko2{
+			Rating:     sqlStats.GlickoRating,
+			Deviation:  sqlStats.GlickoDeviation,
+			Volatility: sqlStats.GlickoVolatility,
+		}.DecaySince(ctx.Settings().Glicko2, sqlStats.GlickoRatedAt.String, time.Now())
+	}
+
+	statistics.Breakdown, err = checkBreakdown(ctx, user.ID)
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to get stats breakdown: %w", err)
+	}
+
+	history, err := ctx.Store.ListRatingHistoryByUserID(context.Background(), database.ListRatingHistoryByUserIDParams{
+		UserID: user.ID,
+		Limit:  ratingHistoryLength,
+	})
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to get rating history: %w", err)
+	}
+	for _, entry := range history {
+		date := entry.CreatedAt.String
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		statistics.RatingHistory = append(statistics.RatingHistory, ratingChange{
+			Date:   date,
+			Before: int(entry.RatingBefore),
+			After:  int(entry.RatingAfter),
+		})
+	}
+
+	achievements, err := ctx.Store.ListAchievementsByUserID(context.Background(), user.ID)
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	for _, unlocked := range achievements {
+		// Achievements that are no longer defined are left out.
+		definition, ok := achievement.Find(unlocked.AchievementID)
+		if !ok {
+			continue
+		}
+		date := unlocked.UnlockedAt
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		statistics.Achievements = append(statistics.Achievements, unlockedAchievement{
+			Date:        date,
+			Name:        definition.Name,
+			Description: definition.Description,
+		})
 	}
 
 	return statistics, nil
 }
 
+// WriteStats writes the stats of a player as shown on the stats screen, or
+// as JSON when asJSON is set.
+func WriteStats(ctx *app.Context, username string, w io.Writer, asJSON bool) error {
+	statistics, err := checkStats(username, ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no player named %s", username)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !asJSON {
+		_, err = io.WriteString(w, strings.TrimRight(statistics.view(), "\n")+"\n")
+		return err
+	}
+
+	// Empty lists are written as [] rather than null.
+	if statistics.RatingHistory == nil {
+		statistics.RatingHistory = []ratingChange{}
+	}
+	if statistics.Achievements == nil {
+		statistics.Achievements = []unlockedAchievement{}
+	}
+	if statistics.Breakdown.Terminations == nil {
+		statistics.Breakdown.Terminations = []groupRecord{}
+	}
+	if statistics.Breakdown.TimeControls == nil {
+		statistics.Breakdown.TimeControls = []groupRecord{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statistics)
+}
+
 type statsField int
 
 const (
-	user1Field statsField = iota
-	user2Field
+	playerField statsField = iota
+	headToHeadField
 	inputUsernameField
 	quitField
 )
@@ -146,10 +183,12 @@ const (
 type statsModel struct {
 	ctx        *app.Context
 	fields     []statsField
+	players    []*app.User
 	focusIndex int
 	input      textinput.Model
 	err        error
 	stats      *stats
+	headToHead *headToHead
 	found      bool
 }
 
@@ -157,16 +196,18 @@ func SetupStats(ctx *app.Context) tea.Model {
 	username := textinput.New()
 	username.Prompt = "Username: "
 	username.Placeholder = "Enter username"
-	username.CharLimit = 20
+	username.CharLimit = MaxUsernameLength
 	username.Width = 30
 	username.Blur()
 
+	// The player fields come first, one for each roster player.
 	var fields []statsField
-	if ctx.User1 != nil {
-		fields = append(fields, user1Field)
+	players := slices.Clone(ctx.Roster)
+	for range players {
+		fields = append(fields, playerField)
 	}
-	if ctx.User2 != nil {
-		fields = append(fields, user2Field)
+	if ctx.User1 != nil && ctx.User2 != nil && ctx.User1.ID != ctx.User2.ID {
+		fields = append(fields, headToHeadField)
 	}
 	fields = append(fields, inputUsernameField, quitField)
 
@@ -179,6 +220,7 @@ func SetupStats(ctx *app.Context) tea.Model {
 	m := statsModel{
 		ctx:        ctx,
 		fields:     fields,
+		players:    players,
 		focusIndex: 0,
 		input:      username,
 	}
@@ -197,6 +239,8 @@ type statsMsg struct {
 	Username string
 }
 
+type headToHeadMsg struct{}
+
 func (m *statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -243,17 +287,16 @@ func (m *statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			switch m.fields[m.focusIndex] {
-			case user1Field:
+			case playerField:
+				username := m.players[m.focusIndex].Username
 				return m, func() tea.Msg {
 					return statsMsg{
-						Username: m.ctx.User1.Username,
+						Username: username,
 					}
 				}
-			case user2Field:
+			case headToHeadField:
 				return m, func() tea.Msg {
-					return statsMsg{
-						Username: m.ctx.User2.Username,
-					}
+					return headToHeadMsg{}
 				}
 			case inputUsernameField:
 				username := m.input.Value()
@@ -279,6 +322,18 @@ func (m *statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.found = true
 		}
 
+		return m, nil
+	case headToHeadMsg:
+		record, err := checkHeadToHead(m.ctx, m.ctx.User1, m.ctx.User2)
+		if err != nil {
+			m.err = err
+			m.headToHead = nil
+		} else {
+			m.err = nil
+			m.headToHead = &record
+			m.found = true
+		}
+
 		return m, nil
 	case error:
 		m.err = msg
@@ -296,37 +351,31 @@ func (m *statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *statsModel) View() string {
 	s := ""
+	if m.found && m.headToHead != nil {
+		return m.headToHead.view()
+	}
 	if m.found && m.stats != nil {
-		s = fmt.Sprintf("Stats for %s:\n\n", m.stats.Username)
-		if m.stats.Wins == 0 && m.stats.Losses == 0 && m.stats.Draws == 0 {
-			s += "No games played yet.\n\n"
-		} else {
-			s += fmt.Sprintf("Wins: %d\n", m.stats.Wins)
-			s += fmt.Sprintf("Losses: %d\n", m.stats.Losses)
-			s += fmt.Sprintf("Draws: %d\n", m.stats.Draws)
-		}
-		s += "Press any key to exit.\n"
-		return s
+		return m.stats.view() + "Press any key to exit.\n"
 	}
 
 	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
 
 	s = "Check Player Stats\n\n"
-	for _, field := range m.fields {
+	for i, field := range m.fields {
 		var label string
 		switch field {
-		case user1Field:
-			label = m.ctx.User1.Username
-		case user2Field:
-			label = m.ctx.User2.Username
+		case playerField:
+			label = m.players[i].Username
+		case headToHeadField:
+			label = fmt.Sprintf("%s vs %s", m.ctx.User1.Username, m.ctx.User2.Username)
 		case inputUsernameField:
 			label = m.input.View()
 		case quitField:
 			label = "[ Quit ]"
 		}
 
-		if field == m.fields[m.focusIndex] {
+		if i == m.focusIndex {
 			s += highlightStyle.Render(label) + "\n"
 		} else {
 			s += buttonStyle.Render(label) + "\n"
@@ -340,3 +389,46 @@ func (m *statsModel) View() string {
 
 	return s
 }
+
+func (st stats) view() string {
+	s := fmt.Sprintf("Stats for %s:\n\n", st.Username)
+	if st.Wins == 0 && st.Losses == 0 && st.Draws == 0 {
+		s += "No games played yet.\n\n"
+	} else {
+		s += fmt.Sprintf("Wins: %d\n", st.Wins)
+		s += fmt.Sprintf("Losses: %d\n", st.Losses)
+		s += fmt.Sprintf("Draws: %d\n", st.Draws)
+	}
+
+	if st.RatingSystem == config.RatingSystemGlicko2 {
+		low, high := st.Glicko2.Interval()
+		s += fmt.Sprintf("Rating: %.0f ± %.0f\n", st.Glicko2.Rating, 1.96*st.Glicko2.Deviation)
+		s += fmt.Sprintf("95%% confidence interval: %.0f-%.0f\n", low, high)
+		s += fmt.Sprintf("Deviation: %.0f, volatility: %.4f\n\n", st.Glicko2.Deviation, st.Glicko2.Volatility)
+	} else {
+		s += fmt.Sprintf("Rating: %d", st.Rating)
+		if st.Provisional {
+			s += " (provisional)"
+		}
+		s += "\n\n"
+	}
+
+	if st.RatingSystem == config.RatingSystemElo && len(st.RatingHistory) > 0 {
+		s += "Recent rating changes:\n"
+		for _, change := range st.RatingHistory {
+			s += fmt.Sprintf("%s  %d -> %d (%+d)\n", change.Date, change.Before, change.After, change.After-change.Before)
+		}
+		s += "\n"
+	}
+	if st.Breakdown.Games > 0 {
+		s += st.Breakdown.view()
+	}
+	if len(st.Achievements) > 0 {
+		s += fmt.Sprintf("Achievements (%d of %d):\n", len(st.Achievements), len(achievement.Definitions))
+		for _, unlocked := range st.Achievements {
+			s += fmt.Sprintf("%s  %s - %s\n", unlocked.Date, unlocked.Name, unlocked.Description)
+		}
+		s += "\n"
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package player
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+)
+
+const (
+	// freeLoginAttempts failed logins are allowed without waiting, after
+	// that the wait doubles with every failure starting at one second.
+	freeLoginAttempts = 3
+	// lockoutLoginAttempts failed logins lock the username for loginLockout.
+	lockoutLoginAttempts = 10
+	loginLockout         = 15 * time.Minute
+	// Failed logins older than loginAttemptsExpire are forgotten.
+	loginAttemptsExpire = 24 * time.Hour
+)
+
+type loginThrottledError struct {
+	until time.Time
+}
+
+func (e *loginThrottledError) Error() string {
+	wait := max(time.Until(e.until).Round(time.Second), time.Second)
+	return fmt.Sprintf("too many failed attempts, try again in %s", wait)
+}
+
+// loginDelay returns how long to wait after the given number of failed
+// logins.
+func loginDelay(failed int64) time.Duration {
+	switch {
+	case failed < freeLoginAttempts:
+		return 0
+	case failed >= lockoutLoginAttempts:
+		return loginLockout
+	}
+	return time.Second << (failed - freeLoginAttempts)
+}
+
+// checkLoginThrottle returns a *loginThrottledError while username has to
+// wait before the next login attempt.
+func checkLoginThrottle(ctx *app.Context, username string, now time.Time) error {
+	attempt, err := ctx.Store.GetLoginAttempt(context.Background(), username)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	if !attempt.LockedUntil.Valid {
+		return nil
+	}
+
+	until, err := time.Parse(time.RFC3339Nano, attempt.LockedUntil.String)
+	if err != nil {
+		return fmt.Errorf("failed to parse lockout time: %w", err)
+	}
+	if now.Before(until) {
+		return &loginThrottledError{until: until}
+	}
+
+	return nil
+}
+
+// recordFailedLogin counts a failed login for username and returns a
+// *loginThrottledError when the next attempt has to wait.
+func recordFailedLogin(ctx *app.Context, username string, now time.Time) error {
+	attempt, err := ctx.Store.GetLoginAttempt(context.Background(), username)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	failed := attempt.FailedAttempts
+	lastFailed, err := time.Parse(time.RFC3339Nano, attempt.LastFailedAt)
+	if err != nil || now.Sub(lastFailed) > loginAttemptsExpire {
+		failed = 0
+	}
+	failed++
+
+	params := database.SaveLoginAttemptParams{
+		Username:       username,
+		FailedAttempts: failed,
+		LastFailedAt:   now.Format(time.RFC3339Nano),
+	}
+	delay := loginDelay(failed)
+	if delay > 0 {
+		params.LockedUntil = sql.NullString{String: now.Add(delay).Format(time.RFC3339Nano), Valid: true}
+	}
+
+	_, err = ctx.Store.SaveLoginAttempt(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("failed to save login attempt: %w", err)
+	}
+
+	if delay == 0 {
+		return nil
+	}
+	if failed >= lockoutLoginAttempts {
+		log.Printf("login: %q locked out until %s after %d failed attempts", username, params.LockedUntil.String, failed)
+	}
+	return &loginThrottledError{until: now.Add(delay)}
+}
@@ -0,0 +1,39 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/deskdaniel/GoMate/internal/config"
+)
+
+const InitialElo = 1500
+
+const (
+	ScoreWin  = 1.0
+	ScoreDraw = 0.5
+	ScoreLoss = 0.0
+)
+
+func ExpectedScore(rating, opponent int64) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponent-rating)/400))
+}
+
+// KFactor returns the provisional K-factor while a player has fewer than
+// the configured number of rated games, and the regular one afterwards.
+func KFactor(cfg config.EloConfig, ratedGames int64) float64 {
+	if ratedGames < cfg.ProvisionalGames {
+		return cfg.ProvisionalKFactor
+	}
+	return cfg.KFactor
+}
+
+func IsProvisional(cfg config.EloConfig, ratedGames int64) bool {
+	return ratedGames < cfg.ProvisionalGames
+}
+
+// Elo returns the new rating of a player after scoring score (1 win,
+// 0.5 draw, 0 loss) against an opponent rated opponent.
+func Elo(rating, opponent int64, score, kFactor float64) int64 {
+	change := kFactor * (score - ExpectedScore(rating, opponent))
+	return rating + int64(math.Round(change))
+}
@@ -0,0 +1,75 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/config"
+)
+
+func TestExpectedScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		rating   int64
+		opponent int64
+		expected float64
+	}{
+		{"equal ratings", 1500, 1500, 0.5},
+		{"400 points stronger", 1900, 1500, 10.0 / 11.0},
+		{"400 points weaker", 1500, 1900, 1.0 / 11.0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ExpectedScore(test.rating, test.opponent)
+			if math.Abs(got-test.expected) > 1e-9 {
+				t.Errorf("ExpectedScore(%d, %d) = %v, want %v", test.rating, test.opponent, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestElo(t *testing.T) {
+	tests := []struct {
+		name     string
+		rating   int64
+		opponent int64
+		score    float64
+		kFactor  float64
+		expected int64
+	}{
+		{"win between equals", 1500, 1500, ScoreWin, 20, 1510},
+		{"loss between equals", 1500, 1500, ScoreLoss, 20, 1490},
+		{"draw between equals", 1500, 1500, ScoreDraw, 20, 1500},
+		{"draw against stronger", 1500, 1900, ScoreDraw, 20, 1508},
+		{"upset win with provisional k", 1500, 1900, ScoreWin, 40, 1536},
+		{"expected win", 1900, 1500, ScoreWin, 20, 1902},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Elo(test.rating, test.opponent, test.score, test.kFactor)
+			if got != test.expected {
+				t.Errorf("Elo(%d, %d, %v, %v) = %d, want %d", test.rating, test.opponent, test.score, test.kFactor, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestKFactor(t *testing.T) {
+	cfg := config.EloConfig{
+		KFactor:            20,
+		ProvisionalKFactor: 40,
+		ProvisionalGames:   30,
+	}
+
+	if KFactor(cfg, 0) != 40 || !IsProvisional(cfg, 0) {
+		t.Error("Expected new player to use provisional K-factor")
+	}
+	if KFactor(cfg, 29) != 40 || !IsProvisional(cfg, 29) {
+		t.Error("Expected player with 29 games to still be provisional")
+	}
+	if KFactor(cfg, 30) != 20 || IsProvisional(cfg, 30) {
+		t.Error("Expected player with 30 games to use regular K-factor")
+	}
+}
@@ -0,0 +1,148 @@
+package rating
+
+import (
+	"math"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/config"
+)
+
+const (
+	InitialGlicko2Rating     = 1500.0
+	InitialGlicko2Deviation  = 350.0
+	InitialGlicko2Volatility = 0.06
+
+	glicko2Scale     = 173.7178
+	glicko2Tolerance = 0.000001
+)
+
+type Glicko2 struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+type Glicko2Result struct {
+	Opponent Glicko2
+	Score    float64
+}
+
+func NewGlicko2() Glicko2 {
+	return Glicko2{
+		Rating:     InitialGlicko2Rating,
+		Deviation:  InitialGlicko2Deviation,
+		Volatility: InitialGlicko2Volatility,
+	}
+}
+
+// Decay grows the rating deviation for the given number of rating periods
+// without games, never beyond the deviation of a new player.
+func (p Glicko2) Decay(periods float64) Glicko2 {
+	if periods <= 0 {
+		return p
+	}
+
+	phi := p.Deviation / glicko2Scale
+	phi = math.Sqrt(phi*phi + periods*p.Volatility*p.Volatility)
+	p.Deviation = math.Min(phi*glicko2Scale, InitialGlicko2Deviation)
+
+	return p
+}
+
+// DecaySince grows the deviation for every whole rating period between
+// the player's last rated game and now.
+func (p Glicko2) DecaySince(cfg config.Glicko2Config, ratedAt string, now time.Time) Glicko2 {
+	return p.Decay(InactivePeriods(cfg, ratedAt, now))
+}
+
+// InactivePeriods returns the number of whole rating periods between the
+// player's last rated game, stored as RFC3339, and now.
+func InactivePeriods(cfg config.Glicko2Config, ratedAt string, now time.Time) float64 {
+	last, err := time.Parse(time.RFC3339, ratedAt)
+	if err != nil || !now.After(last) {
+		return 0
+	}
+
+	period := time.Duration(cfg.RatingPeriodDays * float64(24*time.Hour))
+	return math.Floor(float64(now.Sub(last)) / float64(period))
+}
+
+// Interval returns the 95% confidence interval of the rating.
+func (p Glicko2) Interval() (float64, float64) {
+	return p.Rating - 1.96*p.Deviation, p.Rating + 1.96*p.Deviation
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glicko2E(mu, muOpponent, phiOpponent float64) float64 {
+	return 1 / (1 + math.Exp(-glicko2G(phiOpponent)*(mu-muOpponent)))
+}
+
+// Update rates the player over a single rating period with the given
+// results, following Glickman's "Example of the Glicko-2 system".
+func (p Glicko2) Update(results []Glicko2Result, tau float64) Glicko2 {
+	if len(results) == 0 {
+		return p.Decay(1)
+	}
+
+	mu := (p.Rating - InitialGlicko2Rating) / glicko2Scale
+	phi := p.Deviation / glicko2Scale
+	sigma := p.Volatility
+
+	var vInverse, deltaSum float64
+	for _, result := range results {
+		muOpponent := (result.Opponent.Rating - InitialGlicko2Rating) / glicko2Scale
+		phiOpponent := result.Opponent.Deviation / glicko2Scale
+		g := glicko2G(phiOpponent)
+		e := glicko2E(mu, muOpponent, phiOpponent)
+		vInverse += g * g * e * (1 - e)
+		deltaSum += g * (result.Score - e)
+	}
+	v := 1 / vInverse
+	delta := v * deltaSum
+
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * (phi*phi + v + ex) * (phi*phi + v + ex)
+		return num/den - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Tolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA = fA / 2
+		}
+		B, fB = C, fC
+	}
+	newSigma := math.Exp(A / 2)
+
+	phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*deltaSum
+
+	return Glicko2{
+		Rating:     newMu*glicko2Scale + InitialGlicko2Rating,
+		Deviation:  newPhi * glicko2Scale,
+		Volatility: newSigma,
+	}
+}
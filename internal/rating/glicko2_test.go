@@ -0,0 +1,89 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/config"
+)
+
+func TestGlicko2Update(t *testing.T) {
+	// Example from Glickman's "Example of the Glicko-2 system".
+	player := Glicko2{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Glicko2Result{
+		{Opponent: Glicko2{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: ScoreWin},
+		{Opponent: Glicko2{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: ScoreLoss},
+		{Opponent: Glicko2{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: ScoreLoss},
+	}
+
+	updated := player.Update(results, 0.5)
+	if math.Abs(updated.Rating-1464.06) > 0.01 {
+		t.Errorf("Expected rating 1464.06, got %.2f", updated.Rating)
+	}
+	if math.Abs(updated.Deviation-151.52) > 0.01 {
+		t.Errorf("Expected deviation 151.52, got %.2f", updated.Deviation)
+	}
+	if math.Abs(updated.Volatility-0.05999) > 0.00001 {
+		t.Errorf("Expected volatility 0.05999, got %.5f", updated.Volatility)
+	}
+}
+
+func TestGlicko2Decay(t *testing.T) {
+	player := Glicko2{Rating: 1500, Deviation: 50, Volatility: 0.06}
+
+	if player.Decay(0) != player {
+		t.Error("Expected no decay without elapsed rating periods")
+	}
+
+	decayed := player.Decay(10)
+	if decayed.Deviation <= player.Deviation {
+		t.Errorf("Expected deviation to grow after inactivity, got %.2f", decayed.Deviation)
+	}
+	if decayed.Rating != player.Rating || decayed.Volatility != player.Volatility {
+		t.Error("Expected decay to change only the deviation")
+	}
+
+	decayed = player.Decay(1000000)
+	if decayed.Deviation != InitialGlicko2Deviation {
+		t.Errorf("Expected deviation to be capped at %.0f, got %.2f", InitialGlicko2Deviation, decayed.Deviation)
+	}
+
+	if player.Update(nil, 0.5).Deviation <= player.Deviation {
+		t.Error("Expected a period without games to increase deviation")
+	}
+}
+
+func TestGlicko2Interval(t *testing.T) {
+	low, high := Glicko2{Rating: 1600, Deviation: 100}.Interval()
+	if low != 1404 || high != 1796 {
+		t.Errorf("Expected interval 1404-1796, got %.0f-%.0f", low, high)
+	}
+}
+
+func TestInactivePeriods(t *testing.T) {
+	cfg := config.Glicko2Config{Tau: 0.5, RatingPeriodDays: 7}
+	now := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ratedAt  string
+		expected float64
+	}{
+		{"never rated", "", 0},
+		{"invalid time", "yesterday", 0},
+		{"same period", "2024-03-25T12:00:00Z", 0},
+		{"one period", "2024-03-22T12:00:00Z", 1},
+		{"partial periods are dropped", "2024-03-01T00:00:00Z", 4},
+		{"in the future", "2024-04-01T00:00:00Z", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := InactivePeriods(cfg, test.ratedAt, now)
+			if got != test.expected {
+				t.Errorf("Expected %v periods, got %v", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package results
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/achievement"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/config"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/rating"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	WhiteWins = "1-0"
+	BlackWins = "0-1"
+	Draw      = "1/2-1/2"
+)
+
+// How a game can end.
+const (
+	Checkmate            = "checkmate"
+	Resignation          = "resignation"
+	Stalemate            = "stalemate"
+	Agreement            = "agreement"
+	InsufficientMaterial = "insufficient material"
+	FiftyMoveRule        = "fifty-move rule"
+)
+
+// Terminations lists every way a game played in GoMate can end.
+var Terminations = []string{Checkmate, Resignation, Stalemate, Agreement, InsufficientMaterial, FiftyMoveRule}
+
+// WhiteScore returns white's score for a game result.
+func WhiteScore(result string) float64 {
+	switch result {
+	case WhiteWins:
+		return rating.ScoreWin
+	case BlackWins:
+		return rating.ScoreLoss
+	}
+	return rating.ScoreDraw
+}
+
+// FormatPoints writes a score with quarters and halves as ¼, ½ and ¾, e.g.
+// 2½. Sonneborn-Berger scores can end in quarters.
+func FormatPoints(points float64) string {
+	whole := int(points)
+	fraction := map[float64]string{0.25: "¼", 0.5: "½", 0.75: "¾"}[points-float64(whole)]
+	if whole == 0 && fraction != "" {
+		return fraction
+	}
+	return fmt.Sprint(whole) + fraction
+}
+
+// Record stores a finished game together with the outcome for every
+// registered player and, when both players are registered, their new
+// ratings. Registered players unlock the achievements the game earns. A
+// tournament or match game also completes its pairing.
+// Everything is written in a single transaction.
+func Record(ctx *app.Context, game database.CreateGameParams) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+	if game.Result != WhiteWins && game.Result != BlackWins && game.Result != Draw {
+		return fmt.Errorf("invalid result %q", game.Result)
+	}
+
+	return ctx.Store.WithTx(context.Background(), func(store storage.Store) error {
+		_, err := store.CreateGame(context.Background(), game)
+		if err != nil {
+			return fmt.Errorf("failed to store game: %w", err)
+		}
+
+		whiteScore := WhiteScore(game.Result)
+		now := sql.NullString{String: time.Now().Format(time.RFC3339), Valid: true}
+		players := []struct {
+			userID sql.NullString
+			score  float64
+		}{
+			{game.WhiteUserID, whiteScore},
+			{game.BlackUserID, 1 - whiteScore},
+		}
+		for _, player := range players {
+			if !player.userID.Valid {
+				continue
+			}
+			err = addResult(store, player.userID.String, player.score, now)
+			if err != nil {
+				return fmt.Errorf("failed to update record: %w", err)
+			}
+			err = unlockAchievements(store, player.userID.String, game, now.String)
+			if err != nil {
+				return fmt.Errorf("failed to unlock achievements: %w", err)
+			}
+		}
+
+		if game.WhiteUserID.Valid && game.BlackUserID.Valid {
+			err = updateRatings(store, ctx.Settings(), game)
+			if err != nil {
+				return fmt.Errorf("failed to update ratings: %w", err)
+			}
+		}
+
+		if ctx.Pairing != nil {
+			return recordPairing(store, ctx.Pairing, game)
+		}
+
+		return nil
+	})
+}
+
+func addResult(store storage.Records, userID string, score float64, now sql.NullString) error {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate record ID: %w", err)
+	}
+
+	params := database.AddResultParams{
+		ID:        id.String(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Wins:      sql.NullInt64{Int64: 0, Valid: true},
+		Losses:    sql.NullInt64{Int64: 0, Valid: true},
+		Draws:     sql.NullInt64{Int64: 0, Valid: true},
+	}
+	switch score {
+	case rating.ScoreWin:
+		params.Wins.Int64 = 1
+	case rating.ScoreLoss:
+		params.Losses.Int64 = 1
+	default:
+		params.Draws.Int64 = 1
+	}
+
+	_, err = store.AddResult(context.Background(), params)
+	return err
+}
+
+// userScore returns the user's score in a game.
+func userScore(game database.Game, userID string) float64 {
+	score := WhiteScore(game.Result)
+	if game.WhiteUserID.String != userID {
+		score = 1 - score
+	}
+	return score
+}
+
+// unlockAchievements stores the achievements a player earns with a finished
+// game that they have not unlocked before.
+func unlockAchievements(store storage.Store, userID string, game database.CreateGameParams, unlockedAt string) error {
+	white := game.WhiteUserID.String == userID
+	score := WhiteScore(game.Result)
+	if !white {
+		score = 1 - score
+	}
+
+	earlier, err := store.ListGamesByUserID(context.Background(), sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to get games: %w", err)
+	}
+	earlier = slices.DeleteFunc(earlier, func(g database.Game) bool {
+		return g.ID == game.ID
+	})
+
+	facts := achievement.Game{
+		White: white,
+		Score: score,
+		Moves: strings.Fields(game.Moves),
+		Games: len(earlier) + 1,
+	}
+	if score == rating.ScoreWin {
+		facts.WinStreak = 1
+		for _, g := range earlier {
+			if userScore(g, userID) != rating.ScoreWin {
+				break
+			}
+			facts.WinStreak++
+		}
+	}
+
+	for _, definition := range achievement.Unlocked(facts) {
+		_, err = store.UnlockAchievement(context.Background(), database.UnlockAchievementParams{
+			UserID:        userID,
+			AchievementID: definition.ID,
+			GameID:        game.ID,
+			UnlockedAt:    unlockedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateRatings updates both rating systems so that switching
+// rating_system in the config keeps every player's history.
+func updateRatings(store storage.Records, cfg config.Config, game database.CreateGameParams) error {
+	white, err := store.GetRecordsByUserID(context.Background(), game.WhiteUserID.String)
+	if err != nil {
+		return fmt.Errorf("failed to get white player's record: %w", err)
+	}
+	black, err := store.GetRecordsByUserID(context.Background(), game.BlackUserID.String)
+	if err != nil {
+		return fmt.Errorf("failed to get black player's record: %w", err)
+	}
+
+	whiteScore := WhiteScore(game.Result)
+	whiteRating := rating.Elo(white.Rating, black.Rating, whiteScore, rating.KFactor(cfg.Elo, white.RatedGames))
+	blackRating := rating.Elo(black.Rating, white.Rating, 1-whiteScore, rating.KFactor(cfg.Elo, black.RatedGames))
+
+	endedAt := time.Now()
+	whiteGlicko := currentGlicko2(cfg.Glicko2, white, endedAt)
+	blackGlicko := currentGlicko2(cfg.Glicko2, black, endedAt)
+
+	now := sql.NullString{String: endedAt.Format(time.RFC3339), Valid: true}
+	changes := []struct {
+		record database.Record
+		after  int64
+		glicko rating.Glicko2
+	}{
+		{white, whiteRating, whiteGlicko.Update([]rating.Glicko2Result{{Opponent: blackGlicko, Score: whiteScore}}, cfg.Glicko2.Tau)},
+		{black, blackRating, blackGlicko.Update([]rating.Glicko2Result{{Opponent: whiteGlicko, Score: 1 - whiteScore}}, cfg.Glicko2.Tau)},
+	}
+	for _, change := range changes {
+		_, err = store.UpdateRating(context.Background(), database.UpdateRatingParams{
+			UpdatedAt: now,
+			Rating:    change.after,
+			UserID:    change.record.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = store.UpdateGlicko2(context.Background(), database.UpdateGlicko2Params{
+			UpdatedAt:        now,
+			GlickoRating:     change.glicko.Rating,
+			GlickoDeviation:  change.glicko.Deviation,
+			GlickoVolatility: change.glicko.Volatility,
+			GlickoRatedAt:    now,
+			UserID:           change.record.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return fmt.Errorf("failed to generate rating history ID: %w", err)
+		}
+		_, err = store.CreateRatingHistory(context.Background(), database.CreateRatingHistoryParams{
+			ID:           id.String(),
+			UserID:       change.record.UserID,
+			GameID:       game.ID,
+			CreatedAt:    now,
+			RatingBefore: change.record.Rating,
+			RatingAfter:  change.after,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// currentGlicko2 returns the player's Glicko-2 rating with the deviation
+// grown for every rating period without games.
+func currentGlicko2(cfg config.Glicko2Config, record database.Record, now time.Time) rating.Glicko2 {
+	player := rating.Glicko2{
+		Rating:     record.GlickoRating,
+		Deviation:  record.GlickoDeviation,
+		Volatility: record.GlickoVolatility,
+	}
+	return player.DecaySince(cfg, record.GlickoRatedAt.String, now)
+}
+
+// recordPairing stores the result with the tournament pairing or match game
+// the game was played for. A pairing takes a single result.
+func recordPairing(store storage.Store, pairing *app.Pairing, game database.CreateGameParams) error {
+	result := sql.NullString{String: game.Result, Valid: true}
+	gameID := sql.NullString{String: game.ID, Valid: true}
+
+	if pairing.MatchID != "" {
+		updated, err := store.SetMatchGameResult(context.Background(), database.SetMatchGameResultParams{
+			Result: result,
+			GameID: gameID,
+			ID:     pairing.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update match game: %w", err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("match game already has a result")
+		}
+		return nil
+	}
+
+	updated, err := store.SetTournamentPairingResult(context.Background(), database.SetTournamentPairingResultParams{
+		Result: result,
+		GameID: gameID,
+		ID:     pairing.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update tournament pairing: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("tournament pairing already has a result")
+	}
+	return nil
+}
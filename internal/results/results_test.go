@@ -0,0 +1,242 @@
+package results
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/deskdaniel/GoMate/internal/storage/storagetest"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+func gameParams(id string, white, black *app.User, result string) database.CreateGameParams {
+	return database.CreateGameParams{
+		ID:          id,
+		WhiteUserID: storagetest.UserID(white),
+		BlackUserID: storagetest.UserID(black),
+		WhiteName:   "White",
+		BlackName:   "Black",
+		Result:      result,
+		Termination: "checkmate",
+		StartedAt:   "2024-01-01T10:00:00Z",
+		EndedAt:     "2024-01-01T11:00:00Z",
+		TimeControl: "-",
+	}
+}
+
+func TestRecord(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	alice := storagetest.RegisterUser(t, store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, store, "Bob-id", "Bob")
+	var guest *app.User
+
+	games := []database.CreateGameParams{
+		gameParams("game-1", alice, bob, WhiteWins),
+		gameParams("game-2", bob, alice, WhiteWins),
+		gameParams("game-3", alice, bob, Draw),
+		gameParams("game-4", guest, alice, BlackWins),
+		gameParams("game-5", guest, guest, Draw),
+	}
+	for _, game := range games {
+		err := Record(ctx, game)
+		if err != nil {
+			t.Fatalf("Record(%s) failed: %v", game.ID, err)
+		}
+	}
+
+	expected := []struct {
+		userID     string
+		wins       int64
+		losses     int64
+		draws      int64
+		ratedGames int64
+	}{
+		{alice.ID, 2, 1, 1, 3},
+		{bob.ID, 1, 1, 1, 3},
+	}
+	for _, want := range expected {
+		record, err := store.GetRecordsByUserID(context.Background(), want.userID)
+		if err != nil {
+			t.Fatalf("GetRecordsByUserID failed: %v", err)
+		}
+		if record.Wins.Int64 != want.wins || record.Losses.Int64 != want.losses || record.Draws.Int64 != want.draws {
+			t.Errorf("Expected %d/%d/%d for %s, got %d/%d/%d", want.wins, want.losses, want.draws, want.userID,
+				record.Wins.Int64, record.Losses.Int64, record.Draws.Int64)
+		}
+		if record.RatedGames != want.ratedGames {
+			t.Errorf("Expected %d rated games for %s, got %d", want.ratedGames, want.userID, record.RatedGames)
+		}
+	}
+
+	for _, game := range games {
+		_, err := store.GetGame(context.Background(), game.ID)
+		if err != nil {
+			t.Errorf("Expected game %s to be stored: %v", game.ID, err)
+		}
+	}
+}
+
+func TestRecordIsAtomic(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	store := storage.NewSQLite(db)
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	alice := storagetest.RegisterUser(t, store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, store, "Bob-id", "Bob")
+
+	err := Record(ctx, gameParams("game-1", alice, bob, WhiteWins))
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func() error
+		game  database.CreateGameParams
+	}{
+		{"invalid result", nil, gameParams("game-2", alice, bob, "1-1")},
+		{"duplicate game", nil, gameParams("game-1", alice, bob, WhiteWins)},
+		{"failure after records are updated", func() error {
+			_, err := db.Exec("DROP TABLE rating_history")
+			return err
+		}, gameParams("game-3", alice, bob, BlackWins)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.setup != nil {
+				err := test.setup()
+				if err != nil {
+					t.Fatalf("Setup failed: %v", err)
+				}
+			}
+
+			err := Record(ctx, test.game)
+			if err == nil {
+				t.Fatal("Expected Record to fail")
+			}
+
+			for _, userID := range []string{alice.ID, bob.ID} {
+				record, err := store.GetRecordsByUserID(context.Background(), userID)
+				if err != nil {
+					t.Fatalf("GetRecordsByUserID failed: %v", err)
+				}
+				if record.Wins.Int64+record.Losses.Int64+record.Draws.Int64 != 1 || record.RatedGames != 1 {
+					t.Errorf("Expected only the first game to count for %s, got %+v", userID, record)
+				}
+			}
+			if test.game.ID != "game-1" {
+				_, err = store.GetGame(context.Background(), test.game.ID)
+				if err != sql.ErrNoRows {
+					t.Errorf("Expected game %s to be rolled back, got %v", test.game.ID, err)
+				}
+			}
+		})
+	}
+
+	err = Record(nil, gameParams("game-4", alice, bob, Draw))
+	if err == nil {
+		t.Error("Expected Record to fail without a context")
+	}
+}
+
+func TestRecordUnlocksAchievements(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := &app.Context{
+		Store: store,
+	}
+
+	alice := storagetest.RegisterUser(t, store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, store, "Bob-id", "Bob")
+
+	// Alice wins three in a row after a loss, the last one with black.
+	games := []database.CreateGameParams{
+		gameParams("game-1", alice, bob, BlackWins),
+		gameParams("game-2", alice, bob, WhiteWins),
+		gameParams("game-3", alice, bob, WhiteWins),
+		gameParams("game-4", bob, alice, BlackWins),
+	}
+	for _, game := range games {
+		// Games ending in the same second still count in the order they
+		// were recorded.
+		game.EndedAt = "2024-01-01T11:00:00Z"
+		err := Record(ctx, game)
+		if err != nil {
+			t.Fatalf("Record(%s) failed: %v", game.ID, err)
+		}
+	}
+
+	expected := []struct {
+		userID string
+		want   map[string]string
+	}{
+		{alice.ID, map[string]string{
+			"first-game":   "game-1",
+			"first-win":    "game-2",
+			"quick-win":    "game-2",
+			"black-win":    "game-4",
+			"win-streak-3": "game-4",
+		}},
+		{bob.ID, map[string]string{
+			"first-game": "game-1",
+			"first-win":  "game-1",
+			"black-win":  "game-1",
+			"quick-win":  "game-1",
+		}},
+	}
+	for _, want := range expected {
+		achievements, err := store.ListAchievementsByUserID(context.Background(), want.userID)
+		if err != nil {
+			t.Fatalf("ListAchievementsByUserID failed: %v", err)
+		}
+		got := map[string]string{}
+		for _, unlocked := range achievements {
+			got[unlocked.AchievementID] = unlocked.GameID
+		}
+		if !reflect.DeepEqual(got, want.want) {
+			t.Errorf("Expected achievements %v for %s, got %v", want.want, want.userID, got)
+		}
+	}
+}
+
+func TestFormatPoints(t *testing.T) {
+	tests := map[float64]string{
+		0:    "0",
+		0.25: "¼",
+		0.5:  "½",
+		2:    "2",
+		2.5:  "2½",
+		2.75: "2¾",
+	}
+	for points, want := range tests {
+		if got := FormatPoints(points); got != want {
+			t.Errorf("FormatPoints(%v): expected %q, got %q", points, want, got)
+		}
+	}
+}
@@ -0,0 +1,1286 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+)
+
+var _ Store = (*Memory)(nil)
+
+type memoryData struct {
+	users         map[string]database.User
+	loginAttempts map[string]database.LoginAttempt
+	sessions      map[string]database.Session
+	records       map[string]database.Record
+	games         map[string]database.Game
+	// gameOrder numbers the games in the order they were stored, like the
+	// rowid of the games table.
+	gameOrder     map[string]int
+	lastGame      int
+	savedGames    map[string]database.SavedGame
+	ratingHistory []database.RatingHistory
+	tournaments   map[string]database.Tournament
+	players       map[string]database.TournamentPlayer
+	pairings      map[string]database.TournamentPairing
+	matches       map[string]database.Match
+	matchGames    map[string]database.MatchGame
+	achievements  []database.Achievement
+}
+
+func (d *memoryData) clone() *memoryData {
+	return &memoryData{
+		users:         maps.Clone(d.users),
+		loginAttempts: maps.Clone(d.loginAttempts),
+		sessions:      maps.Clone(d.sessions),
+		records:       maps.Clone(d.records),
+		games:         maps.Clone(d.games),
+		gameOrder:     maps.Clone(d.gameOrder),
+		lastGame:      d.lastGame,
+		savedGames:    maps.Clone(d.savedGames),
+		ratingHistory: slices.Clone(d.ratingHistory),
+		tournaments:   maps.Clone(d.tournaments),
+		players:       maps.Clone(d.players),
+		pairings:      maps.Clone(d.pairings),
+		matches:       maps.Clone(d.matches),
+		matchGames:    maps.Clone(d.matchGames),
+		achievements:  slices.Clone(d.achievements),
+	}
+}
+
+// Memory is a Store that keeps everything in memory. It is meant for tests
+// and mirrors the behavior of the SQLite queries.
+type Memory struct {
+	mu   sync.Mutex
+	txMu *sync.Mutex
+	data *memoryData
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		txMu: &sync.Mutex{},
+		data: &memoryData{
+			users:         map[string]database.User{},
+			loginAttempts: map[string]database.LoginAttempt{},
+			sessions:      map[string]database.Session{},
+			records:       map[string]database.Record{},
+			games:         map[string]database.Game{},
+			gameOrder:     map[string]int{},
+			savedGames:    map[string]database.SavedGame{},
+			tournaments:   map[string]database.Tournament{},
+			players:       map[string]database.TournamentPlayer{},
+			pairings:      map[string]database.TournamentPairing{},
+			matches:       map[string]database.Match{},
+			matchGames:    map[string]database.MatchGame{},
+		},
+	}
+}
+
+// WithTx runs fn against a copy of the data, which replaces the data when
+// fn succeeds. Transactions are serialized.
+func (m *Memory) WithTx(ctx context.Context, fn func(Store) error) error {
+	m.txMu.Lock()
+	defer m.txMu.Unlock()
+
+	m.mu.Lock()
+	tx := &Memory{data: m.data.clone(), txMu: &sync.Mutex{}}
+	m.mu.Unlock()
+
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.data = tx.data
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) GetUserByName(ctx context.Context, username string) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.data.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+func (m *Memory) RegisterUser(ctx context.Context, arg database.RegisterUserParams) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.users[arg.ID]; ok {
+		return database.User{}, fmt.Errorf("user %s already exists", arg.ID)
+	}
+	for _, user := range m.data.users {
+		if user.Username == arg.Username {
+			return database.User{}, fmt.Errorf("username %s already exists", arg.Username)
+		}
+	}
+
+	user := database.User{
+		ID:             arg.ID,
+		Username:       arg.Username,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		HashedPassword: arg.HashedPassword,
+	}
+	m.data.users[user.ID] = user
+	return user, nil
+}
+
+func (m *Memory) UpdatePassword(ctx context.Context, arg database.UpdatePasswordParams) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.data.users[arg.ID]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	user.HashedPassword = arg.HashedPassword
+	user.UpdatedAt = arg.UpdatedAt
+
+	m.data.users[arg.ID] = user
+	return user, nil
+}
+
+func (m *Memory) UpdateUsername(ctx context.Context, arg database.UpdateUsernameParams) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.data.users[arg.ID]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	for _, other := range m.data.users {
+		if other.ID != arg.ID && other.Username == arg.Username {
+			return database.User{}, fmt.Errorf("username %s already exists", arg.Username)
+		}
+	}
+	user.Username = arg.Username
+	user.UpdatedAt = arg.UpdatedAt
+
+	m.data.users[arg.ID] = user
+	return user, nil
+}
+
+// DeleteUser removes the user like the foreign keys do in SQLite: records,
+// sessions, saved games and rating history go with the user, while
+// finished games, tournament players and matches are kept without the user.
+func (m *Memory) DeleteUser(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.users, id)
+	delete(m.data.records, id)
+	maps.DeleteFunc(m.data.sessions, func(_ string, session database.Session) bool {
+		return session.UserID == id
+	})
+	for savedID, saved := range m.data.savedGames {
+		if saved.WhiteUserID.String == id || saved.BlackUserID.String == id {
+			delete(m.data.savedGames, savedID)
+		}
+	}
+	m.data.ratingHistory = slices.DeleteFunc(m.data.ratingHistory, func(entry database.RatingHistory) bool {
+		return entry.UserID == id
+	})
+	m.data.achievements = slices.DeleteFunc(m.data.achievements, func(achievement database.Achievement) bool {
+		return achievement.UserID == id
+	})
+	for gameID, game := range m.data.games {
+		if game.WhiteUserID.String == id {
+			game.WhiteUserID = sql.NullString{}
+		}
+		if game.BlackUserID.String == id {
+			game.BlackUserID = sql.NullString{}
+		}
+		m.data.games[gameID] = game
+	}
+	for playerID, player := range m.data.players {
+		if player.UserID.String == id {
+			player.UserID = sql.NullString{}
+			m.data.players[playerID] = player
+		}
+	}
+	for matchID, match := range m.data.matches {
+		if match.Player1UserID.String == id {
+			match.Player1UserID = sql.NullString{}
+		}
+		if match.Player2UserID.String == id {
+			match.Player2UserID = sql.NullString{}
+		}
+		m.data.matches[matchID] = match
+	}
+	return nil
+}
+
+func (m *Memory) CountUsers(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return int64(len(m.data.users)), nil
+}
+
+func (m *Memory) ResetUsers(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.users)
+	clear(m.data.sessions)
+	clear(m.data.records)
+	clear(m.data.savedGames)
+	m.data.ratingHistory = nil
+	m.data.achievements = nil
+	for id, game := range m.data.games {
+		game.WhiteUserID = sql.NullString{}
+		game.BlackUserID = sql.NullString{}
+		m.data.games[id] = game
+	}
+	for id, player := range m.data.players {
+		player.UserID = sql.NullString{}
+		m.data.players[id] = player
+	}
+	for id, match := range m.data.matches {
+		match.Player1UserID = sql.NullString{}
+		match.Player2UserID = sql.NullString{}
+		m.data.matches[id] = match
+	}
+	return nil
+}
+
+func (m *Memory) ListUsers(ctx context.Context) ([]database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	users := slices.Collect(maps.Values(m.data.users))
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+func (m *Memory) GetLoginAttempt(ctx context.Context, username string) (database.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.data.loginAttempts[username]
+	if !ok {
+		return database.LoginAttempt{}, sql.ErrNoRows
+	}
+	return attempt, nil
+}
+
+func (m *Memory) SaveLoginAttempt(ctx context.Context, arg database.SaveLoginAttemptParams) (database.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt := database.LoginAttempt{
+		Username:       arg.Username,
+		FailedAttempts: arg.FailedAttempts,
+		LastFailedAt:   arg.LastFailedAt,
+		LockedUntil:    arg.LockedUntil,
+	}
+	m.data.loginAttempts[arg.Username] = attempt
+	return attempt, nil
+}
+
+func (m *Memory) ClearLoginAttempts(ctx context.Context, username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.loginAttempts, username)
+	return nil
+}
+
+func (m *Memory) CreateSession(ctx context.Context, arg database.CreateSessionParams) (database.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.users[arg.UserID]; !ok {
+		return database.Session{}, fmt.Errorf("user %s does not exist", arg.UserID)
+	}
+	if _, ok := m.data.sessions[arg.TokenHash]; ok {
+		return database.Session{}, fmt.Errorf("session already exists")
+	}
+
+	session := database.Session{
+		TokenHash: arg.TokenHash,
+		UserID:    arg.UserID,
+		CreatedAt: arg.CreatedAt,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	m.data.sessions[arg.TokenHash] = session
+	return session, nil
+}
+
+func (m *Memory) GetSessionUser(ctx context.Context, tokenHash string) (database.GetSessionUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.data.sessions[tokenHash]
+	if !ok {
+		return database.GetSessionUserRow{}, sql.ErrNoRows
+	}
+	user, ok := m.data.users[session.UserID]
+	if !ok {
+		return database.GetSessionUserRow{}, sql.ErrNoRows
+	}
+
+	return database.GetSessionUserRow{
+		ID:        user.ID,
+		Username:  user.Username,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (m *Memory) DeleteSession(ctx context.Context, tokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.sessions, tokenHash)
+	return nil
+}
+
+func (m *Memory) DeleteSessionsByUserID(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	maps.DeleteFunc(m.data.sessions, func(_ string, session database.Session) bool {
+		return session.UserID == userID
+	})
+	return nil
+}
+
+func (m *Memory) DeleteExpiredSessions(ctx context.Context, expiresAt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	maps.DeleteFunc(m.data.sessions, func(_ string, session database.Session) bool {
+		return session.ExpiresAt <= expiresAt
+	})
+	return nil
+}
+
+func (m *Memory) GetRecordsByUserID(ctx context.Context, userID string) (database.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.data.records[userID]
+	if !ok {
+		return database.Record{}, sql.ErrNoRows
+	}
+	return record, nil
+}
+
+func (m *Memory) AddResult(ctx context.Context, arg database.AddResultParams) (database.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.data.records[arg.UserID]
+	if !ok {
+		record = database.Record{
+			ID:               arg.ID,
+			UserID:           arg.UserID,
+			CreatedAt:        arg.CreatedAt,
+			Wins:             sql.NullInt64{Valid: true},
+			Losses:           sql.NullInt64{Valid: true},
+			Draws:            sql.NullInt64{Valid: true},
+			Rating:           1500,
+			GlickoRating:     1500,
+			GlickoDeviation:  350,
+			GlickoVolatility: 0.06,
+		}
+	}
+	record.UpdatedAt = arg.UpdatedAt
+	record.Wins = sql.NullInt64{Int64: record.Wins.Int64 + arg.Wins.Int64, Valid: true}
+	record.Losses = sql.NullInt64{Int64: record.Losses.Int64 + arg.Losses.Int64, Valid: true}
+	record.Draws = sql.NullInt64{Int64: record.Draws.Int64 + arg.Draws.Int64, Valid: true}
+
+	m.data.records[arg.UserID] = record
+	return record, nil
+}
+
+func (m *Memory) UpdateRating(ctx context.Context, arg database.UpdateRatingParams) (database.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.data.records[arg.UserID]
+	if !ok {
+		return database.Record{}, sql.ErrNoRows
+	}
+	record.UpdatedAt = arg.UpdatedAt
+	record.Rating = arg.Rating
+	record.RatedGames++
+
+	m.data.records[arg.UserID] = record
+	return record, nil
+}
+
+func (m *Memory) UpdateGlicko2(ctx context.Context, arg database.UpdateGlicko2Params) (database.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.data.records[arg.UserID]
+	if !ok {
+		return database.Record{}, sql.ErrNoRows
+	}
+	record.UpdatedAt = arg.UpdatedAt
+	record.GlickoRating = arg.GlickoRating
+	record.GlickoDeviation = arg.GlickoDeviation
+	record.GlickoVolatility = arg.GlickoVolatility
+	record.GlickoRatedAt = arg.GlickoRatedAt
+
+	m.data.records[arg.UserID] = record
+	return record, nil
+}
+
+func leaderboardKey(row database.ListLeaderboardRow, sortBy string) float64 {
+	games := row.Wins + row.Losses + row.Draws
+	switch sortBy {
+	case "rating":
+		return float64(row.Rating)
+	case "glicko_rating":
+		return row.GlickoRating
+	case "win_percentage":
+		return float64(row.Wins) / float64(max(games, 1))
+	case "games":
+		return float64(games)
+	case "wins":
+		return float64(row.Wins)
+	case "losses":
+		return float64(row.Losses)
+	case "draws":
+		return float64(row.Draws)
+	}
+	return 0
+}
+
+func (m *Memory) ListLeaderboard(ctx context.Context, arg database.ListLeaderboardParams) ([]database.ListLeaderboardRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sortBy, _ := arg.SortBy.(string)
+
+	rows := make([]database.ListLeaderboardRow, 0, len(m.data.users))
+	for _, user := range m.data.users {
+		row := database.ListLeaderboardRow{
+			ID:           user.ID,
+			Username:     user.Username,
+			Rating:       1500,
+			GlickoRating: 1500,
+		}
+		if record, ok := m.data.records[user.ID]; ok {
+			row.Wins = record.Wins.Int64
+			row.Losses = record.Losses.Int64
+			row.Draws = record.Draws.Int64
+			row.Rating = record.Rating
+			row.GlickoRating = record.GlickoRating
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := leaderboardKey(rows[i], sortBy), leaderboardKey(rows[j], sortBy)
+		if a != b {
+			return a > b
+		}
+		return rows[i].Username < rows[j].Username
+	})
+
+	start := min(int(arg.PageOffset), len(rows))
+	end := min(start+int(arg.PageSize), len(rows))
+	return rows[start:end], nil
+}
+
+func (m *Memory) CreateRatingHistory(ctx context.Context, arg database.CreateRatingHistoryParams) (database.RatingHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := database.RatingHistory{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		GameID:       arg.GameID,
+		CreatedAt:    arg.CreatedAt,
+		RatingBefore: arg.RatingBefore,
+		RatingAfter:  arg.RatingAfter,
+	}
+	m.data.ratingHistory = append(m.data.ratingHistory, entry)
+	return entry, nil
+}
+
+func (m *Memory) ListRatingHistoryByUserID(ctx context.Context, arg database.ListRatingHistoryByUserIDParams) ([]database.RatingHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []database.RatingHistory
+	for i := len(m.data.ratingHistory) - 1; i >= 0; i-- {
+		if m.data.ratingHistory[i].UserID == arg.UserID {
+			entries = append(entries, m.data.ratingHistory[i])
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.String > entries[j].CreatedAt.String
+	})
+
+	return entries[:min(int(arg.Limit), len(entries))], nil
+}
+
+func (m *Memory) ResetRecords(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.records)
+	return nil
+}
+
+func (m *Memory) ListRecords(ctx context.Context) ([]database.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records := slices.Collect(maps.Values(m.data.records))
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].UserID < records[j].UserID
+	})
+	return records, nil
+}
+
+func (m *Memory) ImportRecord(ctx context.Context, arg database.ImportRecordParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.users[arg.UserID]; !ok {
+		return 0, fmt.Errorf("user %s does not exist", arg.UserID)
+	}
+
+	// A record without an update time is older than any other.
+	record, ok := m.data.records[arg.UserID]
+	newer := arg.UpdatedAt.Valid && (!record.UpdatedAt.Valid || arg.UpdatedAt.String > record.UpdatedAt.String)
+	if ok && !newer {
+		return 0, nil
+	}
+	if !ok {
+		record = database.Record{
+			ID:        arg.ID,
+			UserID:    arg.UserID,
+			CreatedAt: arg.CreatedAt,
+		}
+	}
+	record.UpdatedAt = arg.UpdatedAt
+	record.Wins = arg.Wins
+	record.Losses = arg.Losses
+	record.Draws = arg.Draws
+	record.Rating = arg.Rating
+	record.RatedGames = arg.RatedGames
+	record.GlickoRating = arg.GlickoRating
+	record.GlickoDeviation = arg.GlickoDeviation
+	record.GlickoVolatility = arg.GlickoVolatility
+	record.GlickoRatedAt = arg.GlickoRatedAt
+	m.data.records[arg.UserID] = record
+	return 1, nil
+}
+
+func (m *Memory) ListRatingHistory(ctx context.Context) ([]database.RatingHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := slices.Clone(m.data.ratingHistory)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.String < entries[j].CreatedAt.String
+	})
+	return entries, nil
+}
+
+func (m *Memory) ImportRatingHistory(ctx context.Context, arg database.ImportRatingHistoryParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exists := slices.ContainsFunc(m.data.ratingHistory, func(entry database.RatingHistory) bool {
+		return entry.ID == arg.ID
+	})
+	if exists {
+		return 0, nil
+	}
+
+	m.data.ratingHistory = append(m.data.ratingHistory, database.RatingHistory{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		GameID:       arg.GameID,
+		CreatedAt:    arg.CreatedAt,
+		RatingBefore: arg.RatingBefore,
+		RatingAfter:  arg.RatingAfter,
+	})
+	return 1, nil
+}
+
+func (m *Memory) DeleteRecordsByUserID(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.records, userID)
+	return nil
+}
+
+func (m *Memory) DeleteRatingHistoryByUserID(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data.ratingHistory = slices.DeleteFunc(m.data.ratingHistory, func(entry database.RatingHistory) bool {
+		return entry.UserID == userID
+	})
+	return nil
+}
+
+func (m *Memory) ResetRatingHistory(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data.ratingHistory = nil
+	return nil
+}
+
+func (m *Memory) CreateGame(ctx context.Context, arg database.CreateGameParams) (database.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.games[arg.ID]; ok {
+		return database.Game{}, fmt.Errorf("game %s already exists", arg.ID)
+	}
+
+	game := database.Game{
+		ID:          arg.ID,
+		WhiteUserID: arg.WhiteUserID,
+		BlackUserID: arg.BlackUserID,
+		WhiteName:   arg.WhiteName,
+		BlackName:   arg.BlackName,
+		Result:      arg.Result,
+		Termination: arg.Termination,
+		StartedAt:   arg.StartedAt,
+		EndedAt:     arg.EndedAt,
+		Moves:       arg.Moves,
+		FinalFen:    arg.FinalFen,
+		TimeControl: arg.TimeControl,
+	}
+	m.data.games[game.ID] = game
+	m.data.lastGame++
+	m.data.gameOrder[game.ID] = m.data.lastGame
+	return game, nil
+}
+
+func (m *Memory) GetGame(ctx context.Context, id string) (database.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	game, ok := m.data.games[id]
+	if !ok {
+		return database.Game{}, sql.ErrNoRows
+	}
+	return game, nil
+}
+
+func (m *Memory) listGames(keep func(database.Game) bool) []database.Game {
+	var games []database.Game
+	for _, game := range m.data.games {
+		if keep(game) {
+			games = append(games, game)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].EndedAt != games[j].EndedAt {
+			return games[i].EndedAt > games[j].EndedAt
+		}
+		return m.data.gameOrder[games[i].ID] > m.data.gameOrder[games[j].ID]
+	})
+	return games
+}
+
+func (m *Memory) ListGamesByUserID(ctx context.Context, userID sql.NullString) ([]database.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.listGames(func(game database.Game) bool {
+		return userID.Valid && (game.WhiteUserID == userID || game.BlackUserID == userID)
+	}), nil
+}
+
+func (m *Memory) ListGamesBetweenUsers(ctx context.Context, arg database.ListGamesBetweenUsersParams) ([]database.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.listGames(func(game database.Game) bool {
+		if !arg.UserID.Valid || !arg.OpponentID.Valid {
+			return false
+		}
+		return (game.WhiteUserID == arg.UserID && game.BlackUserID == arg.OpponentID) ||
+			(game.WhiteUserID == arg.OpponentID && game.BlackUserID == arg.UserID)
+	}), nil
+}
+
+func (m *Memory) SaveGame(ctx context.Context, arg database.SaveGameParams) (database.SavedGame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved, ok := m.data.savedGames[arg.ID]
+	if !ok {
+		saved = database.SavedGame{
+			ID:          arg.ID,
+			WhiteUserID: arg.WhiteUserID,
+			BlackUserID: arg.BlackUserID,
+			CreatedAt:   arg.CreatedAt,
+		}
+	}
+	saved.UpdatedAt = arg.UpdatedAt
+	saved.Fen = arg.Fen
+	saved.Moves = arg.Moves
+	saved.OfferedDraw = arg.OfferedDraw
+
+	m.data.savedGames[arg.ID] = saved
+	return saved, nil
+}
+
+func (m *Memory) GetSavedGame(ctx context.Context, id string) (database.SavedGame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved, ok := m.data.savedGames[id]
+	if !ok {
+		return database.SavedGame{}, sql.ErrNoRows
+	}
+	return saved, nil
+}
+
+func (m *Memory) GetLatestSavedGame(ctx context.Context, arg database.GetLatestSavedGameParams) (database.SavedGame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	eventGames := map[string]bool{}
+	for _, pairing := range m.data.pairings {
+		if pairing.GameID.Valid {
+			eventGames[pairing.GameID.String] = true
+		}
+	}
+	for _, game := range m.data.matchGames {
+		if game.GameID.Valid {
+			eventGames[game.GameID.String] = true
+		}
+	}
+
+	var latest *database.SavedGame
+	for _, saved := range m.data.savedGames {
+		if saved.WhiteUserID != arg.WhiteUserID || saved.BlackUserID != arg.BlackUserID || eventGames[saved.ID] {
+			continue
+		}
+		if latest == nil || strings.Compare(saved.UpdatedAt.String, latest.UpdatedAt.String) > 0 {
+			latest = &saved
+		}
+	}
+	if latest == nil {
+		return database.SavedGame{}, sql.ErrNoRows
+	}
+	return *latest, nil
+}
+
+func (m *Memory) DeleteSavedGame(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.savedGames, id)
+	return nil
+}
+
+func (m *Memory) DeleteSavedGames(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.savedGames)
+	return nil
+}
+
+func (m *Memory) ListGames(ctx context.Context) ([]database.Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	games := slices.Collect(maps.Values(m.data.games))
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].EndedAt != games[j].EndedAt {
+			return games[i].EndedAt < games[j].EndedAt
+		}
+		return games[i].ID < games[j].ID
+	})
+	return games, nil
+}
+
+func (m *Memory) ImportGame(ctx context.Context, arg database.ImportGameParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.games[arg.ID]; ok {
+		return 0, nil
+	}
+
+	m.data.games[arg.ID] = database.Game{
+		ID:          arg.ID,
+		WhiteUserID: arg.WhiteUserID,
+		BlackUserID: arg.BlackUserID,
+		WhiteName:   arg.WhiteName,
+		BlackName:   arg.BlackName,
+		Result:      arg.Result,
+		Termination: arg.Termination,
+		StartedAt:   arg.StartedAt,
+		EndedAt:     arg.EndedAt,
+		Moves:       arg.Moves,
+		FinalFen:    arg.FinalFen,
+		TimeControl: arg.TimeControl,
+	}
+	m.data.lastGame++
+	m.data.gameOrder[arg.ID] = m.data.lastGame
+	return 1, nil
+}
+
+func (m *Memory) DeleteGames(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.games)
+	clear(m.data.gameOrder)
+	m.data.ratingHistory = nil
+	return nil
+}
+
+func (m *Memory) CreateTournament(ctx context.Context, arg database.CreateTournamentParams) (database.Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.tournaments[arg.ID]; ok {
+		return database.Tournament{}, fmt.Errorf("tournament %s already exists", arg.ID)
+	}
+
+	tournament := database.Tournament{
+		ID:        arg.ID,
+		Name:      arg.Name,
+		Format:    arg.Format,
+		Rounds:    arg.Rounds,
+		CreatedAt: arg.CreatedAt,
+	}
+	m.data.tournaments[arg.ID] = tournament
+	return tournament, nil
+}
+
+func (m *Memory) GetTournament(ctx context.Context, id string) (database.Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tournament, ok := m.data.tournaments[id]
+	if !ok {
+		return database.Tournament{}, sql.ErrNoRows
+	}
+	return tournament, nil
+}
+
+func (m *Memory) ListTournaments(ctx context.Context) ([]database.Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tournaments := slices.Collect(maps.Values(m.data.tournaments))
+	sort.Slice(tournaments, func(i, j int) bool {
+		if tournaments[i].CreatedAt != tournaments[j].CreatedAt {
+			return tournaments[i].CreatedAt > tournaments[j].CreatedAt
+		}
+		return tournaments[i].ID < tournaments[j].ID
+	})
+	return tournaments, nil
+}
+
+func (m *Memory) StartTournament(ctx context.Context, arg database.StartTournamentParams) (database.Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tournament, ok := m.data.tournaments[arg.ID]
+	if !ok {
+		return database.Tournament{}, sql.ErrNoRows
+	}
+	tournament.Rounds = arg.Rounds
+	tournament.StartedAt = arg.StartedAt
+	m.data.tournaments[arg.ID] = tournament
+	return tournament, nil
+}
+
+// DeleteTournament removes the tournament with its players and pairings.
+func (m *Memory) DeleteTournament(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.tournaments, id)
+	maps.DeleteFunc(m.data.players, func(_ string, player database.TournamentPlayer) bool {
+		return player.TournamentID == id
+	})
+	maps.DeleteFunc(m.data.pairings, func(_ string, pairing database.TournamentPairing) bool {
+		return pairing.TournamentID == id
+	})
+	return nil
+}
+
+func (m *Memory) DeleteTournaments(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.tournaments)
+	clear(m.data.players)
+	clear(m.data.pairings)
+	return nil
+}
+
+func (m *Memory) AddTournamentPlayer(ctx context.Context, arg database.AddTournamentPlayerParams) (database.TournamentPlayer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.tournaments[arg.TournamentID]; !ok {
+		return database.TournamentPlayer{}, fmt.Errorf("tournament %s does not exist", arg.TournamentID)
+	}
+	if _, ok := m.data.users[arg.UserID.String]; arg.UserID.Valid && !ok {
+		return database.TournamentPlayer{}, fmt.Errorf("user %s does not exist", arg.UserID.String)
+	}
+	for _, player := range m.data.players {
+		if player.ID == arg.ID || (player.TournamentID == arg.TournamentID && player.Name == arg.Name) {
+			return database.TournamentPlayer{}, fmt.Errorf("tournament player %s already exists", arg.Name)
+		}
+	}
+
+	player := database.TournamentPlayer{
+		ID:           arg.ID,
+		TournamentID: arg.TournamentID,
+		UserID:       arg.UserID,
+		Name:         arg.Name,
+		Seed:         arg.Seed,
+	}
+	m.data.players[arg.ID] = player
+	return player, nil
+}
+
+func (m *Memory) ListTournamentPlayers(ctx context.Context, tournamentID string) ([]database.TournamentPlayer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var players []database.TournamentPlayer
+	for _, player := range m.data.players {
+		if player.TournamentID == tournamentID {
+			players = append(players, player)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Seed < players[j].Seed
+	})
+	return players, nil
+}
+
+// DeleteTournamentPlayer removes the player with their pairings.
+func (m *Memory) DeleteTournamentPlayer(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.players, id)
+	maps.DeleteFunc(m.data.pairings, func(_ string, pairing database.TournamentPairing) bool {
+		return pairing.WhitePlayerID == id || pairing.BlackPlayerID.String == id
+	})
+	return nil
+}
+
+func (m *Memory) CreateTournamentPairing(ctx context.Context, arg database.CreateTournamentPairingParams) (database.TournamentPairing, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.tournaments[arg.TournamentID]; !ok {
+		return database.TournamentPairing{}, fmt.Errorf("tournament %s does not exist", arg.TournamentID)
+	}
+	if _, ok := m.data.players[arg.WhitePlayerID]; !ok {
+		return database.TournamentPairing{}, fmt.Errorf("tournament player %s does not exist", arg.WhitePlayerID)
+	}
+	if _, ok := m.data.players[arg.BlackPlayerID.String]; arg.BlackPlayerID.Valid && !ok {
+		return database.TournamentPairing{}, fmt.Errorf("tournament player %s does not exist", arg.BlackPlayerID.String)
+	}
+	for _, pairing := range m.data.pairings {
+		if pairing.ID == arg.ID || (pairing.TournamentID == arg.TournamentID && pairing.Round == arg.Round && pairing.Board == arg.Board) {
+			return database.TournamentPairing{}, fmt.Errorf("pairing for round %d board %d already exists", arg.Round, arg.Board)
+		}
+	}
+
+	pairing := database.TournamentPairing{
+		ID:            arg.ID,
+		TournamentID:  arg.TournamentID,
+		Round:         arg.Round,
+		Board:         arg.Board,
+		WhitePlayerID: arg.WhitePlayerID,
+		BlackPlayerID: arg.BlackPlayerID,
+	}
+	m.data.pairings[arg.ID] = pairing
+	return pairing, nil
+}
+
+func (m *Memory) ListTournamentPairings(ctx context.Context, tournamentID string) ([]database.TournamentPairing, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pairings []database.TournamentPairing
+	for _, pairing := range m.data.pairings {
+		if pairing.TournamentID == tournamentID {
+			pairings = append(pairings, pairing)
+		}
+	}
+	sort.Slice(pairings, func(i, j int) bool {
+		if pairings[i].Round != pairings[j].Round {
+			return pairings[i].Round < pairings[j].Round
+		}
+		return pairings[i].Board < pairings[j].Board
+	})
+	return pairings, nil
+}
+
+func (m *Memory) SetTournamentPairingGame(ctx context.Context, arg database.SetTournamentPairingGameParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pairing, ok := m.data.pairings[arg.ID]
+	if !ok {
+		return nil
+	}
+	pairing.GameID = arg.GameID
+	m.data.pairings[arg.ID] = pairing
+	return nil
+}
+
+func (m *Memory) SetTournamentPairingResult(ctx context.Context, arg database.SetTournamentPairingResultParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pairing, ok := m.data.pairings[arg.ID]
+	if !ok || pairing.Result.Valid {
+		return 0, nil
+	}
+	pairing.Result = arg.Result
+	pairing.GameID = arg.GameID
+	m.data.pairings[arg.ID] = pairing
+	return 1, nil
+}
+
+func (m *Memory) CreateMatch(ctx context.Context, arg database.CreateMatchParams) (database.Match, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.matches[arg.ID]; ok {
+		return database.Match{}, fmt.Errorf("match %s already exists", arg.ID)
+	}
+	for _, userID := range []sql.NullString{arg.Player1UserID, arg.Player2UserID} {
+		if _, ok := m.data.users[userID.String]; userID.Valid && !ok {
+			return database.Match{}, fmt.Errorf("user %s does not exist", userID.String)
+		}
+	}
+
+	match := database.Match{
+		ID:            arg.ID,
+		Player1UserID: arg.Player1UserID,
+		Player2UserID: arg.Player2UserID,
+		Player1Name:   arg.Player1Name,
+		Player2Name:   arg.Player2Name,
+		Games:         arg.Games,
+		Armageddon:    arg.Armageddon,
+		CreatedAt:     arg.CreatedAt,
+	}
+	m.data.matches[arg.ID] = match
+	return match, nil
+}
+
+func (m *Memory) GetMatch(ctx context.Context, id string) (database.Match, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	match, ok := m.data.matches[id]
+	if !ok {
+		return database.Match{}, sql.ErrNoRows
+	}
+	return match, nil
+}
+
+func (m *Memory) ListMatches(ctx context.Context) ([]database.Match, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	matches := slices.Collect(maps.Values(m.data.matches))
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].CreatedAt != matches[j].CreatedAt {
+			return matches[i].CreatedAt > matches[j].CreatedAt
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches, nil
+}
+
+func (m *Memory) FinishMatch(ctx context.Context, arg database.FinishMatchParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	match, ok := m.data.matches[arg.ID]
+	if !ok {
+		return nil
+	}
+	match.FinishedAt = arg.FinishedAt
+	m.data.matches[arg.ID] = match
+	return nil
+}
+
+// DeleteMatch removes the match with its games.
+func (m *Memory) DeleteMatch(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.data.matches, id)
+	maps.DeleteFunc(m.data.matchGames, func(_ string, game database.MatchGame) bool {
+		return game.MatchID == id
+	})
+	return nil
+}
+
+func (m *Memory) DeleteMatches(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.matches)
+	clear(m.data.matchGames)
+	return nil
+}
+
+func (m *Memory) CreateMatchGame(ctx context.Context, arg database.CreateMatchGameParams) (database.MatchGame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.matches[arg.MatchID]; !ok {
+		return database.MatchGame{}, fmt.Errorf("match %s does not exist", arg.MatchID)
+	}
+	for _, game := range m.data.matchGames {
+		if game.ID == arg.ID || (game.MatchID == arg.MatchID && game.Number == arg.Number) {
+			return database.MatchGame{}, fmt.Errorf("match game %d already exists", arg.Number)
+		}
+	}
+
+	game := database.MatchGame{
+		ID:           arg.ID,
+		MatchID:      arg.MatchID,
+		Number:       arg.Number,
+		Player1White: arg.Player1White,
+		Armageddon:   arg.Armageddon,
+	}
+	m.data.matchGames[arg.ID] = game
+	return game, nil
+}
+
+func (m *Memory) ListMatchGames(ctx context.Context, matchID string) ([]database.MatchGame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var games []database.MatchGame
+	for _, game := range m.data.matchGames {
+		if game.MatchID == matchID {
+			games = append(games, game)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].Number < games[j].Number
+	})
+	return games, nil
+}
+
+func (m *Memory) SetMatchGameID(ctx context.Context, arg database.SetMatchGameIDParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	game, ok := m.data.matchGames[arg.ID]
+	if !ok {
+		return nil
+	}
+	game.GameID = arg.GameID
+	m.data.matchGames[arg.ID] = game
+	return nil
+}
+
+func (m *Memory) SetMatchGameResult(ctx context.Context, arg database.SetMatchGameResultParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	game, ok := m.data.matchGames[arg.ID]
+	if !ok || game.Result.Valid {
+		return 0, nil
+	}
+	game.Result = arg.Result
+	game.GameID = arg.GameID
+	m.data.matchGames[arg.ID] = game
+	return 1, nil
+}
+
+func (m *Memory) UnlockAchievement(ctx context.Context, arg database.UnlockAchievementParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.users[arg.UserID]; !ok {
+		return 0, fmt.Errorf("user %s does not exist", arg.UserID)
+	}
+	for _, achievement := range m.data.achievements {
+		if achievement.UserID == arg.UserID && achievement.AchievementID == arg.AchievementID {
+			return 0, nil
+		}
+	}
+
+	m.data.achievements = append(m.data.achievements, database.Achievement{
+		UserID:        arg.UserID,
+		AchievementID: arg.AchievementID,
+		GameID:        arg.GameID,
+		UnlockedAt:    arg.UnlockedAt,
+	})
+	return 1, nil
+}
+
+func (m *Memory) ListAchievementsByUserID(ctx context.Context, userID string) ([]database.Achievement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var achievements []database.Achievement
+	for _, achievement := range m.data.achievements {
+		if achievement.UserID == userID {
+			achievements = append(achievements, achievement)
+		}
+	}
+	sort.SliceStable(achievements, func(i, j int) bool {
+		if achievements[i].UnlockedAt != achievements[j].UnlockedAt {
+			return achievements[i].UnlockedAt < achievements[j].UnlockedAt
+		}
+		return achievements[i].AchievementID < achievements[j].AchievementID
+	})
+	return achievements, nil
+}
+
+func (m *Memory) ListAchievements(ctx context.Context) ([]database.Achievement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	achievements := slices.Clone(m.data.achievements)
+	sort.SliceStable(achievements, func(i, j int) bool {
+		a, b := achievements[i], achievements[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		if a.UnlockedAt != b.UnlockedAt {
+			return a.UnlockedAt < b.UnlockedAt
+		}
+		return a.AchievementID < b.AchievementID
+	})
+	return achievements, nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+)
+
+var _ Store = (*SQLite)(nil)
+
+// SQLite is the Store backed by the sqlc queries.
+type SQLite struct {
+	*database.Queries
+	db *sql.DB
+}
+
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{
+		Queries: database.New(db),
+		db:      db,
+	}
+}
+
+func (s *SQLite) WithTx(ctx context.Context, fn func(Store) error) error {
+	// A store created for a transaction joins it.
+	if s.db == nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = fn(&SQLite{Queries: s.Queries.WithTx(tx)})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+)
+
+// Users stores registered players.
+type Users interface {
+	GetUserByName(ctx context.Context, username string) (database.User, error)
+	RegisterUser(ctx context.Context, arg database.RegisterUserParams) (database.User, error)
+	UpdatePassword(ctx context.Context, arg database.UpdatePasswordParams) (database.User, error)
+	UpdateUsername(ctx context.Context, arg database.UpdateUsernameParams) (database.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	CountUsers(ctx context.Context) (int64, error)
+	ResetUsers(ctx context.Context) error
+	ListUsers(ctx context.Context) ([]database.User, error)
+}
+
+// LoginAttempts stores failed logins per username.
+type LoginAttempts interface {
+	GetLoginAttempt(ctx context.Context, username string) (database.LoginAttempt, error)
+	SaveLoginAttempt(ctx context.Context, arg database.SaveLoginAttemptParams) (database.LoginAttempt, error)
+	ClearLoginAttempts(ctx context.Context, username string) error
+}
+
+// Sessions stores remembered logins by the hash of their token.
+type Sessions interface {
+	CreateSession(ctx context.Context, arg database.CreateSessionParams) (database.Session, error)
+	GetSessionUser(ctx context.Context, tokenHash string) (database.GetSessionUserRow, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteSessionsByUserID(ctx context.Context, userID string) error
+	DeleteExpiredSessions(ctx context.Context, expiresAt string) error
+}
+
+// Records stores players' results and ratings.
+type Records interface {
+	GetRecordsByUserID(ctx context.Context, userID string) (database.Record, error)
+	AddResult(ctx context.Context, arg database.AddResultParams) (database.Record, error)
+	UpdateRating(ctx context.Context, arg database.UpdateRatingParams) (database.Record, error)
+	UpdateGlicko2(ctx context.Context, arg database.UpdateGlicko2Params) (database.Record, error)
+	ListLeaderboard(ctx context.Context, arg database.ListLeaderboardParams) ([]database.ListLeaderboardRow, error)
+	CreateRatingHistory(ctx context.Context, arg database.CreateRatingHistoryParams) (database.RatingHistory, error)
+	ListRatingHistoryByUserID(ctx context.Context, arg database.ListRatingHistoryByUserIDParams) ([]database.RatingHistory, error)
+	ResetRecords(ctx context.Context) error
+	ListRecords(ctx context.Context) ([]database.Record, error)
+	ImportRecord(ctx context.Context, arg database.ImportRecordParams) (int64, error)
+	ListRatingHistory(ctx context.Context) ([]database.RatingHistory, error)
+	ImportRatingHistory(ctx context.Context, arg database.ImportRatingHistoryParams) (int64, error)
+	DeleteRecordsByUserID(ctx context.Context, userID string) error
+	DeleteRatingHistoryByUserID(ctx context.Context, userID string) error
+	ResetRatingHistory(ctx context.Context) error
+}
+
+// Games stores finished and unfinished games.
+type Games interface {
+	CreateGame(ctx context.Context, arg database.CreateGameParams) (database.Game, error)
+	GetGame(ctx context.Context, id string) (database.Game, error)
+	ListGamesByUserID(ctx context.Context, userID sql.NullString) ([]database.Game, error)
+	ListGamesBetweenUsers(ctx context.Context, arg database.ListGamesBetweenUsersParams) ([]database.Game, error)
+	SaveGame(ctx context.Context, arg database.SaveGameParams) (database.SavedGame, error)
+	GetSavedGame(ctx context.Context, id string) (database.SavedGame, error)
+	GetLatestSavedGame(ctx context.Context, arg database.GetLatestSavedGameParams) (database.SavedGame, error)
+	DeleteSavedGame(ctx context.Context, id string) error
+	DeleteSavedGames(ctx context.Context) error
+	ListGames(ctx context.Context) ([]database.Game, error)
+	ImportGame(ctx context.Context, arg database.ImportGameParams) (int64, error)
+	DeleteGames(ctx context.Context) error
+}
+
+// Tournaments stores tournaments with their players and pairings.
+type Tournaments interface {
+	CreateTournament(ctx context.Context, arg database.CreateTournamentParams) (database.Tournament, error)
+	GetTournament(ctx context.Context, id string) (database.Tournament, error)
+	ListTournaments(ctx context.Context) ([]database.Tournament, error)
+	StartTournament(ctx context.Context, arg database.StartTournamentParams) (database.Tournament, error)
+	DeleteTournament(ctx context.Context, id string) error
+	DeleteTournaments(ctx context.Context) error
+	AddTournamentPlayer(ctx context.Context, arg database.AddTournamentPlayerParams) (database.TournamentPlayer, error)
+	ListTournamentPlayers(ctx context.Context, tournamentID string) ([]database.TournamentPlayer, error)
+	DeleteTournamentPlayer(ctx context.Context, id string) error
+	CreateTournamentPairing(ctx context.Context, arg database.CreateTournamentPairingParams) (database.TournamentPairing, error)
+	ListTournamentPairings(ctx context.Context, tournamentID string) ([]database.TournamentPairing, error)
+	SetTournamentPairingGame(ctx context.Context, arg database.SetTournamentPairingGameParams) error
+	SetTournamentPairingResult(ctx context.Context, arg database.SetTournamentPairingResultParams) (int64, error)
+}
+
+// Matches stores match series with their games.
+type Matches interface {
+	CreateMatch(ctx context.Context, arg database.CreateMatchParams) (database.Match, error)
+	GetMatch(ctx context.Context, id string) (database.Match, error)
+	ListMatches(ctx context.Context) ([]database.Match, error)
+	FinishMatch(ctx context.Context, arg database.FinishMatchParams) error
+	DeleteMatch(ctx context.Context, id string) error
+	DeleteMatches(ctx context.Context) error
+	CreateMatchGame(ctx context.Context, arg database.CreateMatchGameParams) (database.MatchGame, error)
+	ListMatchGames(ctx context.Context, matchID string) ([]database.MatchGame, error)
+	SetMatchGameID(ctx context.Context, arg database.SetMatchGameIDParams) error
+	SetMatchGameResult(ctx context.Context, arg database.SetMatchGameResultParams) (int64, error)
+}
+
+// Achievements stores the achievements players unlocked.
+type Achievements interface {
+	UnlockAchievement(ctx context.Context, arg database.UnlockAchievementParams) (int64, error)
+	ListAchievementsByUserID(ctx context.Context, userID string) ([]database.Achievement, error)
+	ListAchievements(ctx context.Context) ([]database.Achievement, error)
+}
+
+// Store is the storage used by the game. Lookups of missing rows return
+// sql.ErrNoRows, like the SQLite implementation.
+type Store interface {
+	Users
+	LoginAttempts
+	Sessions
+	Records
+	Games
+	Tournaments
+	Matches
+	Achievements
+
+	// WithTx runs fn with a Store whose changes are all kept when fn
+	// returns nil and all discarded otherwise.
+	WithTx(ctx context.Context, fn func(Store) error) error
+}
+
+// DeleteAll deletes every player with everything stored for them, and every
+// game, saved game, tournament and match. Run it in a transaction.
+func DeleteAll(ctx context.Context, store Store) error {
+	err := store.DeleteTournaments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete tournaments: %w", err)
+	}
+	err = store.DeleteMatches(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete matches: %w", err)
+	}
+	err = store.DeleteSavedGames(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved games: %w", err)
+	}
+	err = store.ResetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete users: %w", err)
+	}
+	err = store.DeleteGames(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete games: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,1196 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// forEachStore runs test against every Store implementation.
+func forEachStore(t *testing.T, test func(t *testing.T, store Store)) {
+	stores := []struct {
+		name  string
+		store func(t *testing.T) Store
+	}{
+		{"sqlite", func(t *testing.T) Store {
+			db := setupTestDB(t)
+			t.Cleanup(func() { db.Close() })
+			return NewSQLite(db)
+		}},
+		{"memory", func(t *testing.T) Store {
+			return NewMemory()
+		}},
+	}
+
+	for _, store := range stores {
+		t.Run(store.name, func(t *testing.T) {
+			test(t, store.store(t))
+		})
+	}
+}
+
+func registerUser(t *testing.T, store Store, username string) string {
+	t.Helper()
+
+	user, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             username + "-id",
+		Username:       username,
+		HashedPassword: "hash",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	return user.ID
+}
+
+func addResult(t *testing.T, store Store, userID string, wins, losses, draws int64) database.Record {
+	t.Helper()
+
+	record, err := store.AddResult(context.Background(), database.AddResultParams{
+		ID:     userID + "-record",
+		UserID: userID,
+		Wins:   sql.NullInt64{Int64: wins, Valid: true},
+		Losses: sql.NullInt64{Int64: losses, Valid: true},
+		Draws:  sql.NullInt64{Int64: draws, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("AddResult failed: %v", err)
+	}
+
+	return record
+}
+
+func TestUsers(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		id := registerUser(t, store, "Alice")
+
+		user, err := store.GetUserByName(context.Background(), "Alice")
+		if err != nil {
+			t.Fatalf("GetUserByName failed: %v", err)
+		}
+		if user.ID != id {
+			t.Errorf("Expected user %s, got %s", id, user.ID)
+		}
+
+		_, err = store.RegisterUser(context.Background(), database.RegisterUserParams{
+			ID:       "other-id",
+			Username: "Alice",
+		})
+		if err == nil {
+			t.Error("Expected duplicate username to fail")
+		}
+
+		_, err = store.GetUserByName(context.Background(), "Bob")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows for a missing user, got %v", err)
+		}
+
+		count, err := store.CountUsers(context.Background())
+		if err != nil {
+			t.Fatalf("CountUsers failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 user, got %d", count)
+		}
+
+		user, err = store.UpdatePassword(context.Background(), database.UpdatePasswordParams{
+			HashedPassword: "new hash",
+			ID:             id,
+		})
+		if err != nil {
+			t.Fatalf("UpdatePassword failed: %v", err)
+		}
+		if user.HashedPassword != "new hash" {
+			t.Errorf("Expected the new password hash, got %q", user.HashedPassword)
+		}
+
+		registerUser(t, store, "Bob")
+		_, err = store.UpdateUsername(context.Background(), database.UpdateUsernameParams{
+			Username: "Bob",
+			ID:       id,
+		})
+		if err == nil {
+			t.Error("Expected renaming to a taken username to fail")
+		}
+		_, err = store.UpdateUsername(context.Background(), database.UpdateUsernameParams{
+			Username: "Alicia",
+			ID:       id,
+		})
+		if err != nil {
+			t.Fatalf("UpdateUsername failed: %v", err)
+		}
+		user, err = store.GetUserByName(context.Background(), "Alicia")
+		if err != nil || user.ID != id {
+			t.Errorf("Expected Alice to be renamed to Alicia, got %+v (%v)", user, err)
+		}
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := registerUser(t, store, "Alice")
+		bob := registerUser(t, store, "Bob")
+		addResult(t, store, alice, 1, 0, 0)
+		addResult(t, store, bob, 0, 1, 0)
+
+		_, err := store.CreateGame(context.Background(), database.CreateGameParams{
+			ID:          "game-1",
+			WhiteUserID: sql.NullString{String: alice, Valid: true},
+			BlackUserID: sql.NullString{String: bob, Valid: true},
+			Result:      "1-0",
+		})
+		if err != nil {
+			t.Fatalf("CreateGame failed: %v", err)
+		}
+		_, err = store.CreateRatingHistory(context.Background(), database.CreateRatingHistoryParams{
+			ID:     "history-1",
+			UserID: alice,
+			GameID: "game-1",
+		})
+		if err != nil {
+			t.Fatalf("CreateRatingHistory failed: %v", err)
+		}
+		_, err = store.SaveGame(context.Background(), database.SaveGameParams{
+			ID:          "saved-1",
+			WhiteUserID: sql.NullString{String: alice, Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("SaveGame failed: %v", err)
+		}
+
+		err = store.DeleteUser(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+
+		_, err = store.GetUserByName(context.Background(), "Alice")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the user to be deleted, got %v", err)
+		}
+		_, err = store.GetRecordsByUserID(context.Background(), alice)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the record to be deleted, got %v", err)
+		}
+		_, err = store.GetSavedGame(context.Background(), "saved-1")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the saved game to be deleted, got %v", err)
+		}
+		history, err := store.ListRatingHistoryByUserID(context.Background(), database.ListRatingHistoryByUserIDParams{
+			UserID: alice,
+			Limit:  10,
+		})
+		if err != nil || len(history) != 0 {
+			t.Errorf("Expected the rating history to be deleted, got %+v (%v)", history, err)
+		}
+
+		game, err := store.GetGame(context.Background(), "game-1")
+		if err != nil {
+			t.Fatalf("Expected the game to be kept: %v", err)
+		}
+		if game.WhiteUserID.Valid || game.BlackUserID.String != bob {
+			t.Errorf("Expected only the deleted player to be removed from the game, got %+v", game)
+		}
+		if _, err = store.GetRecordsByUserID(context.Background(), bob); err != nil {
+			t.Errorf("Expected the opponent's record to be kept, got %v", err)
+		}
+	})
+}
+
+func TestLoginAttempts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		_, err := store.GetLoginAttempt(context.Background(), "Alice")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows without failed logins, got %v", err)
+		}
+
+		for _, failed := range []int64{1, 2} {
+			_, err = store.SaveLoginAttempt(context.Background(), database.SaveLoginAttemptParams{
+				Username:       "Alice",
+				FailedAttempts: failed,
+				LastFailedAt:   "2024-01-01T10:00:00Z",
+				LockedUntil:    sql.NullString{String: "2024-01-01T10:15:00Z", Valid: failed == 2},
+			})
+			if err != nil {
+				t.Fatalf("SaveLoginAttempt failed: %v", err)
+			}
+		}
+
+		attempt, err := store.GetLoginAttempt(context.Background(), "Alice")
+		if err != nil {
+			t.Fatalf("GetLoginAttempt failed: %v", err)
+		}
+		if attempt.FailedAttempts != 2 || attempt.LockedUntil.String != "2024-01-01T10:15:00Z" {
+			t.Errorf("Expected the last saved attempt, got %+v", attempt)
+		}
+
+		err = store.ClearLoginAttempts(context.Background(), "Alice")
+		if err != nil {
+			t.Fatalf("ClearLoginAttempts failed: %v", err)
+		}
+		_, err = store.GetLoginAttempt(context.Background(), "Alice")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after clearing, got %v", err)
+		}
+	})
+}
+
+func TestSessions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := registerUser(t, store, "Alice")
+
+		for i, expiresAt := range []string{"2024-01-01T10:00:00Z", "2024-02-01T10:00:00Z"} {
+			_, err := store.CreateSession(context.Background(), database.CreateSessionParams{
+				TokenHash: fmt.Sprintf("hash-%d", i),
+				UserID:    alice,
+				ExpiresAt: expiresAt,
+			})
+			if err != nil {
+				t.Fatalf("CreateSession failed: %v", err)
+			}
+		}
+
+		user, err := store.GetSessionUser(context.Background(), "hash-1")
+		if err != nil {
+			t.Fatalf("GetSessionUser failed: %v", err)
+		}
+		if user.ID != alice || user.Username != "Alice" || user.ExpiresAt != "2024-02-01T10:00:00Z" {
+			t.Errorf("Unexpected session user %+v", user)
+		}
+
+		err = store.DeleteExpiredSessions(context.Background(), "2024-01-15T10:00:00Z")
+		if err != nil {
+			t.Fatalf("DeleteExpiredSessions failed: %v", err)
+		}
+		_, err = store.GetSessionUser(context.Background(), "hash-0")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the expired session to be deleted, got %v", err)
+		}
+
+		bob := registerUser(t, store, "Bob")
+		_, err = store.CreateSession(context.Background(), database.CreateSessionParams{
+			TokenHash: "hash-bob",
+			UserID:    bob,
+			ExpiresAt: "2024-02-01T10:00:00Z",
+		})
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		err = store.DeleteSessionsByUserID(context.Background(), bob)
+		if err != nil {
+			t.Fatalf("DeleteSessionsByUserID failed: %v", err)
+		}
+		_, err = store.GetSessionUser(context.Background(), "hash-bob")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected Bob's sessions to be deleted, got %v", err)
+		}
+		_, err = store.GetSessionUser(context.Background(), "hash-1")
+		if err != nil {
+			t.Errorf("Expected Alice's session to stay, got %v", err)
+		}
+
+		err = store.DeleteUser(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+		_, err = store.GetSessionUser(context.Background(), "hash-1")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the session to be deleted with the user, got %v", err)
+		}
+	})
+}
+
+func TestRecords(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		id := registerUser(t, store, "Alice")
+
+		_, err := store.GetRecordsByUserID(context.Background(), id)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows before the first game, got %v", err)
+		}
+
+		addResult(t, store, id, 1, 0, 0)
+		record := addResult(t, store, id, 0, 1, 1)
+		if record.Wins.Int64 != 1 || record.Losses.Int64 != 1 || record.Draws.Int64 != 1 {
+			t.Errorf("Expected results to add up, got %+v", record)
+		}
+		if record.Rating != 1500 || record.GlickoRating != 1500 || record.GlickoDeviation != 350 {
+			t.Errorf("Expected initial ratings, got %+v", record)
+		}
+
+		record, err = store.UpdateRating(context.Background(), database.UpdateRatingParams{
+			Rating: 1520,
+			UserID: id,
+		})
+		if err != nil {
+			t.Fatalf("UpdateRating failed: %v", err)
+		}
+		if record.Rating != 1520 || record.RatedGames != 1 {
+			t.Errorf("Expected rating 1520 after 1 game, got %d after %d", record.Rating, record.RatedGames)
+		}
+
+		for i, change := range []int64{1520, 1510} {
+			_, err = store.CreateGame(context.Background(), database.CreateGameParams{
+				ID:     fmt.Sprintf("game-%d", i),
+				Result: "1-0",
+			})
+			if err != nil {
+				t.Fatalf("CreateGame failed: %v", err)
+			}
+			_, err = store.CreateRatingHistory(context.Background(), database.CreateRatingHistoryParams{
+				ID:          fmt.Sprintf("history-%d", i),
+				UserID:      id,
+				GameID:      fmt.Sprintf("game-%d", i),
+				CreatedAt:   sql.NullString{String: fmt.Sprintf("2024-01-0%dT10:00:00Z", i+1), Valid: true},
+				RatingAfter: change,
+			})
+			if err != nil {
+				t.Fatalf("CreateRatingHistory failed: %v", err)
+			}
+		}
+		history, err := store.ListRatingHistoryByUserID(context.Background(), database.ListRatingHistoryByUserIDParams{
+			UserID: id,
+			Limit:  1,
+		})
+		if err != nil {
+			t.Fatalf("ListRatingHistoryByUserID failed: %v", err)
+		}
+		if len(history) != 1 || history[0].RatingAfter != 1510 {
+			t.Errorf("Expected only the latest rating change, got %+v", history)
+		}
+
+		err = store.ResetRecords(context.Background())
+		if err != nil {
+			t.Fatalf("ResetRecords failed: %v", err)
+		}
+		_, err = store.GetRecordsByUserID(context.Background(), id)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after reset, got %v", err)
+		}
+	})
+}
+
+func TestResetStats(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := registerUser(t, store, "Alice")
+		bob := registerUser(t, store, "Bob")
+
+		_, err := store.CreateGame(context.Background(), database.CreateGameParams{ID: "game", Result: "1-0"})
+		if err != nil {
+			t.Fatalf("CreateGame failed: %v", err)
+		}
+		for _, id := range []string{alice, bob} {
+			addResult(t, store, id, 1, 0, 0)
+			_, err = store.CreateRatingHistory(context.Background(), database.CreateRatingHistoryParams{
+				ID:     id + "-history",
+				UserID: id,
+				GameID: "game",
+			})
+			if err != nil {
+				t.Fatalf("CreateRatingHistory failed: %v", err)
+			}
+		}
+
+		err = store.DeleteRecordsByUserID(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("DeleteRecordsByUserID failed: %v", err)
+		}
+		err = store.DeleteRatingHistoryByUserID(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("DeleteRatingHistoryByUserID failed: %v", err)
+		}
+		_, err = store.GetRecordsByUserID(context.Background(), alice)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected Alice's record to be deleted, got %v", err)
+		}
+		_, err = store.GetRecordsByUserID(context.Background(), bob)
+		if err != nil {
+			t.Errorf("Expected Bob's record to be kept, got %v", err)
+		}
+		history, err := store.ListRatingHistory(context.Background())
+		if err != nil {
+			t.Fatalf("ListRatingHistory failed: %v", err)
+		}
+		if len(history) != 1 || history[0].UserID != bob {
+			t.Errorf("Expected only Bob's rating history to be kept, got %+v", history)
+		}
+
+		err = store.ResetRatingHistory(context.Background())
+		if err != nil {
+			t.Fatalf("ResetRatingHistory failed: %v", err)
+		}
+		history, err = store.ListRatingHistory(context.Background())
+		if err != nil {
+			t.Fatalf("ListRatingHistory failed: %v", err)
+		}
+		if len(history) != 0 {
+			t.Errorf("Expected no rating history after reset, got %+v", history)
+		}
+	})
+}
+
+func TestListLeaderboard(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := registerUser(t, store, "Alice")
+		bob := registerUser(t, store, "Bob")
+		registerUser(t, store, "Carol")
+
+		addResult(t, store, alice, 1, 3, 0)
+		addResult(t, store, bob, 2, 0, 0)
+		_, err := store.UpdateRating(context.Background(), database.UpdateRatingParams{
+			Rating: 1600,
+			UserID: alice,
+		})
+		if err != nil {
+			t.Fatalf("UpdateRating failed: %v", err)
+		}
+
+		tests := []struct {
+			sortBy   string
+			offset   int64
+			expected []string
+		}{
+			{"rating", 0, []string{"Alice", "Bob"}},
+			{"wins", 0, []string{"Bob", "Alice"}},
+			{"win_percentage", 0, []string{"Bob", "Alice"}},
+			{"games", 0, []string{"Alice", "Bob"}},
+			{"losses", 1, []string{"Bob", "Carol"}},
+			{"username", 0, []string{"Alice", "Bob"}},
+			{"rating", 2, []string{"Carol"}},
+		}
+
+		for _, test := range tests {
+			rows, err := store.ListLeaderboard(context.Background(), database.ListLeaderboardParams{
+				SortBy:     test.sortBy,
+				PageSize:   2,
+				PageOffset: test.offset,
+			})
+			if err != nil {
+				t.Fatalf("ListLeaderboard failed: %v", err)
+			}
+
+			var usernames []string
+			for _, row := range rows {
+				usernames = append(usernames, row.Username)
+			}
+			if fmt.Sprint(usernames) != fmt.Sprint(test.expected) {
+				t.Errorf("Sorted by %s from %d: expected %v, got %v", test.sortBy, test.offset, test.expected, usernames)
+			}
+		}
+	})
+}
+
+func TestGames(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := sql.NullString{String: registerUser(t, store, "Alice"), Valid: true}
+		bob := sql.NullString{String: registerUser(t, store, "Bob"), Valid: true}
+
+		games := []database.CreateGameParams{
+			{ID: "game-1", WhiteUserID: alice, BlackUserID: bob, EndedAt: "2024-01-01T10:00:00Z"},
+			{ID: "game-2", WhiteUserID: bob, BlackUserID: alice, EndedAt: "2024-01-03T10:00:00Z"},
+			{ID: "game-3", WhiteUserID: alice, EndedAt: "2024-01-02T10:00:00Z"},
+		}
+		for _, game := range games {
+			game.Result = "1-0"
+			game.Termination = "checkmate"
+			game.StartedAt = game.EndedAt
+			game.TimeControl = "-"
+			_, err := store.CreateGame(context.Background(), game)
+			if err != nil {
+				t.Fatalf("CreateGame failed: %v", err)
+			}
+		}
+		_, err := store.CreateGame(context.Background(), games[0])
+		if err == nil {
+			t.Error("Expected duplicate game to fail")
+		}
+
+		_, err = store.GetGame(context.Background(), "missing")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows for a missing game, got %v", err)
+		}
+
+		list, err := store.ListGamesByUserID(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("ListGamesByUserID failed: %v", err)
+		}
+		if len(list) != 3 || list[0].ID != "game-2" || list[2].ID != "game-1" {
+			t.Errorf("Expected Alice's games newest first, got %+v", list)
+		}
+
+		list, err = store.ListGamesBetweenUsers(context.Background(), database.ListGamesBetweenUsersParams{
+			UserID:     bob,
+			OpponentID: alice,
+		})
+		if err != nil {
+			t.Fatalf("ListGamesBetweenUsers failed: %v", err)
+		}
+		if len(list) != 2 || list[0].ID != "game-2" {
+			t.Errorf("Expected 2 games between Alice and Bob, got %+v", list)
+		}
+
+		// Games ending in the same second are listed newest first too.
+		for _, id := range []string{"game-4", "game-5"} {
+			_, err = store.CreateGame(context.Background(), database.CreateGameParams{
+				ID:          id,
+				WhiteUserID: bob,
+				Result:      "1-0",
+				Termination: "checkmate",
+				StartedAt:   "2024-01-04T10:00:00Z",
+				EndedAt:     "2024-01-04T10:00:00Z",
+				TimeControl: "-",
+			})
+			if err != nil {
+				t.Fatalf("CreateGame failed: %v", err)
+			}
+		}
+		list, err = store.ListGamesByUserID(context.Background(), bob)
+		if err != nil {
+			t.Fatalf("ListGamesByUserID failed: %v", err)
+		}
+		if len(list) != 4 || list[0].ID != "game-5" || list[1].ID != "game-4" {
+			t.Errorf("Expected the last stored game first, got %+v", list)
+		}
+	})
+}
+
+func TestSavedGames(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := sql.NullString{String: registerUser(t, store, "Alice"), Valid: true}
+
+		for i, updatedAt := range []string{"2024-01-01T10:00:00Z", "2024-01-02T10:00:00Z"} {
+			_, err := store.SaveGame(context.Background(), database.SaveGameParams{
+				ID:          fmt.Sprintf("saved-%d", i),
+				WhiteUserID: alice,
+				UpdatedAt:   sql.NullString{String: updatedAt, Valid: true},
+				Fen:         "start",
+			})
+			if err != nil {
+				t.Fatalf("SaveGame failed: %v", err)
+			}
+		}
+		saved, err := store.SaveGame(context.Background(), database.SaveGameParams{
+			ID:          "saved-0",
+			WhiteUserID: alice,
+			UpdatedAt:   sql.NullString{String: "2024-01-01T11:00:00Z", Valid: true},
+			Fen:         "moved",
+		})
+		if err != nil {
+			t.Fatalf("SaveGame failed: %v", err)
+		}
+		if saved.Fen != "moved" {
+			t.Errorf("Expected saving again to update the game, got %+v", saved)
+		}
+
+		latest, err := store.GetLatestSavedGame(context.Background(), database.GetLatestSavedGameParams{
+			WhiteUserID: alice,
+		})
+		if err != nil {
+			t.Fatalf("GetLatestSavedGame failed: %v", err)
+		}
+		if latest.ID != "saved-1" {
+			t.Errorf("Expected saved-1 to be the latest saved game, got %s", latest.ID)
+		}
+
+		_, err = store.GetLatestSavedGame(context.Background(), database.GetLatestSavedGameParams{
+			WhiteUserID: alice,
+			BlackUserID: alice,
+		})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows for other players, got %v", err)
+		}
+
+		err = store.DeleteSavedGame(context.Background(), "saved-1")
+		if err != nil {
+			t.Fatalf("DeleteSavedGame failed: %v", err)
+		}
+		_, err = store.GetSavedGame(context.Background(), "saved-1")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+		}
+	})
+}
+
+func TestTournaments(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := sql.NullString{String: registerUser(t, store, "Alice"), Valid: true}
+
+		for i, createdAt := range []string{"2024-01-01T10:00:00Z", "2024-01-02T10:00:00Z"} {
+			_, err := store.CreateTournament(context.Background(), database.CreateTournamentParams{
+				ID:        fmt.Sprintf("tournament-%d", i),
+				Name:      fmt.Sprintf("Cup %d", i),
+				Format:    "round_robin",
+				CreatedAt: createdAt,
+			})
+			if err != nil {
+				t.Fatalf("CreateTournament failed: %v", err)
+			}
+		}
+		tournaments, err := store.ListTournaments(context.Background())
+		if err != nil {
+			t.Fatalf("ListTournaments failed: %v", err)
+		}
+		if len(tournaments) != 2 || tournaments[0].ID != "tournament-1" {
+			t.Errorf("Expected the newest tournament first, got %+v", tournaments)
+		}
+
+		players := []database.AddTournamentPlayerParams{
+			{ID: "player-b", TournamentID: "tournament-0", Name: "Guest", Seed: 2},
+			{ID: "player-a", TournamentID: "tournament-0", UserID: alice, Name: "Alice", Seed: 1},
+		}
+		for _, player := range players {
+			_, err = store.AddTournamentPlayer(context.Background(), player)
+			if err != nil {
+				t.Fatalf("AddTournamentPlayer failed: %v", err)
+			}
+		}
+		_, err = store.AddTournamentPlayer(context.Background(), database.AddTournamentPlayerParams{
+			ID: "player-c", TournamentID: "tournament-0", Name: "Guest", Seed: 3,
+		})
+		if err == nil {
+			t.Error("Expected a duplicate name to fail")
+		}
+		listed, err := store.ListTournamentPlayers(context.Background(), "tournament-0")
+		if err != nil {
+			t.Fatalf("ListTournamentPlayers failed: %v", err)
+		}
+		if len(listed) != 2 || listed[0].ID != "player-a" {
+			t.Errorf("Expected players in seed order, got %+v", listed)
+		}
+
+		pairing, err := store.CreateTournamentPairing(context.Background(), database.CreateTournamentPairingParams{
+			ID:            "pairing-1",
+			TournamentID:  "tournament-0",
+			Round:         1,
+			Board:         1,
+			WhitePlayerID: "player-a",
+			BlackPlayerID: sql.NullString{String: "player-b", Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreateTournamentPairing failed: %v", err)
+		}
+		if pairing.Result.Valid || pairing.GameID.Valid {
+			t.Errorf("Expected a new pairing without result, got %+v", pairing)
+		}
+		started, err := store.StartTournament(context.Background(), database.StartTournamentParams{
+			Rounds:    1,
+			StartedAt: sql.NullString{String: "2024-01-03T10:00:00Z", Valid: true},
+			ID:        "tournament-0",
+		})
+		if err != nil {
+			t.Fatalf("StartTournament failed: %v", err)
+		}
+		if started.Rounds != 1 || !started.StartedAt.Valid {
+			t.Errorf("Expected the tournament to be started, got %+v", started)
+		}
+
+		gameID := sql.NullString{String: "tournament-game", Valid: true}
+		err = store.SetTournamentPairingGame(context.Background(), database.SetTournamentPairingGameParams{
+			GameID: gameID,
+			ID:     "pairing-1",
+		})
+		if err != nil {
+			t.Fatalf("SetTournamentPairingGame failed: %v", err)
+		}
+		_, err = store.SaveGame(context.Background(), database.SaveGameParams{
+			ID:          gameID.String,
+			WhiteUserID: alice,
+			Fen:         "start",
+		})
+		if err != nil {
+			t.Fatalf("SaveGame failed: %v", err)
+		}
+		_, err = store.GetLatestSavedGame(context.Background(), database.GetLatestSavedGameParams{
+			WhiteUserID: alice,
+		})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected tournament games not to be offered for resuming, got %v", err)
+		}
+
+		result := database.SetTournamentPairingResultParams{
+			Result: sql.NullString{String: "1-0", Valid: true},
+			GameID: gameID,
+			ID:     "pairing-1",
+		}
+		updated, err := store.SetTournamentPairingResult(context.Background(), result)
+		if err != nil || updated != 1 {
+			t.Fatalf("SetTournamentPairingResult failed: %d, %v", updated, err)
+		}
+		updated, err = store.SetTournamentPairingResult(context.Background(), result)
+		if err != nil || updated != 0 {
+			t.Errorf("Expected a second result to be ignored, got %d, %v", updated, err)
+		}
+
+		err = store.DeleteUser(context.Background(), alice.String)
+		if err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+		listed, err = store.ListTournamentPlayers(context.Background(), "tournament-0")
+		if err != nil {
+			t.Fatalf("ListTournamentPlayers failed: %v", err)
+		}
+		if len(listed) != 2 || listed[0].UserID.Valid {
+			t.Errorf("Expected deleted users to stay in the tournament as guests, got %+v", listed)
+		}
+
+		err = store.DeleteTournamentPlayer(context.Background(), "player-b")
+		if err != nil {
+			t.Fatalf("DeleteTournamentPlayer failed: %v", err)
+		}
+		pairings, err := store.ListTournamentPairings(context.Background(), "tournament-0")
+		if err != nil {
+			t.Fatalf("ListTournamentPairings failed: %v", err)
+		}
+		if len(pairings) != 0 {
+			t.Errorf("Expected the removed player's pairings to be deleted, got %+v", pairings)
+		}
+
+		err = store.DeleteTournament(context.Background(), "tournament-0")
+		if err != nil {
+			t.Fatalf("DeleteTournament failed: %v", err)
+		}
+		_, err = store.GetTournament(context.Background(), "tournament-0")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+		}
+		listed, err = store.ListTournamentPlayers(context.Background(), "tournament-0")
+		if err != nil || len(listed) != 0 {
+			t.Errorf("Expected the players to be deleted with the tournament, got %+v, %v", listed, err)
+		}
+	})
+}
+
+func TestMatches(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := sql.NullString{String: registerUser(t, store, "Alice"), Valid: true}
+
+		for i, createdAt := range []string{"2024-01-01T10:00:00Z", "2024-01-02T10:00:00Z"} {
+			_, err := store.CreateMatch(context.Background(), database.CreateMatchParams{
+				ID:            fmt.Sprintf("match-%d", i),
+				Player1UserID: alice,
+				Player1Name:   "Alice",
+				Player2Name:   "Guest 2",
+				Games:         3,
+				Armageddon:    i == 0,
+				CreatedAt:     createdAt,
+			})
+			if err != nil {
+				t.Fatalf("CreateMatch failed: %v", err)
+			}
+		}
+		matches, err := store.ListMatches(context.Background())
+		if err != nil {
+			t.Fatalf("ListMatches failed: %v", err)
+		}
+		if len(matches) != 2 || matches[0].ID != "match-1" || !matches[1].Armageddon {
+			t.Errorf("Expected the newest match first, got %+v", matches)
+		}
+
+		for _, number := range []int64{2, 1} {
+			_, err = store.CreateMatchGame(context.Background(), database.CreateMatchGameParams{
+				ID:           fmt.Sprintf("match-game-%d", number),
+				MatchID:      "match-0",
+				Number:       number,
+				Player1White: number%2 == 1,
+			})
+			if err != nil {
+				t.Fatalf("CreateMatchGame failed: %v", err)
+			}
+		}
+		_, err = store.CreateMatchGame(context.Background(), database.CreateMatchGameParams{
+			ID: "match-game-x", MatchID: "match-0", Number: 1,
+		})
+		if err == nil {
+			t.Error("Expected a duplicate game number to fail")
+		}
+		games, err := store.ListMatchGames(context.Background(), "match-0")
+		if err != nil {
+			t.Fatalf("ListMatchGames failed: %v", err)
+		}
+		if len(games) != 2 || games[0].Number != 1 || !games[0].Player1White || games[0].Result.Valid {
+			t.Errorf("Expected games in order without results, got %+v", games)
+		}
+
+		gameID := sql.NullString{String: "match-saved-game", Valid: true}
+		err = store.SetMatchGameID(context.Background(), database.SetMatchGameIDParams{
+			GameID: gameID,
+			ID:     "match-game-1",
+		})
+		if err != nil {
+			t.Fatalf("SetMatchGameID failed: %v", err)
+		}
+		_, err = store.SaveGame(context.Background(), database.SaveGameParams{
+			ID:          gameID.String,
+			WhiteUserID: alice,
+			Fen:         "start",
+		})
+		if err != nil {
+			t.Fatalf("SaveGame failed: %v", err)
+		}
+		_, err = store.GetLatestSavedGame(context.Background(), database.GetLatestSavedGameParams{
+			WhiteUserID: alice,
+		})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected match games not to be offered for resuming, got %v", err)
+		}
+
+		result := database.SetMatchGameResultParams{
+			Result: sql.NullString{String: "1/2-1/2", Valid: true},
+			GameID: gameID,
+			ID:     "match-game-1",
+		}
+		updated, err := store.SetMatchGameResult(context.Background(), result)
+		if err != nil || updated != 1 {
+			t.Fatalf("SetMatchGameResult failed: %d, %v", updated, err)
+		}
+		updated, err = store.SetMatchGameResult(context.Background(), result)
+		if err != nil || updated != 0 {
+			t.Errorf("Expected a second result to be ignored, got %d, %v", updated, err)
+		}
+
+		err = store.FinishMatch(context.Background(), database.FinishMatchParams{
+			FinishedAt: sql.NullString{String: "2024-01-03T10:00:00Z", Valid: true},
+			ID:         "match-0",
+		})
+		if err != nil {
+			t.Fatalf("FinishMatch failed: %v", err)
+		}
+		err = store.DeleteUser(context.Background(), alice.String)
+		if err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+		match, err := store.GetMatch(context.Background(), "match-0")
+		if err != nil {
+			t.Fatalf("GetMatch failed: %v", err)
+		}
+		if match.Player1UserID.Valid || match.Player1Name != "Alice" || !match.FinishedAt.Valid {
+			t.Errorf("Expected the finished match to keep the deleted player's name, got %+v", match)
+		}
+
+		err = store.DeleteMatch(context.Background(), "match-0")
+		if err != nil {
+			t.Fatalf("DeleteMatch failed: %v", err)
+		}
+		_, err = store.GetMatch(context.Background(), "match-0")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+		}
+		games, err = store.ListMatchGames(context.Background(), "match-0")
+		if err != nil || len(games) != 0 {
+			t.Errorf("Expected the games to be deleted with the match, got %+v, %v", games, err)
+		}
+	})
+}
+
+func TestAchievements(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := registerUser(t, store, "Alice")
+
+		unlocks := []struct {
+			id         string
+			unlockedAt string
+			want       int64
+		}{
+			{"first-win", "2024-01-02T10:00:00Z", 1},
+			{"first-game", "2024-01-01T10:00:00Z", 1},
+			{"first-win", "2024-01-03T10:00:00Z", 0},
+		}
+		for _, unlock := range unlocks {
+			unlocked, err := store.UnlockAchievement(context.Background(), database.UnlockAchievementParams{
+				UserID:        alice,
+				AchievementID: unlock.id,
+				GameID:        "game",
+				UnlockedAt:    unlock.unlockedAt,
+			})
+			if err != nil {
+				t.Fatalf("UnlockAchievement failed: %v", err)
+			}
+			if unlocked != unlock.want {
+				t.Errorf("Expected %d rows unlocking %s at %s, got %d", unlock.want, unlock.id, unlock.unlockedAt, unlocked)
+			}
+		}
+
+		achievements, err := store.ListAchievementsByUserID(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("ListAchievementsByUserID failed: %v", err)
+		}
+		if len(achievements) != 2 || achievements[0].AchievementID != "first-game" || achievements[1].UnlockedAt != "2024-01-02T10:00:00Z" {
+			t.Errorf("Expected the first unlocks in order, got %+v", achievements)
+		}
+
+		err = store.DeleteUser(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("DeleteUser failed: %v", err)
+		}
+		achievements, err = store.ListAchievementsByUserID(context.Background(), alice)
+		if err != nil {
+			t.Fatalf("ListAchievementsByUserID failed: %v", err)
+		}
+		if len(achievements) != 0 {
+			t.Errorf("Expected the achievements to be deleted with the user, got %+v", achievements)
+		}
+	})
+}
+
+func TestImport(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		bob := registerUser(t, store, "Bob")
+		alice := registerUser(t, store, "Alice")
+
+		users, err := store.ListUsers(context.Background())
+		if err != nil {
+			t.Fatalf("ListUsers failed: %v", err)
+		}
+		if len(users) != 2 || users[0].ID != alice || users[1].ID != bob {
+			t.Errorf("Expected users by name, got %+v", users)
+		}
+
+		record := database.ImportRecordParams{
+			ID:        "record",
+			UserID:    alice,
+			UpdatedAt: sql.NullString{String: "2024-01-02T10:00:00Z", Valid: true},
+			Wins:      sql.NullInt64{Int64: 3, Valid: true},
+			Rating:    1600,
+		}
+		// An empty updatedAt imports a record without an update time.
+		imports := []struct {
+			updatedAt string
+			wins      int64
+			want      int64
+		}{
+			{"", 2, 1},
+			{"", 4, 0},
+			{"2024-01-02T10:00:00Z", 3, 1},
+			{"2024-01-01T10:00:00Z", 1, 0},
+			{"", 9, 0},
+			{"2024-01-03T10:00:00Z", 5, 1},
+		}
+		for _, test := range imports {
+			record.UpdatedAt = sql.NullString{String: test.updatedAt, Valid: test.updatedAt != ""}
+			record.Wins.Int64 = test.wins
+			imported, err := store.ImportRecord(context.Background(), record)
+			if err != nil {
+				t.Fatalf("ImportRecord failed: %v", err)
+			}
+			if imported != test.want {
+				t.Errorf("Expected %d rows importing the record from %s, got %d", test.want, test.updatedAt, imported)
+			}
+		}
+		records, err := store.ListRecords(context.Background())
+		if err != nil {
+			t.Fatalf("ListRecords failed: %v", err)
+		}
+		if len(records) != 1 || records[0].Wins.Int64 != 5 || records[0].Rating != 1600 {
+			t.Errorf("Expected the newest record to be kept, got %+v", records)
+		}
+
+		game := database.ImportGameParams{
+			ID:          "game-1",
+			WhiteUserID: sql.NullString{String: alice, Valid: true},
+			Result:      "1-0",
+			Termination: "checkmate",
+			EndedAt:     "2024-01-01T10:00:00Z",
+			TimeControl: "-",
+		}
+		for _, want := range []int64{1, 0} {
+			imported, err := store.ImportGame(context.Background(), game)
+			if err != nil {
+				t.Fatalf("ImportGame failed: %v", err)
+			}
+			if imported != want {
+				t.Errorf("Expected %d rows importing the game, got %d", want, imported)
+			}
+		}
+		for _, want := range []int64{1, 0} {
+			imported, err := store.ImportRatingHistory(context.Background(), database.ImportRatingHistoryParams{
+				ID:           "history",
+				UserID:       alice,
+				GameID:       "game-1",
+				RatingBefore: 1500,
+				RatingAfter:  1510,
+			})
+			if err != nil {
+				t.Fatalf("ImportRatingHistory failed: %v", err)
+			}
+			if imported != want {
+				t.Errorf("Expected %d rows importing the rating history, got %d", want, imported)
+			}
+		}
+		_, err = store.UnlockAchievement(context.Background(), database.UnlockAchievementParams{
+			UserID:        bob,
+			AchievementID: "first-game",
+			GameID:        "game-1",
+			UnlockedAt:    "2024-01-01T10:00:00Z",
+		})
+		if err != nil {
+			t.Fatalf("UnlockAchievement failed: %v", err)
+		}
+
+		games, err := store.ListGames(context.Background())
+		if err != nil {
+			t.Fatalf("ListGames failed: %v", err)
+		}
+		history, err := store.ListRatingHistory(context.Background())
+		if err != nil {
+			t.Fatalf("ListRatingHistory failed: %v", err)
+		}
+		achievements, err := store.ListAchievements(context.Background())
+		if err != nil {
+			t.Fatalf("ListAchievements failed: %v", err)
+		}
+		if len(games) != 1 || len(history) != 1 || len(achievements) != 1 {
+			t.Errorf("Expected a single game, rating change and achievement, got %+v, %+v and %+v", games, history, achievements)
+		}
+
+		err = store.DeleteGames(context.Background())
+		if err != nil {
+			t.Fatalf("DeleteGames failed: %v", err)
+		}
+		games, err = store.ListGames(context.Background())
+		if err != nil {
+			t.Fatalf("ListGames failed: %v", err)
+		}
+		history, err = store.ListRatingHistory(context.Background())
+		if err != nil {
+			t.Fatalf("ListRatingHistory failed: %v", err)
+		}
+		if len(games) != 0 || len(history) != 0 {
+			t.Errorf("Expected games and their rating history to be deleted, got %+v and %+v", games, history)
+		}
+	})
+}
+
+func TestWithTx(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		err := store.WithTx(context.Background(), func(tx Store) error {
+			registerUser(t, tx, "Alice")
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+
+		failure := errors.New("failure")
+		err = store.WithTx(context.Background(), func(tx Store) error {
+			registerUser(t, tx, "Bob")
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected the error of fn, got %v", err)
+		}
+
+		count, err := store.CountUsers(context.Background())
+		if err != nil {
+			t.Fatalf("CountUsers failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected only the committed user, got %d users", count)
+		}
+	})
+}
+
+func TestDeleteAll(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		alice := sql.NullString{String: registerUser(t, store, "Alice"), Valid: true}
+
+		_, err := store.CreateTournament(context.Background(), database.CreateTournamentParams{
+			ID:        "tournament",
+			Name:      "Club Championship",
+			Format:    "round-robin",
+			CreatedAt: "2024-01-01T10:00:00Z",
+		})
+		if err != nil {
+			t.Fatalf("CreateTournament failed: %v", err)
+		}
+		_, err = store.AddTournamentPlayer(context.Background(), database.AddTournamentPlayerParams{
+			ID:           "player",
+			TournamentID: "tournament",
+			UserID:       alice,
+			Name:         "Alice",
+			Seed:         1,
+		})
+		if err != nil {
+			t.Fatalf("AddTournamentPlayer failed: %v", err)
+		}
+		_, err = store.CreateMatch(context.Background(), database.CreateMatchParams{
+			ID:            "match",
+			Player1UserID: alice,
+			Player1Name:   "Alice",
+			Player2Name:   "Guest",
+			Games:         3,
+			CreatedAt:     "2024-01-01T10:00:00Z",
+		})
+		if err != nil {
+			t.Fatalf("CreateMatch failed: %v", err)
+		}
+		// A game between guests is not deleted with the players.
+		_, err = store.SaveGame(context.Background(), database.SaveGameParams{
+			ID:  "saved",
+			Fen: "fen",
+		})
+		if err != nil {
+			t.Fatalf("SaveGame failed: %v", err)
+		}
+		_, err = store.CreateGame(context.Background(), database.CreateGameParams{
+			ID:          "game",
+			WhiteUserID: alice,
+			Result:      "1-0",
+			Termination: "checkmate",
+			TimeControl: "-",
+		})
+		if err != nil {
+			t.Fatalf("CreateGame failed: %v", err)
+		}
+
+		err = store.WithTx(context.Background(), func(tx Store) error {
+			return DeleteAll(context.Background(), tx)
+		})
+		if err != nil {
+			t.Fatalf("DeleteAll failed: %v", err)
+		}
+
+		count, err := store.CountUsers(context.Background())
+		if err != nil || count != 0 {
+			t.Errorf("Expected no users, got %d, %v", count, err)
+		}
+		games, err := store.ListGames(context.Background())
+		if err != nil || len(games) != 0 {
+			t.Errorf("Expected no games, got %+v, %v", games, err)
+		}
+		_, err = store.GetSavedGame(context.Background(), "saved")
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected the saved game to be deleted, got %v", err)
+		}
+		tournaments, err := store.ListTournaments(context.Background())
+		if err != nil || len(tournaments) != 0 {
+			t.Errorf("Expected no tournaments, got %+v, %v", tournaments, err)
+		}
+		players, err := store.ListTournamentPlayers(context.Background(), "tournament")
+		if err != nil || len(players) != 0 {
+			t.Errorf("Expected no tournament players, got %+v, %v", players, err)
+		}
+		matches, err := store.ListMatches(context.Background())
+		if err != nil || len(matches) != 0 {
+			t.Errorf("Expected no matches, got %+v, %v", matches, err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+// Package storagetest provides helpers for tests that need players in a
+// store.
+package storagetest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+// Hash is a well-formed Argon2id hash, so players registered by RegisterUser
+// pass the checks of an archive import.
+const Hash = "$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$a2tra2tra2tra2tra2tra2tra2tra2tra2tra2tra2s"
+
+// RegisterUser registers a player with the given ID and fails the test if
+// that is not possible.
+func RegisterUser(t testing.TB, store storage.Store, id, username string) *app.User {
+	t.Helper()
+
+	user, err := store.RegisterUser(context.Background(), database.RegisterUserParams{
+		ID:             id,
+		Username:       username,
+		HashedPassword: Hash,
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser failed: %v", err)
+	}
+
+	return &app.User{ID: user.ID, Username: user.Username}
+}
+
+// UserID returns the ID games and pairings store for user, which is null for
+// a guest.
+func UserID(user *app.User) sql.NullString {
+	if user == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: user.ID, Valid: true}
+}
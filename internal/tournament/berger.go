@@ -0,0 +1,71 @@
+package tournament
+
+// bye stands in for the opponent of a player who sits out a round.
+const bye = -1
+
+// pair holds the indexes of the seeded players on a board. black is bye
+// when white sits out the round.
+type pair struct {
+	white int
+	black int
+}
+
+// bergerRounds returns the pairings of a single round robin between n
+// seeded players, round by round, following the FIDE Berger tables. With
+// an odd number of players whoever meets the extra number has a bye.
+func bergerRounds(n int) [][]pair {
+	if n < 2 {
+		return nil
+	}
+
+	// Number the players from 1. The last number stays put while the others
+	// move on by half a table every round.
+	size := n + n%2
+	half := size / 2
+	rotate := func(number, round int) int {
+		return (number-1+round*half)%(size-1) + 1
+	}
+	index := func(number int) int {
+		if number > n {
+			return bye
+		}
+		return number - 1
+	}
+
+	rounds := make([][]pair, size-1)
+	for round := range rounds {
+		for board := range half {
+			var white, black int
+			if board == 0 {
+				white, black = rotate(1, round), size
+				if round%2 == 1 {
+					white, black = black, white
+				}
+			} else {
+				white, black = rotate(board+1, round), rotate(size-board, round)
+			}
+
+			p := pair{white: index(white), black: index(black)}
+			if p.white == bye {
+				p.white, p.black = p.black, bye
+			}
+			rounds[round] = append(rounds[round], p)
+		}
+	}
+
+	return rounds
+}
+
+// doubleRounds plays every round a second time with the colors reversed.
+func doubleRounds(rounds [][]pair) [][]pair {
+	second := make([][]pair, len(rounds))
+	for i, round := range rounds {
+		for _, p := range round {
+			if p.black != bye {
+				p.white, p.black = p.black, p.white
+			}
+			second[i] = append(second[i], p)
+		}
+	}
+	return append(rounds, second...)
+}
@@ -0,0 +1,108 @@
+package tournament
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBergerRoundsMatchesFIDETables(t *testing.T) {
+	tests := []struct {
+		players int
+		rounds  [][]pair
+	}{
+		{4, [][]pair{
+			{{0, 3}, {1, 2}},
+			{{3, 2}, {0, 1}},
+			{{1, 3}, {2, 0}},
+		}},
+		{6, [][]pair{
+			{{0, 5}, {1, 4}, {2, 3}},
+			{{5, 3}, {4, 2}, {0, 1}},
+			{{1, 5}, {2, 0}, {3, 4}},
+			{{5, 4}, {0, 3}, {1, 2}},
+			{{2, 5}, {3, 1}, {4, 0}},
+		}},
+		{3, [][]pair{
+			{{0, bye}, {1, 2}},
+			{{2, bye}, {0, 1}},
+			{{1, bye}, {2, 0}},
+		}},
+	}
+
+	for _, test := range tests {
+		rounds := bergerRounds(test.players)
+		if !reflect.DeepEqual(rounds, test.rounds) {
+			t.Errorf("bergerRounds(%d) = %v, want %v", test.players, rounds, test.rounds)
+		}
+	}
+}
+
+func TestBergerRoundsMeetsEveryoneOnce(t *testing.T) {
+	for n := 2; n <= maxPlayers; n++ {
+		rounds := bergerRounds(n)
+		if len(rounds) != n+n%2-1 {
+			t.Errorf("%d players: expected %d rounds, got %d", n, n+n%2-1, len(rounds))
+		}
+
+		met := map[[2]int]int{}
+		whites := make([]int, n)
+		byes := make([]int, n)
+		for r, round := range rounds {
+			seen := map[int]bool{}
+			for _, p := range round {
+				for _, player := range []int{p.white, p.black} {
+					if player == bye {
+						continue
+					}
+					if seen[player] {
+						t.Errorf("%d players: player %d plays twice in round %d", n, player, r+1)
+					}
+					seen[player] = true
+				}
+				if p.black == bye {
+					byes[p.white]++
+					continue
+				}
+				whites[p.white]++
+				met[[2]int{min(p.white, p.black), max(p.white, p.black)}]++
+			}
+			if len(seen) != n {
+				t.Errorf("%d players: only %d players in round %d", n, len(seen), r+1)
+			}
+		}
+
+		if len(met) != n*(n-1)/2 {
+			t.Errorf("%d players: expected %d different games, got %d", n, n*(n-1)/2, len(met))
+		}
+		for players, games := range met {
+			if games != 1 {
+				t.Errorf("%d players: %v meet %d times", n, players, games)
+			}
+		}
+		for player := range n {
+			if n%2 == 1 && byes[player] != 1 {
+				t.Errorf("%d players: player %d has %d byes", n, player, byes[player])
+			}
+			games := n - 1
+			if whites[player] < games/2 || whites[player] > (games+1)/2 {
+				t.Errorf("%d players: player %d has white %d times in %d games", n, player, whites[player], games)
+			}
+		}
+	}
+}
+
+func TestDoubleRounds(t *testing.T) {
+	rounds := doubleRounds(bergerRounds(3))
+	if len(rounds) != 6 {
+		t.Fatalf("Expected 6 rounds, got %d", len(rounds))
+	}
+
+	want := [][]pair{
+		{{0, bye}, {2, 1}},
+		{{2, bye}, {1, 0}},
+		{{1, bye}, {0, 2}},
+	}
+	if !reflect.DeepEqual(rounds[3:], want) {
+		t.Errorf("Expected the second cycle with colors reversed, got %v", rounds[3:])
+	}
+}
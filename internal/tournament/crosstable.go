@@ -0,0 +1,193 @@
+package tournament
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+)
+
+type standing struct {
+	player          database.TournamentPlayer
+	points          float64
+	games           int
+	sonnebornBerger float64
+	buchholz        float64
+	medianBuchholz  float64
+	// scores holds the player's game scores against each opponent by their
+	// player ID, in round order.
+	scores map[string][]float64
+}
+
+// standings ranks the players by points, then by seed after the
+// tiebreaks. A round robin is broken by Sonneborn-Berger score, the sum of
+// the points of every beaten opponent and half the points of every drawn
+// one. A Swiss tournament, where a bye scores a point, is broken by
+// Buchholz, the sum of the opponents' points, then by Median-Buchholz,
+// which leaves out the best and the worst opponent once there are three.
+func (s *tournamentState) standings() []standing {
+	swiss := s.tournament.Format == FormatSwiss
+
+	byID := map[string]*standing{}
+	table := make([]standing, len(s.players))
+	for i, player := range s.players {
+		table[i] = standing{player: player, scores: map[string][]float64{}}
+		byID[player.ID] = &table[i]
+	}
+
+	for _, pairing := range s.pairings {
+		if swiss && !pairing.BlackPlayerID.Valid {
+			if white := byID[pairing.WhitePlayerID]; white != nil {
+				white.points++
+			}
+			continue
+		}
+		if !pairing.BlackPlayerID.Valid || !pairing.Result.Valid {
+			continue
+		}
+		white, black := byID[pairing.WhitePlayerID], byID[pairing.BlackPlayerID.String]
+		if white == nil || black == nil {
+			continue
+		}
+
+		score := results.WhiteScore(pairing.Result.String)
+		white.add(black.player.ID, score)
+		black.add(white.player.ID, 1-score)
+	}
+
+	for i := range table {
+		var opponents []float64
+		for opponentID, scores := range table[i].scores {
+			for _, score := range scores {
+				table[i].sonnebornBerger += score * byID[opponentID].points
+				opponents = append(opponents, byID[opponentID].points)
+			}
+		}
+		for _, points := range opponents {
+			table[i].buchholz += points
+		}
+		table[i].medianBuchholz = table[i].buchholz
+		if len(opponents) >= 3 {
+			table[i].medianBuchholz -= slices.Max(opponents) + slices.Min(opponents)
+		}
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.points != b.points {
+			return a.points > b.points
+		}
+		if swiss {
+			if a.buchholz != b.buchholz {
+				return a.buchholz > b.buchholz
+			}
+			if a.medianBuchholz != b.medianBuchholz {
+				return a.medianBuchholz > b.medianBuchholz
+			}
+		} else if a.sonnebornBerger != b.sonnebornBerger {
+			return a.sonnebornBerger > b.sonnebornBerger
+		}
+		return a.player.Seed < b.player.Seed
+	})
+
+	return table
+}
+
+func (st *standing) add(opponentID string, score float64) {
+	st.scores[opponentID] = append(st.scores[opponentID], score)
+	st.points += score
+	st.games++
+}
+
+func scoreSymbol(score float64) string {
+	switch score {
+	case 1:
+		return "1"
+	case 0:
+		return "0"
+	}
+	return "½"
+}
+
+// pad left-aligns s in a column of width runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
+
+// crosstable renders the standings with a column per opponent in ranking
+// order. Every cell lists the games between the two players, with a dot
+// for a game still to be played.
+func (s *tournamentState) crosstable() string {
+	table := s.standings()
+
+	cycles := 1
+	if s.tournament.Format == FormatDoubleRoundRobin {
+		cycles = 2
+	}
+	nameWidth := len("Player")
+	for _, st := range table {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(st.player.Name))
+	}
+	cellWidth := max(cycles, len(fmt.Sprint(len(table))))
+
+	header := pad("#", 3) + pad("Player", nameWidth+2)
+	for i := range table {
+		header += pad(fmt.Sprint(i+1), cellWidth+1)
+	}
+	header += pad("Pts", 5) + "SB"
+
+	lines := []string{header}
+	for i, st := range table {
+		line := pad(fmt.Sprint(i+1), 3) + pad(st.player.Name, nameWidth+2)
+		for j, opponent := range table {
+			cell := strings.Repeat("X", cycles)
+			if i != j {
+				cell = ""
+				scores := st.scores[opponent.player.ID]
+				for _, score := range scores {
+					cell += scoreSymbol(score)
+				}
+				cell += strings.Repeat(".", max(cycles-len(scores), 0))
+			}
+			line += pad(cell, cellWidth+1)
+		}
+		line += pad(results.FormatPoints(st.points), 5) + results.FormatPoints(st.sonnebornBerger)
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// swissTable renders the standings of a Swiss tournament, too many players
+// for a crosstable, with the games played and both tiebreaks.
+func (s *tournamentState) swissTable() string {
+	table := s.standings()
+
+	nameWidth := len("Player")
+	for _, st := range table {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(st.player.Name))
+	}
+	rankWidth := len(fmt.Sprint(len(table))) + 2
+
+	lines := []string{pad("#", rankWidth) + pad("Player", nameWidth+2) + "Games  Pts  Buch   M-Buch"}
+	for i, st := range table {
+		line := pad(fmt.Sprint(i+1), rankWidth) + pad(st.player.Name, nameWidth+2)
+		line += pad(fmt.Sprint(st.games), 7) + pad(results.FormatPoints(st.points), 5)
+		line += pad(results.FormatPoints(st.buchholz), 7) + results.FormatPoints(st.medianBuchholz)
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// table renders the standings in the tournament's format.
+func (s *tournamentState) table() string {
+	if s.tournament.Format == FormatSwiss {
+		return s.swissTable()
+	}
+	return s.crosstable()
+}
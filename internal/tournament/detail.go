@@ -0,0 +1,378 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+	"github.com/deskdaniel/GoMate/internal/results"
+)
+
+type tournamentModel struct {
+	ctx   *app.Context
+	id    string
+	state *tournamentState
+	// Before the start focusIndex runs over the players, the add player row
+	// and the start button. Afterwards it picks a game of the shown round.
+	focusIndex int
+	candidate  int
+	guestName  textinput.Model
+	round      int64
+	err        error
+}
+
+func SetupTournament(ctx *app.Context, id string) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupTournament called with nil ctx or nil ctx.Store")
+	}
+
+	guestName := textinput.New()
+	guestName.Prompt = "Guest name: "
+	guestName.Placeholder = "name"
+	guestName.CharLimit = maxNameLength
+	guestName.Width = 30
+
+	m := tournamentModel{
+		ctx:       ctx,
+		id:        id,
+		guestName: guestName,
+	}
+	m.load()
+	if m.state != nil {
+		m.round = m.currentRound()
+	}
+
+	return &m
+}
+
+// load reads the tournament, pairing the next Swiss round first when the
+// last one is over.
+func (m *tournamentModel) load() {
+	m.err = pairNextRound(m.ctx, m.id)
+
+	state, err := loadTournament(m.ctx, m.id)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.state = state
+}
+
+// currentRound returns the first round with a game still to be played, or
+// the last paired round once every game is over.
+func (m *tournamentModel) currentRound() int64 {
+	for _, pairing := range m.state.pairings {
+		if pairing.BlackPlayerID.Valid && !pairing.Result.Valid {
+			return pairing.Round
+		}
+	}
+	return max(m.state.pairedRounds(), 1)
+}
+
+// candidates lists who can be added: the signed-in players not yet in the
+// tournament, followed by a guest.
+func (m *tournamentModel) candidates() []*app.User {
+	var users []*app.User
+	for _, user := range m.ctx.Roster {
+		entered := false
+		for _, player := range m.state.players {
+			if player.UserID.Valid && player.UserID.String == user.ID {
+				entered = true
+			}
+		}
+		if !entered {
+			users = append(users, user)
+		}
+	}
+	return append(users, nil)
+}
+
+// games returns the games of the shown round, leaving out byes.
+func (m *tournamentModel) games() []database.TournamentPairing {
+	var games []database.TournamentPairing
+	for _, pairing := range m.state.pairings {
+		if pairing.Round == m.round && pairing.BlackPlayerID.Valid {
+			games = append(games, pairing)
+		}
+	}
+	return games
+}
+
+func (m *tournamentModel) addRow() int {
+	return len(m.state.players)
+}
+
+func (m *tournamentModel) startRow() int {
+	return len(m.state.players) + 1
+}
+
+func (m *tournamentModel) focusGuestName() tea.Cmd {
+	candidates := m.candidates()
+	m.candidate = min(m.candidate, len(candidates)-1)
+	if m.focusIndex == m.addRow() && candidates[m.candidate] == nil {
+		return m.guestName.Focus()
+	}
+	m.guestName.Blur()
+	return nil
+}
+
+func (m *tournamentModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tournamentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg {
+				return messages.SwitchToTournaments{}
+			}
+		}
+		if m.state == nil {
+			return m, nil
+		}
+		if m.state.started() {
+			return m, m.updateStarted(msg)
+		}
+		return m, m.updateEntries(msg)
+	case error:
+		m.ctx.Pairing = nil
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *tournamentModel) updateEntries(msg tea.KeyMsg) tea.Cmd {
+	rows := m.startRow() + 1
+
+	switch msg.String() {
+	case "up", "shift+tab":
+		m.focusIndex = (m.focusIndex + rows - 1) % rows
+		return m.focusGuestName()
+	case "down", "tab":
+		m.focusIndex = (m.focusIndex + 1) % rows
+		return m.focusGuestName()
+	case "left", "right":
+		if m.focusIndex != m.addRow() {
+			return nil
+		}
+		n := len(m.candidates())
+		if msg.String() == "left" {
+			m.candidate = (m.candidate + n - 1) % n
+		} else {
+			m.candidate = (m.candidate + 1) % n
+		}
+		return m.focusGuestName()
+	case "x", "delete":
+		if m.focusIndex < m.addRow() {
+			err := removePlayer(m.ctx, m.id, m.state.players[m.focusIndex].ID)
+			m.load()
+			m.err = err
+			m.focusIndex = min(m.focusIndex, m.addRow())
+			return m.focusGuestName()
+		}
+	case "enter":
+		switch m.focusIndex {
+		case m.addRow():
+			user := m.candidates()[m.candidate]
+			err := addPlayer(m.ctx, m.id, user, m.guestName.Value())
+			if err != nil {
+				m.err = err
+				return nil
+			}
+			m.guestName.SetValue("")
+			m.load()
+			m.focusIndex = m.addRow()
+			return m.focusGuestName()
+		case m.startRow():
+			err := startTournament(m.ctx, m.id, time.Now())
+			m.load()
+			m.err = err
+			if err == nil {
+				m.focusIndex = 0
+				m.round = m.currentRound()
+				m.guestName.Blur()
+			}
+			return nil
+		}
+		return nil
+	}
+
+	if m.guestName.Focused() {
+		var cmd tea.Cmd
+		m.guestName, cmd = m.guestName.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *tournamentModel) updateStarted(msg tea.KeyMsg) tea.Cmd {
+	games := m.games()
+
+	switch msg.String() {
+	case "up":
+		if len(games) > 0 {
+			m.focusIndex = (m.focusIndex + len(games) - 1) % len(games)
+		}
+	case "down":
+		if len(games) > 0 {
+			m.focusIndex = (m.focusIndex + 1) % len(games)
+		}
+	case "left":
+		if m.round > 1 {
+			m.round--
+			m.focusIndex = 0
+		}
+	case "right":
+		if m.round < m.state.pairedRounds() {
+			m.round++
+			m.focusIndex = 0
+		}
+	case "enter":
+		if m.focusIndex < len(games) {
+			return m.play(games[m.focusIndex])
+		}
+	}
+
+	return nil
+}
+
+// play starts the game of a pairing, or resumes it when it was interrupted.
+func (m *tournamentModel) play(pairing database.TournamentPairing) tea.Cmd {
+	gamePairing, err := m.state.gamePairing(pairing)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.ctx.Pairing = gamePairing
+
+	if pairing.GameID.Valid {
+		_, err := m.ctx.Store.GetSavedGame(context.Background(), pairing.GameID.String)
+		if err == nil {
+			gameID := pairing.GameID.String
+			return func() tea.Msg {
+				return messages.SwitchToResumeGame{GameID: gameID}
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		return messages.SwitchToGame{}
+	}
+}
+
+func (m *tournamentModel) name(playerID string) string {
+	player, ok := m.state.player(playerID)
+	if !ok {
+		return "?"
+	}
+	return player.Name
+}
+
+func (m *tournamentModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.state == nil {
+		s := "Tournament\n\n"
+		if m.err != nil {
+			s += errStyle.Render(m.err.Error()) + "\n"
+		}
+		return s + "\nPress esc to go back.\n"
+	}
+
+	t := m.state.tournament
+	s := fmt.Sprintf("%s - %s\n\n", t.Name, formatName(t.Format))
+
+	if !m.state.started() {
+		labels := []string{}
+		for _, player := range m.state.players {
+			label := fmt.Sprintf("%d. %s", player.Seed, player.Name)
+			if !player.UserID.Valid {
+				label += " (guest)"
+			}
+			labels = append(labels, label)
+		}
+		candidate := m.candidates()[min(m.candidate, len(m.candidates())-1)]
+		if candidate != nil {
+			labels = append(labels, fmt.Sprintf("Add player: < %s >", candidate.Username))
+		} else {
+			labels = append(labels, "Add player: < Guest >")
+		}
+		labels = append(labels, "[ Start ]")
+
+		for i, label := range labels {
+			if i == m.focusIndex {
+				s += highlightStyle.Render(label) + "\n"
+			} else {
+				s += buttonStyle.Render(label) + "\n"
+			}
+			if i == m.addRow() && candidate == nil {
+				s += m.guestName.View() + "\n"
+			}
+		}
+
+		if m.err != nil {
+			s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+		}
+		s += fmt.Sprintf("\nAdd %d to %d players: signed-in players or guests.\n", minPlayers, playerLimit(t.Format))
+		s += "Use up/down arrows to navigate, left/right to pick a player, enter to add.\n"
+		s += "Press x to remove a player, esc to go back.\n"
+		return s
+	}
+
+	s += m.state.table() + "\n"
+
+	played, total := m.state.progress()
+	if m.state.finished() {
+		winner := m.state.standings()[0]
+		s += fmt.Sprintf("Tournament finished! %s wins with %s points.\n\n", winner.player.Name, results.FormatPoints(winner.points))
+	} else {
+		s += fmt.Sprintf("Games played: %d/%d\n\n", played, total)
+	}
+
+	s += fmt.Sprintf("Round %d of %d\n", m.round, t.Rounds)
+	i := 0
+	for _, pairing := range m.state.pairings {
+		if pairing.Round != m.round {
+			continue
+		}
+		if !pairing.BlackPlayerID.Valid {
+			label := fmt.Sprintf("   %s has a bye", m.name(pairing.WhitePlayerID))
+			if t.Format == FormatSwiss {
+				label += " (1 point)"
+			}
+			s += buttonStyle.Render(label) + "\n"
+			continue
+		}
+
+		result := "-"
+		if pairing.Result.Valid {
+			result = pairing.Result.String
+		}
+		label := fmt.Sprintf("%d. %s - %s  %s", pairing.Board, m.name(pairing.WhitePlayerID), m.name(pairing.BlackPlayerID.String), result)
+		if i == m.focusIndex {
+			s += highlightStyle.Render(label) + "\n"
+		} else {
+			s += buttonStyle.Render(label) + "\n"
+		}
+		i++
+	}
+
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+	s += "\nUse up/down arrows to pick a game, enter to play it, left/right to change the round.\n"
+	s += "Press esc to go back.\n"
+
+	return s
+}
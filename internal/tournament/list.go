@@ -0,0 +1,230 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/messages"
+)
+
+type tournamentsModel struct {
+	ctx         *app.Context
+	tournaments []database.Tournament
+	// focusIndex 0 is the new tournament entry, the tournaments follow.
+	focusIndex int
+	confirming bool
+	creating   bool
+	// field is the focused row of the create form: the name, the format or
+	// the number of rounds of a Swiss tournament.
+	field  int
+	name   textinput.Model
+	format int
+	rounds int64
+	err    error
+}
+
+func SetupTournaments(ctx *app.Context) tea.Model {
+	if ctx == nil || ctx.Store == nil {
+		panic("SetupTournaments called with nil ctx or nil ctx.Store")
+	}
+
+	name := textinput.New()
+	name.Prompt = "Name: "
+	name.Placeholder = "tournament name"
+	name.CharLimit = maxNameLength
+	name.Width = 30
+
+	m := tournamentsModel{
+		ctx:    ctx,
+		name:   name,
+		rounds: defaultSwissRounds,
+	}
+	m.load()
+
+	return &m
+}
+
+func (m *tournamentsModel) load() {
+	tournaments, err := m.ctx.Store.ListTournaments(context.Background())
+	if err != nil {
+		m.err = fmt.Errorf("failed to list tournaments: %w", err)
+		return
+	}
+	m.tournaments = tournaments
+	m.focusIndex = min(m.focusIndex, len(m.tournaments))
+}
+
+func (m *tournamentsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tournamentsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.err = deleteTournament(m.ctx, m.tournaments[m.focusIndex-1].ID)
+				m.load()
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		if m.creating {
+			return m, m.updateCreate(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg {
+				return messages.SwitchToMainMenu{}
+			}
+		case "up":
+			m.focusIndex = (m.focusIndex + len(m.tournaments)) % (len(m.tournaments) + 1)
+		case "down":
+			m.focusIndex = (m.focusIndex + 1) % (len(m.tournaments) + 1)
+		case "d":
+			if m.focusIndex > 0 {
+				m.confirming = true
+			}
+		case "enter":
+			if m.focusIndex == 0 {
+				m.creating = true
+				m.field = 0
+				m.err = nil
+				m.name.SetValue("")
+				return m, m.name.Focus()
+			}
+			id := m.tournaments[m.focusIndex-1].ID
+			return m, func() tea.Msg {
+				return messages.SwitchToTournament{ID: id}
+			}
+		}
+	case error:
+		m.err = msg
+	}
+
+	return m, nil
+}
+
+func (m *tournamentsModel) updateCreate(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.creating = false
+		m.err = nil
+		m.name.Blur()
+		return nil
+	case "tab", "down", "shift+tab", "up":
+		fields := 2
+		if formats[m.format] == FormatSwiss {
+			fields = 3
+		}
+		if msg.String() == "tab" || msg.String() == "down" {
+			m.field = (m.field + 1) % fields
+		} else {
+			m.field = (m.field + fields - 1) % fields
+		}
+		if m.field == 0 {
+			return m.name.Focus()
+		}
+		m.name.Blur()
+		return nil
+	case "left", "right":
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		switch m.field {
+		case 1:
+			m.format = (m.format + step + len(formats)) % len(formats)
+			return nil
+		case 2:
+			m.rounds = min(max(m.rounds+int64(step), minSwissRounds), maxSwissRounds)
+			return nil
+		}
+	case "enter":
+		tournament, err := createTournament(m.ctx, m.name.Value(), formats[m.format], m.rounds, time.Now())
+		if err != nil {
+			m.err = err
+			return nil
+		}
+		return func() tea.Msg {
+			return messages.SwitchToTournament{ID: tournament.ID}
+		}
+	}
+
+	if !m.name.Focused() {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.name, cmd = m.name.Update(msg)
+	return cmd
+}
+
+func (m *tournamentsModel) View() string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	buttonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Bold(true)
+
+	if m.creating {
+		s := "New Tournament\n\n"
+		s += m.name.View() + "\n\n"
+		rows := []string{fmt.Sprintf("Format: < %s >", formatName(formats[m.format]))}
+		if formats[m.format] == FormatSwiss {
+			rows = append(rows, fmt.Sprintf("Rounds: < %d >", m.rounds))
+		}
+		for i, row := range rows {
+			if i+1 == m.field {
+				s += highlightStyle.Render(row) + "\n"
+			} else {
+				s += buttonStyle.Render(row) + "\n"
+			}
+		}
+		if m.err != nil {
+			s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+		}
+		s += "\nUse up/down arrows to navigate, left/right to change a setting, enter to create.\n"
+		s += "Press esc to go back.\n"
+		return s
+	}
+
+	s := "Tournaments\n\n"
+	labels := []string{"[ New tournament ]"}
+	for _, tournament := range m.tournaments {
+		status := "not started"
+		if tournament.StartedAt.Valid {
+			status = fmt.Sprintf("%d rounds", tournament.Rounds)
+		} else if tournament.Format == FormatSwiss {
+			status = fmt.Sprintf("%d rounds, not started", tournament.Rounds)
+		}
+		labels = append(labels, fmt.Sprintf("%s - %s, %s", tournament.Name, formatName(tournament.Format), status))
+	}
+	for i, label := range labels {
+		if i == m.focusIndex {
+			s += highlightStyle.Render(label) + "\n"
+		} else {
+			s += buttonStyle.Render(label) + "\n"
+		}
+	}
+
+	if m.confirming {
+		s += "\n" + errStyle.Render(fmt.Sprintf("Delete tournament %s with all its pairings? Finished games stay in the history. (y/n)", m.tournaments[m.focusIndex-1].Name)) + "\n"
+	}
+	if m.err != nil {
+		s += "\n" + errStyle.Render(m.err.Error()) + "\n"
+	}
+
+	s += "\nUse up/down arrows to navigate, enter to open, d to delete.\n"
+	s += "Press esc to return to main menu.\n"
+
+	return s
+}
@@ -0,0 +1,241 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/deskdaniel/GoMate/internal/results"
+)
+
+const (
+	minSwissRounds     = 3
+	maxSwissRounds     = 11
+	defaultSwissRounds = 5
+	maxSwissPlayers    = 64
+	// swissSearchLimit bounds the pairing search, which backtracks when
+	// repeat games leave no way to complete a round.
+	swissSearchLimit = 200000
+)
+
+// swissPlayer is a player's record going into a Swiss round.
+type swissPlayer struct {
+	// index is the player's position in the seed order.
+	index     int
+	points    float64
+	opponents map[int]bool
+	// colors lists the colors played, true for white, in round order.
+	colors []bool
+	hadBye bool
+}
+
+// balance returns the number of games with white minus those with black.
+func (p *swissPlayer) balance() int {
+	balance := 0
+	for _, white := range p.colors {
+		if white {
+			balance++
+		} else {
+			balance--
+		}
+	}
+	return balance
+}
+
+// mustPlay returns the color a player has to get next: the other color
+// after playing the same one twice in a row or twice more often.
+func (p *swissPlayer) mustPlay() (white bool, ok bool) {
+	balance := p.balance()
+	switch {
+	case balance >= 2:
+		return false, true
+	case balance <= -2:
+		return true, true
+	}
+	if n := len(p.colors); n >= 2 && p.colors[n-1] == p.colors[n-2] {
+		return !p.colors[n-1], true
+	}
+	return false, false
+}
+
+// swissPlayers returns the players' records from the rounds paired so far,
+// in ranking order for the next round: by points, then by seed.
+func (s *tournamentState) swissPlayers() []*swissPlayer {
+	players := make([]*swissPlayer, len(s.players))
+	byID := map[string]*swissPlayer{}
+	for i, player := range s.players {
+		players[i] = &swissPlayer{index: i, opponents: map[int]bool{}}
+		byID[player.ID] = players[i]
+	}
+
+	for _, pairing := range s.pairings {
+		white := byID[pairing.WhitePlayerID]
+		if white == nil {
+			continue
+		}
+		if !pairing.BlackPlayerID.Valid {
+			white.hadBye = true
+			white.points++
+			continue
+		}
+		black := byID[pairing.BlackPlayerID.String]
+		if black == nil {
+			continue
+		}
+
+		white.opponents[black.index] = true
+		black.opponents[white.index] = true
+		white.colors = append(white.colors, true)
+		black.colors = append(black.colors, false)
+		if pairing.Result.Valid {
+			score := results.WhiteScore(pairing.Result.String)
+			white.points += score
+			black.points += 1 - score
+		}
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].points > players[j].points
+	})
+
+	return players
+}
+
+// swissRound pairs the next round with the basics of the Dutch system:
+// each score group is split in halves and the top half meets the bottom
+// half, leftover players float down to the next group, nobody meets the
+// same opponent twice and colors are balanced. With an odd number of
+// players the lowest ranked player without a bye so far gets one.
+func (s *tournamentState) swissRound() ([]pair, error) {
+	players := s.swissPlayers()
+
+	// Color constraints are dropped only when no pairing satisfies them.
+	for _, strict := range []bool{true, false} {
+		budget := swissSearchLimit
+
+		if len(players)%2 == 0 {
+			pairs, ok := pairSwiss(players, strict, &budget)
+			if ok {
+				return colorSwiss(pairs), nil
+			}
+			continue
+		}
+
+		for i := len(players) - 1; i >= 0; i-- {
+			if players[i].hadBye {
+				continue
+			}
+			rest := append(append([]*swissPlayer{}, players[:i]...), players[i+1:]...)
+			pairs, ok := pairSwiss(rest, strict, &budget)
+			if ok {
+				return append(colorSwiss(pairs), pair{white: players[i].index, black: bye}), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no pairing without repeat games is left for round %d", s.pairedRounds()+1)
+}
+
+// pairSwiss pairs players given in ranking order, the highest ranked
+// player first, and backtracks when the rest cannot be paired.
+func pairSwiss(players []*swissPlayer, strict bool, budget *int) ([][2]*swissPlayer, bool) {
+	if len(players) == 0 {
+		return nil, true
+	}
+	*budget--
+	if *budget < 0 {
+		return nil, false
+	}
+
+	top := players[0]
+	rest := players[1:]
+	for _, i := range swissCandidates(top, rest) {
+		opponent := rest[i]
+		if top.opponents[opponent.index] || (strict && colorClash(top, opponent)) {
+			continue
+		}
+
+		others := append(append([]*swissPlayer{}, rest[:i]...), rest[i+1:]...)
+		pairs, ok := pairSwiss(others, strict, budget)
+		if ok {
+			return append([][2]*swissPlayer{{top, opponent}}, pairs...), true
+		}
+		if *budget < 0 {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// swissCandidates orders the opponents for top by preference. Within its
+// score group top meets the player half the group below it first, then
+// the rest of the bottom half, then the top half. Players of lower score
+// groups follow in ranking order.
+func swissCandidates(top *swissPlayer, rest []*swissPlayer) []int {
+	group := 0
+	for group < len(rest) && rest[group].points == top.points {
+		group++
+	}
+
+	// top is the first player of its group, so the bottom half of the
+	// group starts at index half of rest.
+	half := max((group+1)/2-1, 0)
+	order := make([]int, 0, len(rest))
+	for i := half; i < group; i++ {
+		order = append(order, i)
+	}
+	for i := half - 1; i >= 0; i-- {
+		order = append(order, i)
+	}
+	for i := group; i < len(rest); i++ {
+		order = append(order, i)
+	}
+	return order
+}
+
+// colorClash reports whether both players have to get the same color.
+func colorClash(a, b *swissPlayer) bool {
+	aWhite, aOK := a.mustPlay()
+	bWhite, bOK := b.mustPlay()
+	return aOK && bOK && aWhite == bWhite
+}
+
+// colorSwiss gives the colors for pairs of players, the higher ranked
+// player first. A color a player has to get wins, then the player who had
+// white less often gets it, then the players alternate from the last round
+// in which their colors differed. In the first round the higher ranked
+// player has white on odd boards.
+func colorSwiss(pairs [][2]*swissPlayer) []pair {
+	colored := make([]pair, len(pairs))
+	for board, p := range pairs {
+		a, b := p[0], p[1]
+		colored[board] = pair{white: b.index, black: a.index}
+		if swissWhiteFirst(a, b, board) {
+			colored[board] = pair{white: a.index, black: b.index}
+		}
+	}
+	return colored
+}
+
+// swissWhiteFirst reports whether the higher ranked player a gets white.
+func swissWhiteFirst(a, b *swissPlayer, board int) bool {
+	if white, ok := a.mustPlay(); ok {
+		return white
+	}
+	if white, ok := b.mustPlay(); ok {
+		return !white
+	}
+	if a.balance() != b.balance() {
+		return a.balance() < b.balance()
+	}
+
+	for i, j := len(a.colors)-1, len(b.colors)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if a.colors[i] != b.colors[j] {
+			return !a.colors[i]
+		}
+	}
+	if len(a.colors) > 0 {
+		return !a.colors[len(a.colors)-1]
+	}
+	return board%2 == 0
+}
@@ -0,0 +1,289 @@
+package tournament
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+)
+
+func startSwiss(t *testing.T, players int, rounds int64) (*app.Context, string) {
+	t.Helper()
+
+	ctx := &app.Context{Store: storage.NewMemory()}
+	tournament, err := createTournament(ctx, "Club Night", FormatSwiss, rounds, time.Now())
+	if err != nil {
+		t.Fatalf("createTournament failed: %v", err)
+	}
+	for i := 1; i <= players; i++ {
+		err = addPlayer(ctx, tournament.ID, nil, fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("addPlayer failed: %v", err)
+		}
+	}
+
+	err = startTournament(ctx, tournament.ID, time.Now())
+	if err != nil {
+		t.Fatalf("startTournament failed: %v", err)
+	}
+	return ctx, tournament.ID
+}
+
+func setResult(t *testing.T, ctx *app.Context, pairing database.TournamentPairing, result string) {
+	t.Helper()
+
+	_, err := ctx.Store.SetTournamentPairingResult(context.Background(), database.SetTournamentPairingResultParams{
+		Result: sql.NullString{String: result, Valid: true},
+		ID:     pairing.ID,
+	})
+	if err != nil {
+		t.Fatalf("SetTournamentPairingResult failed: %v", err)
+	}
+}
+
+// seedPairs returns the pairs of a round as seed numbers, with 0 for a bye.
+func seedPairs(s *tournamentState, round int64) [][2]int64 {
+	var pairs [][2]int64
+	for _, pairing := range s.pairings {
+		if pairing.Round != round {
+			continue
+		}
+		white, _ := s.player(pairing.WhitePlayerID)
+		black, _ := s.player(pairing.BlackPlayerID.String)
+		pairs = append(pairs, [2]int64{white.Seed, black.Seed})
+	}
+	return pairs
+}
+
+func TestSwissFirstRound(t *testing.T) {
+	ctx, id := startSwiss(t, 7, 3)
+
+	state, err := loadTournament(ctx, id)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	if state.tournament.Rounds != 3 || state.pairedRounds() != 1 {
+		t.Fatalf("Expected round 1 of 3 to be paired, got %d of %d", state.pairedRounds(), state.tournament.Rounds)
+	}
+
+	// The top half meets the bottom half with alternating colors and the
+	// lowest seed gets the bye.
+	want := [][2]int64{{1, 4}, {5, 2}, {3, 6}, {7, 0}}
+	if got := seedPairs(state, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected round 1 pairings %v, got %v", want, got)
+	}
+
+	err = pairNextRound(ctx, id)
+	if err != nil {
+		t.Fatalf("pairNextRound failed: %v", err)
+	}
+	state, err = loadTournament(ctx, id)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	if state.pairedRounds() != 1 {
+		t.Error("Expected round 2 to wait for the results of round 1")
+	}
+}
+
+func TestSwissScoreGroups(t *testing.T) {
+	ctx, id := startSwiss(t, 6, 3)
+
+	state, err := loadTournament(ctx, id)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	// Round 1 is 1-4, 5-2 and 3-6: seeds 1 and 2 win, 3 and 6 draw.
+	for _, pairing := range state.pairings {
+		white, _ := state.player(pairing.WhitePlayerID)
+		switch white.Seed {
+		case 1:
+			setResult(t, ctx, pairing, results.WhiteWins)
+		case 5:
+			setResult(t, ctx, pairing, results.BlackWins)
+		case 3:
+			setResult(t, ctx, pairing, results.Draw)
+		}
+	}
+
+	err = pairNextRound(ctx, id)
+	if err != nil {
+		t.Fatalf("pairNextRound failed: %v", err)
+	}
+	state, err = loadTournament(ctx, id)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+
+	// The winners meet. The drawn players cannot meet again, so both float
+	// down to the losers, and every player gets the other color than in
+	// round 1.
+	want := [][2]int64{{2, 1}, {4, 3}, {6, 5}}
+	if got := seedPairs(state, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected round 2 pairings %v, got %v", want, got)
+	}
+}
+
+func TestSwissTournament(t *testing.T) {
+	tests := []struct {
+		players int
+		rounds  int64
+	}{
+		{5, 4},
+		{8, 5},
+		{9, 7},
+		{16, 5},
+		{31, 9},
+	}
+
+	outcomes := []string{results.WhiteWins, results.BlackWins, results.Draw, results.WhiteWins, results.BlackWins}
+	for _, test := range tests {
+		ctx, id := startSwiss(t, test.players, test.rounds)
+
+		for game := 0; ; {
+			state, err := loadTournament(ctx, id)
+			if err != nil {
+				t.Fatalf("loadTournament failed: %v", err)
+			}
+			if state.finished() {
+				break
+			}
+			round := state.pairedRounds()
+			for _, pairing := range state.pairings {
+				if pairing.Round == round && pairing.BlackPlayerID.Valid {
+					setResult(t, ctx, pairing, outcomes[game%len(outcomes)])
+					game++
+				}
+			}
+			err = pairNextRound(ctx, id)
+			if err != nil {
+				t.Fatalf("%d players: pairNextRound after round %d failed: %v", test.players, round, err)
+			}
+		}
+
+		state, err := loadTournament(ctx, id)
+		if err != nil {
+			t.Fatalf("loadTournament failed: %v", err)
+		}
+		if state.pairedRounds() != test.rounds {
+			t.Errorf("%d players: expected %d rounds, got %d", test.players, test.rounds, state.pairedRounds())
+		}
+
+		met := map[[2]string]bool{}
+		byes := map[string]int{}
+		for round := int64(1); round <= test.rounds; round++ {
+			seen := map[string]bool{}
+			for _, pairing := range state.pairings {
+				if pairing.Round != round {
+					continue
+				}
+				for _, id := range []string{pairing.WhitePlayerID, pairing.BlackPlayerID.String} {
+					if id == "" {
+						continue
+					}
+					if seen[id] {
+						t.Errorf("%d players: %s plays twice in round %d", test.players, id, round)
+					}
+					seen[id] = true
+				}
+				if !pairing.BlackPlayerID.Valid {
+					byes[pairing.WhitePlayerID]++
+					continue
+				}
+				key := [2]string{min(pairing.WhitePlayerID, pairing.BlackPlayerID.String), max(pairing.WhitePlayerID, pairing.BlackPlayerID.String)}
+				if met[key] {
+					t.Errorf("%d players: %v meet twice", test.players, key)
+				}
+				met[key] = true
+			}
+			if len(seen) != test.players {
+				t.Errorf("%d players: only %d players in round %d", test.players, len(seen), round)
+			}
+		}
+		for id, n := range byes {
+			if n > 1 {
+				t.Errorf("%d players: %s has %d byes", test.players, id, n)
+			}
+		}
+
+		for _, player := range state.swissPlayers() {
+			if balance := player.balance(); balance < -2 || balance > 2 {
+				t.Errorf("%d players: player %d has a color balance of %d", test.players, player.index, balance)
+			}
+		}
+	}
+}
+
+func TestSwissNeedsMorePlayersThanRounds(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+	tournament, err := createTournament(ctx, "Club Night", FormatSwiss, 5, time.Now())
+	if err != nil {
+		t.Fatalf("createTournament failed: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		err = addPlayer(ctx, tournament.ID, nil, fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("addPlayer failed: %v", err)
+		}
+	}
+
+	err = startTournament(ctx, tournament.ID, time.Now())
+	if err == nil {
+		t.Error("Expected a Swiss tournament with as many players as rounds not to start")
+	}
+}
+
+func TestSwissStandings(t *testing.T) {
+	black := func(id string) sql.NullString {
+		return sql.NullString{String: id, Valid: true}
+	}
+	result := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: true}
+	}
+
+	state := &tournamentState{
+		tournament: database.Tournament{Format: FormatSwiss, Rounds: 3},
+		players: []database.TournamentPlayer{
+			{ID: "a", Name: "Alice", Seed: 1},
+			{ID: "b", Name: "Bob", Seed: 2},
+			{ID: "c", Name: "Carol", Seed: 3},
+			{ID: "d", Name: "Dave", Seed: 4},
+			{ID: "e", Name: "Erin", Seed: 5},
+		},
+		pairings: []database.TournamentPairing{
+			{Round: 1, WhitePlayerID: "a", BlackPlayerID: black("c"), Result: result("1-0")},
+			{Round: 1, WhitePlayerID: "d", BlackPlayerID: black("b"), Result: result("0-1")},
+			{Round: 1, WhitePlayerID: "e"},
+			{Round: 2, WhitePlayerID: "b", BlackPlayerID: black("a"), Result: result("1/2-1/2")},
+			{Round: 2, WhitePlayerID: "e", BlackPlayerID: black("c"), Result: result("0-1")},
+			{Round: 2, WhitePlayerID: "d"},
+			{Round: 3, WhitePlayerID: "a", BlackPlayerID: black("e"), Result: result("1-0")},
+			{Round: 3, WhitePlayerID: "c", BlackPlayerID: black("d"), Result: result("1/2-1/2")},
+			{Round: 3, WhitePlayerID: "b"},
+		},
+	}
+
+	// Points: Alice 2½, Bob 2½ with a bye, Carol 1½, Dave 1½ with a bye,
+	// Erin 1 from a bye. Alice met Carol, Bob and Erin for a Buchholz of 5
+	// and a Median-Buchholz of 1½; Bob only met Dave and Alice, so nothing
+	// is left out of his.
+	want := strings.Join([]string{
+		"#  Player  Games  Pts  Buch   M-Buch",
+		"1  Alice   3      2½   5      1½",
+		"2  Bob     2      2½   4      4",
+		"3  Carol   3      1½   5      1½",
+		"4  Dave    2      1½   4      4",
+		"5  Erin    2      1    4      4",
+	}, "\n") + "\n"
+	if got := state.table(); got != want {
+		t.Errorf("Unexpected standings:\n%s\nwant:\n%s", got, want)
+	}
+}
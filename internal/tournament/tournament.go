@@ -0,0 +1,437 @@
+package tournament
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	FormatRoundRobin       = "round_robin"
+	FormatDoubleRoundRobin = "double_round_robin"
+	FormatSwiss            = "swiss"
+)
+
+var formats = []string{FormatRoundRobin, FormatDoubleRoundRobin, FormatSwiss}
+
+const (
+	minPlayers    = 3
+	maxPlayers    = 16
+	maxNameLength = 20
+)
+
+func formatName(format string) string {
+	switch format {
+	case FormatRoundRobin:
+		return "Round robin"
+	case FormatDoubleRoundRobin:
+		return "Double round robin"
+	case FormatSwiss:
+		return "Swiss"
+	}
+	return format
+}
+
+// playerLimit returns the most players a tournament format allows.
+func playerLimit(format string) int {
+	if format == FormatSwiss {
+		return maxSwissPlayers
+	}
+	return maxPlayers
+}
+
+// tournamentState is a tournament with its players in seed order and its
+// pairings in round order.
+type tournamentState struct {
+	tournament database.Tournament
+	players    []database.TournamentPlayer
+	pairings   []database.TournamentPairing
+}
+
+func (s *tournamentState) started() bool {
+	return s.tournament.StartedAt.Valid
+}
+
+// pairedRounds returns the number of rounds with pairings. A Swiss round
+// is paired only once the previous one is over.
+func (s *tournamentState) pairedRounds() int64 {
+	var rounds int64
+	for _, pairing := range s.pairings {
+		rounds = max(rounds, pairing.Round)
+	}
+	return rounds
+}
+
+// roundFinished reports whether every game of a round has a result.
+func (s *tournamentState) roundFinished(round int64) bool {
+	for _, pairing := range s.pairings {
+		if pairing.Round == round && pairing.BlackPlayerID.Valid && !pairing.Result.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// finished reports whether every round of a started tournament is paired
+// and every game has a result.
+func (s *tournamentState) finished() bool {
+	if !s.started() || s.pairedRounds() < s.tournament.Rounds {
+		return false
+	}
+	for _, pairing := range s.pairings {
+		if pairing.BlackPlayerID.Valid && !pairing.Result.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// progress returns the number of finished and scheduled games. Swiss
+// rounds still to be paired count with one game per two players.
+func (s *tournamentState) progress() (int, int) {
+	played, total := 0, 0
+	for _, pairing := range s.pairings {
+		if !pairing.BlackPlayerID.Valid {
+			continue
+		}
+		total++
+		if pairing.Result.Valid {
+			played++
+		}
+	}
+	if s.tournament.Format == FormatSwiss {
+		total += int(s.tournament.Rounds-s.pairedRounds()) * (len(s.players) / 2)
+	}
+	return played, total
+}
+
+func (s *tournamentState) player(id string) (database.TournamentPlayer, bool) {
+	for _, player := range s.players {
+		if player.ID == id {
+			return player, true
+		}
+	}
+	return database.TournamentPlayer{}, false
+}
+
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return fmt.Errorf("name cannot be longer than %d characters", maxNameLength)
+	}
+	return nil
+}
+
+// createTournament creates a tournament without players. rounds is the
+// length of a Swiss tournament; a round robin gets its rounds at the start.
+func createTournament(ctx *app.Context, name, format string, rounds int64, now time.Time) (database.Tournament, error) {
+	if ctx == nil || ctx.Store == nil {
+		return database.Tournament{}, fmt.Errorf("context or Store is nil")
+	}
+
+	name = strings.TrimSpace(name)
+	err := checkName(name)
+	if err != nil {
+		return database.Tournament{}, err
+	}
+	if !slices.Contains(formats, format) {
+		return database.Tournament{}, fmt.Errorf("unknown tournament format %q", format)
+	}
+	if format != FormatSwiss {
+		rounds = 0
+	} else if rounds < minSwissRounds || rounds > maxSwissRounds {
+		return database.Tournament{}, fmt.Errorf("a Swiss tournament has %d to %d rounds", minSwissRounds, maxSwissRounds)
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return database.Tournament{}, fmt.Errorf("failed to generate tournament ID: %w", err)
+	}
+
+	tournament, err := ctx.Store.CreateTournament(context.Background(), database.CreateTournamentParams{
+		ID:        id.String(),
+		Name:      name,
+		Format:    format,
+		Rounds:    rounds,
+		CreatedAt: now.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return database.Tournament{}, fmt.Errorf("failed to create tournament: %w", err)
+	}
+
+	return tournament, nil
+}
+
+func loadTournament(ctx *app.Context, id string) (*tournamentState, error) {
+	if ctx == nil || ctx.Store == nil {
+		return nil, fmt.Errorf("context or Store is nil")
+	}
+
+	tournament, err := ctx.Store.GetTournament(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+	players, err := ctx.Store.ListTournamentPlayers(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament players: %w", err)
+	}
+	pairings, err := ctx.Store.ListTournamentPairings(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament pairings: %w", err)
+	}
+
+	return &tournamentState{
+		tournament: tournament,
+		players:    players,
+		pairings:   pairings,
+	}, nil
+}
+
+// addPlayer enters a registered player, or a guest called name when user
+// is nil, into a tournament that has not started yet.
+func addPlayer(ctx *app.Context, tournamentID string, user *app.User, name string) error {
+	state, err := loadTournament(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	if state.started() {
+		return fmt.Errorf("tournament has already started")
+	}
+	if limit := playerLimit(state.tournament.Format); len(state.players) >= limit {
+		return fmt.Errorf("a tournament cannot have more than %d players", limit)
+	}
+
+	var userID sql.NullString
+	if user != nil {
+		name = user.Username
+		userID = sql.NullString{String: user.ID, Valid: true}
+	}
+	name = strings.TrimSpace(name)
+	err = checkName(name)
+	if err != nil {
+		return err
+	}
+
+	var seed int64 = 1
+	for _, player := range state.players {
+		if strings.EqualFold(player.Name, name) || (userID.Valid && player.UserID == userID) {
+			return fmt.Errorf("%s is already in the tournament", name)
+		}
+		seed = max(seed, player.Seed+1)
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate player ID: %w", err)
+	}
+
+	_, err = ctx.Store.AddTournamentPlayer(context.Background(), database.AddTournamentPlayerParams{
+		ID:           id.String(),
+		TournamentID: tournamentID,
+		UserID:       userID,
+		Name:         name,
+		Seed:         seed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add player: %w", err)
+	}
+
+	return nil
+}
+
+func removePlayer(ctx *app.Context, tournamentID, playerID string) error {
+	state, err := loadTournament(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	if state.started() {
+		return fmt.Errorf("tournament has already started")
+	}
+
+	err = ctx.Store.DeleteTournamentPlayer(context.Background(), playerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove player: %w", err)
+	}
+
+	return nil
+}
+
+// startTournament draws up the whole schedule of a round robin from the
+// Berger tables, with the players numbered in the order they were added.
+// A Swiss tournament gets its first round only.
+func startTournament(ctx *app.Context, tournamentID string, now time.Time) error {
+	state, err := loadTournament(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	if state.started() {
+		return fmt.Errorf("tournament has already started")
+	}
+	if len(state.players) < minPlayers {
+		return fmt.Errorf("a tournament needs at least %d players", minPlayers)
+	}
+
+	var rounds [][]pair
+	total := state.tournament.Rounds
+	switch state.tournament.Format {
+	case FormatSwiss:
+		if int64(len(state.players)) <= total {
+			return fmt.Errorf("a Swiss tournament of %d rounds needs at least %d players", total, total+1)
+		}
+		first, err := state.swissRound()
+		if err != nil {
+			return err
+		}
+		rounds = [][]pair{first}
+	case FormatDoubleRoundRobin:
+		rounds = doubleRounds(bergerRounds(len(state.players)))
+		total = int64(len(rounds))
+	default:
+		rounds = bergerRounds(len(state.players))
+		total = int64(len(rounds))
+	}
+
+	return ctx.Store.WithTx(context.Background(), func(store storage.Store) error {
+		for i, round := range rounds {
+			err := createPairings(store, state, int64(i+1), round)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := store.StartTournament(context.Background(), database.StartTournamentParams{
+			Rounds:    total,
+			StartedAt: sql.NullString{String: now.Format(time.RFC3339), Valid: true},
+			ID:        tournamentID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start tournament: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// pairNextRound pairs the next Swiss round once every game of the last one
+// has a result, so results flow in from finished games. It does nothing for
+// other tournaments or while games are still to be played.
+func pairNextRound(ctx *app.Context, tournamentID string) error {
+	state, err := loadTournament(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	if state.tournament.Format != FormatSwiss || !state.started() {
+		return nil
+	}
+	round := state.pairedRounds()
+	if round >= state.tournament.Rounds || !state.roundFinished(round) {
+		return nil
+	}
+
+	pairs, err := state.swissRound()
+	if err != nil {
+		return err
+	}
+
+	return ctx.Store.WithTx(context.Background(), func(store storage.Store) error {
+		return createPairings(store, state, round+1, pairs)
+	})
+}
+
+// createPairings stores the pairs of a round, byes after the games.
+func createPairings(store storage.Tournaments, state *tournamentState, round int64, pairs []pair) error {
+	var games, byes []pair
+	for _, p := range pairs {
+		if p.black == bye {
+			byes = append(byes, p)
+		} else {
+			games = append(games, p)
+		}
+	}
+
+	for board, p := range append(games, byes...) {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return fmt.Errorf("failed to generate pairing ID: %w", err)
+		}
+
+		params := database.CreateTournamentPairingParams{
+			ID:            id.String(),
+			TournamentID:  state.tournament.ID,
+			Round:         round,
+			Board:         int64(board + 1),
+			WhitePlayerID: state.players[p.white].ID,
+		}
+		if p.black != bye {
+			params.BlackPlayerID = sql.NullString{String: state.players[p.black].ID, Valid: true}
+		}
+
+		_, err = store.CreateTournamentPairing(context.Background(), params)
+		if err != nil {
+			return fmt.Errorf("failed to create pairing: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func deleteTournament(ctx *app.Context, tournamentID string) error {
+	if ctx == nil || ctx.Store == nil {
+		return fmt.Errorf("context or Store is nil")
+	}
+
+	err := ctx.Store.DeleteTournament(context.Background(), tournamentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tournament: %w", err)
+	}
+
+	return nil
+}
+
+func pairingUser(player database.TournamentPlayer) *app.User {
+	if !player.UserID.Valid {
+		return nil
+	}
+	return &app.User{ID: player.UserID.String, Username: player.Name}
+}
+
+// gamePairing returns the link between the game of a pairing and the
+// tournament, which seats the pairing's players.
+func (s *tournamentState) gamePairing(pairing database.TournamentPairing) (*app.Pairing, error) {
+	if !pairing.BlackPlayerID.Valid {
+		return nil, fmt.Errorf("a bye is not played")
+	}
+	if pairing.Result.Valid {
+		return nil, fmt.Errorf("game has already been played")
+	}
+
+	white, ok := s.player(pairing.WhitePlayerID)
+	if !ok {
+		return nil, fmt.Errorf("white player not found")
+	}
+	black, ok := s.player(pairing.BlackPlayerID.String)
+	if !ok {
+		return nil, fmt.Errorf("black player not found")
+	}
+
+	return &app.Pairing{
+		ID:           pairing.ID,
+		TournamentID: s.tournament.ID,
+		White:        pairingUser(white),
+		Black:        pairingUser(black),
+		WhiteName:    white.Name,
+		BlackName:    black.Name,
+		Title:        fmt.Sprintf("%s | Round %d, board %d", s.tournament.Name, pairing.Round, pairing.Board),
+	}, nil
+}
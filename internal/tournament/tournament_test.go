@@ -0,0 +1,324 @@
+package tournament
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deskdaniel/GoMate/internal/app"
+	"github.com/deskdaniel/GoMate/internal/database"
+	"github.com/deskdaniel/GoMate/internal/results"
+	"github.com/deskdaniel/GoMate/internal/storage"
+	"github.com/deskdaniel/GoMate/internal/storage/storagetest"
+)
+
+func TestCreateTournament(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+
+	tests := []struct {
+		name       string
+		format     string
+		rounds     int64
+		wantRounds int64
+		wantErr    bool
+	}{
+		{"Club Championship", FormatRoundRobin, 5, 0, false},
+		{"  Spring Cup  ", FormatDoubleRoundRobin, 0, 0, false},
+		{"Club Night", FormatSwiss, 5, 5, false},
+		{"", FormatRoundRobin, 0, 0, true},
+		{strings.Repeat("x", maxNameLength+1), FormatRoundRobin, 0, 0, true},
+		{"Open", "knockout", 0, 0, true},
+		{"Blitz", FormatSwiss, minSwissRounds - 1, 0, true},
+		{"Blitz", FormatSwiss, maxSwissRounds + 1, 0, true},
+	}
+	for _, test := range tests {
+		tournament, err := createTournament(ctx, test.name, test.format, test.rounds, time.Now())
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Expected creating %q (%s) to fail", test.name, test.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("createTournament(%q) failed: %v", test.name, err)
+			continue
+		}
+		if tournament.Name != strings.TrimSpace(test.name) || tournament.Rounds != test.wantRounds || tournament.StartedAt.Valid {
+			t.Errorf("Unexpected tournament %+v", tournament)
+		}
+	}
+}
+
+func TestTournamentEntries(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+	alice := storagetest.RegisterUser(t, ctx.Store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, ctx.Store, "Bob-id", "Bob")
+
+	tournament, err := createTournament(ctx, "Club Championship", FormatRoundRobin, 0, time.Now())
+	if err != nil {
+		t.Fatalf("createTournament failed: %v", err)
+	}
+	id := tournament.ID
+
+	for _, entry := range []struct {
+		user *app.User
+		name string
+	}{{alice, ""}, {bob, ""}, {nil, "Guest"}} {
+		err = addPlayer(ctx, id, entry.user, entry.name)
+		if err != nil {
+			t.Fatalf("addPlayer failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		user  *app.User
+		guest string
+	}{
+		{"registered player twice", alice, ""},
+		{"guest with a taken name", nil, "bob"},
+		{"guest without a name", nil, "  "},
+	}
+	for _, test := range tests {
+		err = addPlayer(ctx, id, test.user, test.guest)
+		if err == nil {
+			t.Errorf("Expected adding a %s to fail", test.name)
+		}
+	}
+
+	err = startTournament(ctx, id, time.Now())
+	if err != nil {
+		t.Fatalf("startTournament failed: %v", err)
+	}
+	err = addPlayer(ctx, id, nil, "Late")
+	if err == nil {
+		t.Error("Expected adding a player after the start to fail")
+	}
+
+	state, err := loadTournament(ctx, id)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	err = removePlayer(ctx, id, state.players[2].ID)
+	if err == nil {
+		t.Error("Expected removing a player after the start to fail")
+	}
+	err = startTournament(ctx, id, time.Now())
+	if err == nil {
+		t.Error("Expected starting twice to fail")
+	}
+}
+
+func TestStartTournament(t *testing.T) {
+	tests := []struct {
+		format  string
+		players int
+		rounds  int64
+		games   int
+		byes    int
+	}{
+		{FormatRoundRobin, 4, 3, 6, 0},
+		{FormatRoundRobin, 5, 5, 10, 5},
+		{FormatDoubleRoundRobin, 3, 6, 6, 6},
+	}
+
+	for _, test := range tests {
+		ctx := &app.Context{Store: storage.NewMemory()}
+		tournament, err := createTournament(ctx, "Cup", test.format, 0, time.Now())
+		if err != nil {
+			t.Fatalf("createTournament failed: %v", err)
+		}
+
+		err = addPlayer(ctx, tournament.ID, nil, "Player 1")
+		if err != nil {
+			t.Fatalf("addPlayer failed: %v", err)
+		}
+		err = startTournament(ctx, tournament.ID, time.Now())
+		if err == nil {
+			t.Error("Expected starting with too few players to fail")
+		}
+		for i := 2; i <= test.players; i++ {
+			err = addPlayer(ctx, tournament.ID, nil, fmt.Sprintf("Player %d", i))
+			if err != nil {
+				t.Fatalf("addPlayer failed: %v", err)
+			}
+		}
+
+		err = startTournament(ctx, tournament.ID, time.Now())
+		if err != nil {
+			t.Fatalf("startTournament failed: %v", err)
+		}
+		state, err := loadTournament(ctx, tournament.ID)
+		if err != nil {
+			t.Fatalf("loadTournament failed: %v", err)
+		}
+
+		byes := 0
+		for _, pairing := range state.pairings {
+			if !pairing.BlackPlayerID.Valid {
+				byes++
+			}
+		}
+		_, games := state.progress()
+		if state.tournament.Rounds != test.rounds || games != test.games || byes != test.byes {
+			t.Errorf("%s with %d players: expected %d rounds, %d games and %d byes, got %d, %d and %d",
+				test.format, test.players, test.rounds, test.games, test.byes, state.tournament.Rounds, games, byes)
+		}
+		if state.finished() {
+			t.Error("Expected a new tournament not to be finished")
+		}
+	}
+}
+
+func TestPlayTournament(t *testing.T) {
+	ctx := &app.Context{Store: storage.NewMemory()}
+	alice := storagetest.RegisterUser(t, ctx.Store, "Alice-id", "Alice")
+	bob := storagetest.RegisterUser(t, ctx.Store, "Bob-id", "Bob")
+
+	tournament, err := createTournament(ctx, "Cup", FormatRoundRobin, 0, time.Now())
+	if err != nil {
+		t.Fatalf("createTournament failed: %v", err)
+	}
+	for _, user := range []*app.User{alice, bob, nil} {
+		err = addPlayer(ctx, tournament.ID, user, "Carol")
+		if err != nil {
+			t.Fatalf("addPlayer failed: %v", err)
+		}
+	}
+	err = startTournament(ctx, tournament.ID, time.Now())
+	if err != nil {
+		t.Fatalf("startTournament failed: %v", err)
+	}
+
+	state, err := loadTournament(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	outcomes := []string{results.WhiteWins, results.BlackWins, results.Draw}
+	played := 0
+	for _, pairing := range state.pairings {
+		if !pairing.BlackPlayerID.Valid {
+			_, err = state.gamePairing(pairing)
+			if err == nil {
+				t.Error("Expected a bye not to be playable")
+			}
+			continue
+		}
+
+		ctx.Pairing, err = state.gamePairing(pairing)
+		if err != nil {
+			t.Fatalf("gamePairing failed: %v", err)
+		}
+		game := database.CreateGameParams{
+			ID:          fmt.Sprintf("game-%d", played),
+			WhiteUserID: sql.NullString{String: userID(ctx.Pairing.White), Valid: ctx.Pairing.White != nil},
+			BlackUserID: sql.NullString{String: userID(ctx.Pairing.Black), Valid: ctx.Pairing.Black != nil},
+			WhiteName:   ctx.Pairing.WhiteName,
+			BlackName:   ctx.Pairing.BlackName,
+			Result:      outcomes[played],
+			Termination: "checkmate",
+			FinalFen:    "fen",
+		}
+		err = results.Record(ctx, game)
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		game.ID += "-again"
+		err = results.Record(ctx, game)
+		if err == nil {
+			t.Error("Expected recording a second result for the pairing to fail")
+		}
+		played++
+	}
+	ctx.Pairing = nil
+
+	state, err = loadTournament(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("loadTournament failed: %v", err)
+	}
+	if !state.finished() {
+		t.Fatal("Expected the tournament to be finished")
+	}
+	for _, pairing := range state.pairings {
+		if pairing.BlackPlayerID.Valid && (!pairing.GameID.Valid || !pairing.Result.Valid) {
+			t.Errorf("Expected every game to be linked to its pairing, got %+v", pairing)
+		}
+		if pairing.BlackPlayerID.Valid {
+			_, err = state.gamePairing(pairing)
+			if err == nil {
+				t.Error("Expected a finished game not to be playable again")
+			}
+		}
+	}
+
+	// Round 1: Bob-Carol 1-0, round 2: Alice-Bob 0-1, round 3: Carol-Alice ½-½.
+	table := state.standings()
+	want := []struct {
+		name            string
+		points          float64
+		sonnebornBerger float64
+	}{
+		{"Bob", 2, 1},
+		{"Alice", 0.5, 0.25},
+		{"Carol", 0.5, 0.25},
+	}
+	for i, st := range table {
+		if st.player.Name != want[i].name || st.points != want[i].points || st.sonnebornBerger != want[i].sonnebornBerger {
+			t.Errorf("Rank %d: expected %+v, got %s with %v points and %v SB", i+1, want[i], st.player.Name, st.points, st.sonnebornBerger)
+		}
+	}
+
+	record, err := ctx.Store.GetRecordsByUserID(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetRecordsByUserID failed: %v", err)
+	}
+	if record.Wins.Int64 != 2 {
+		t.Errorf("Expected tournament games to count in Bob's record, got %+v", record)
+	}
+}
+
+func userID(user *app.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
+
+func TestCrosstable(t *testing.T) {
+	result := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: s != ""}
+	}
+	black := func(id string) sql.NullString {
+		return sql.NullString{String: id, Valid: true}
+	}
+
+	state := &tournamentState{
+		tournament: database.Tournament{Format: FormatDoubleRoundRobin, Rounds: 2},
+		players: []database.TournamentPlayer{
+			{ID: "a", Name: "Alice", Seed: 1},
+			{ID: "b", Name: "Bob", Seed: 2},
+			{ID: "c", Name: "Carol", Seed: 3},
+		},
+		pairings: []database.TournamentPairing{
+			{Round: 1, WhitePlayerID: "a", BlackPlayerID: black("b"), Result: result("1/2-1/2")},
+			{Round: 1, WhitePlayerID: "c", BlackPlayerID: black("a"), Result: result("0-1")},
+			{Round: 2, WhitePlayerID: "b", BlackPlayerID: black("a"), Result: result("1-0")},
+			{Round: 2, WhitePlayerID: "b", BlackPlayerID: black("c")},
+		},
+	}
+
+	// Alice and Bob both have 1½ points; Alice's win over Carol counts for
+	// nothing, while Bob's draw and win against Alice count for 2¼.
+	want := strings.Join([]string{
+		"#  Player  1  2  3  Pts  SB",
+		"1  Bob     XX ½1 .. 1½   2¼",
+		"2  Alice   ½0 XX 1. 1½   ¾",
+		"3  Carol   .. 0. XX 0    0",
+	}, "\n") + "\n"
+	if got := state.crosstable(); got != want {
+		t.Errorf("Unexpected crosstable:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -1,34 +1,127 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
 	"os"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/deskdaniel/GoMate/internal/app"
-
+	"github.com/deskdaniel/GoMate/internal/config"
 	"github.com/deskdaniel/GoMate/internal/database"
-	"github.com/deskdaniel/GoMate/internal/navigation"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// globalOptions holds the flags given before the command.
+type globalOptions struct {
+	dbFlag       string
+	configFlag   string
+	logFlag      string
+	migrateLocal bool
+}
+
 func main() {
-	db, err := database.OpenDb()
+	var opts globalOptions
+	flag.StringVar(&opts.dbFlag, "db", "", "path to the database file (default $GOMATE_DB or $XDG_DATA_HOME/gomate/chess.db)")
+	flag.StringVar(&opts.configFlag, "config", "", "path to the config file (default $XDG_CONFIG_HOME/gomate/config.json)")
+	flag.StringVar(&opts.logFlag, "log", "", "path to the log file (default gomate.log next to the database for play, stderr for the other commands)")
+	flag.BoolVar(&opts.migrateLocal, "migrate-local-db", false, "copy chess.db from the current directory to the database location before starting")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	name := "play"
+	var args []string
+	if flag.NArg() > 0 {
+		name = flag.Arg(0)
+		args = flag.Args()[1:]
+	}
+	if name == "help" {
+		flag.CommandLine.SetOutput(os.Stdout)
+		printUsage()
+		return
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q.\n\n", name)
+		printUsage()
+		os.Exit(2)
+	}
+
+	err := runCommand(opts, cmd, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
 	if err != nil {
-		fmt.Printf("Error initializing database: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	defer db.Close()
-	queries := database.New(db)
-	ctx := &app.Context{
-		Queries: queries,
+}
+
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: gomate [global flags] [command] [arguments]\n\n")
+	fmt.Fprintf(out, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nWithout a command the game starts. Run gomate <command> -h for the arguments of a command.\n\n")
+	fmt.Fprintf(out, "Global flags:\n")
+	flag.PrintDefaults()
+}
+
+// openDb resolves the database location, handling a chess.db left in the
+// current directory by older versions, and opens it. It returns the path
+// it opened.
+func (o globalOptions) openDb() (*sql.DB, string, error) {
+	dbPath, err := database.ResolvePath(o.dbFlag)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to locate database: %w", err)
+	}
+
+	if o.migrateLocal {
+		err = database.CopyLegacyDb(dbPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to migrate local database: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Copied %s to %s\n", database.LegacyPath, dbPath)
+	} else {
+		legacy, err := database.HasLegacyDb(dbPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to locate database: %w", err)
+		}
+		if legacy {
+			return nil, "", fmt.Errorf("found %s in the current directory, but GoMate now stores its database in %s.\n"+
+				"Run with --migrate-local-db to copy it there, or with --db chess.db to keep using it in place", database.LegacyPath, dbPath)
+		}
+	}
+
+	db, err := database.OpenDb(dbPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	m := navigation.SetupNavigation(ctx)
+	return db, dbPath, nil
+}
 
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
-		log.Fatal(err)
+func (o globalOptions) loadConfig() (config.Config, error) {
+	configPath := o.configFlag
+	if configPath != "" {
+		// Unlike the default location, a config given explicitly must exist.
+		_, err := os.Stat(configPath)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("failed to read config: %w", err)
+		}
+	} else {
+		var err error
+		configPath, err = config.DefaultPath()
+		if err != nil {
+			return config.Config{}, fmt.Errorf("failed to locate config: %w", err)
+		}
 	}
+
+	return config.Load(configPath)
 }
@@ -0,0 +1,18 @@
+-- name: UnlockAchievement :execrows
+INSERT INTO achievements (user_id, achievement_id, game_id, unlocked_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (user_id, achievement_id) DO NOTHING;
+
+-- name: ListAchievementsByUserID :many
+SELECT * FROM achievements
+WHERE user_id = ?
+ORDER BY unlocked_at, achievement_id;
+
+-- name: ListAchievements :many
+SELECT * FROM achievements
+ORDER BY user_id, unlocked_at, achievement_id;
\ No newline at end of file
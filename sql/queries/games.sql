@@ -0,0 +1,57 @@
+-- name: CreateGame :one
+INSERT INTO games (id, white_user_id, black_user_id, white_name, black_name, result, termination, started_at, ended_at, moves, final_fen, time_control)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: GetGame :one
+SELECT * FROM games
+WHERE id = ?;
+
+-- name: ListGamesBetweenUsers :many
+SELECT * FROM games
+WHERE (white_user_id = sqlc.arg(user_id) AND black_user_id = sqlc.arg(opponent_id))
+    OR (white_user_id = sqlc.arg(opponent_id) AND black_user_id = sqlc.arg(user_id))
+ORDER BY ended_at DESC, rowid DESC;
+
+-- name: ListGamesByUserID :many
+SELECT * FROM games
+WHERE white_user_id = sqlc.arg(user_id) OR black_user_id = sqlc.arg(user_id)
+ORDER BY ended_at DESC, rowid DESC;
+
+-- name: ListGames :many
+SELECT * FROM games
+ORDER BY ended_at, id;
+
+-- name: ImportGame :execrows
+INSERT INTO games (id, white_user_id, black_user_id, white_name, black_name, result, termination, started_at, ended_at, moves, final_fen, time_control)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: DeleteGames :exec
+DELETE FROM games;
\ No newline at end of file
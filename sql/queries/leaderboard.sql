@@ -0,0 +1,26 @@
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
+
+-- name: ListLeaderboard :many
+SELECT
+    users.id,
+    users.username,
+    COALESCE(records.wins, 0) AS wins,
+    COALESCE(records.losses, 0) AS losses,
+    COALESCE(records.draws, 0) AS draws,
+    COALESCE(records.rating, 1500) AS rating,
+    COALESCE(records.glicko_rating, 1500.0) AS glicko_rating
+FROM users
+LEFT JOIN records ON records.user_id = users.id
+ORDER BY
+    CASE sqlc.arg(sort_by)
+        WHEN 'rating' THEN COALESCE(records.rating, 1500)
+        WHEN 'glicko_rating' THEN COALESCE(records.glicko_rating, 1500.0)
+        WHEN 'win_percentage' THEN CAST(COALESCE(records.wins, 0) AS REAL) / MAX(COALESCE(records.wins, 0) + COALESCE(records.losses, 0) + COALESCE(records.draws, 0), 1)
+        WHEN 'games' THEN COALESCE(records.wins, 0) + COALESCE(records.losses, 0) + COALESCE(records.draws, 0)
+        WHEN 'wins' THEN COALESCE(records.wins, 0)
+        WHEN 'losses' THEN COALESCE(records.losses, 0)
+        WHEN 'draws' THEN COALESCE(records.draws, 0)
+    END DESC,
+    users.username ASC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
\ No newline at end of file
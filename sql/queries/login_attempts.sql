@@ -0,0 +1,21 @@
+-- name: GetLoginAttempt :one
+SELECT * FROM login_attempts
+WHERE username = ?;
+
+-- name: SaveLoginAttempt :one
+INSERT INTO login_attempts (username, failed_attempts, last_failed_at, locked_until)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (username) DO UPDATE SET
+    failed_attempts = excluded.failed_attempts,
+    last_failed_at = excluded.last_failed_at,
+    locked_until = excluded.locked_until
+RETURNING *;
+
+-- name: ClearLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE username = ?;
\ No newline at end of file
@@ -0,0 +1,59 @@
+-- name: CreateMatch :one
+INSERT INTO matches (id, player1_user_id, player2_user_id, player1_name, player2_name, games, armageddon, created_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: GetMatch :one
+SELECT * FROM matches
+WHERE id = ?;
+
+-- name: ListMatches :many
+SELECT * FROM matches
+ORDER BY created_at DESC, id;
+
+-- name: FinishMatch :exec
+UPDATE matches
+SET finished_at = ?
+WHERE id = ?;
+
+-- name: DeleteMatch :exec
+DELETE FROM matches
+WHERE id = ?;
+
+-- name: DeleteMatches :exec
+DELETE FROM matches;
+
+-- name: CreateMatchGame :one
+INSERT INTO match_games (id, match_id, number, player1_white, armageddon)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: ListMatchGames :many
+SELECT * FROM match_games
+WHERE match_id = ?
+ORDER BY number;
+
+-- name: SetMatchGameID :exec
+UPDATE match_games
+SET game_id = ?
+WHERE id = ?;
+
+-- name: SetMatchGameResult :execrows
+UPDATE match_games
+SET result = ?, game_id = ?
+WHERE id = ? AND result IS NULL;
\ No newline at end of file
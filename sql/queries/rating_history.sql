@@ -0,0 +1,40 @@
+-- name: CreateRatingHistory :one
+INSERT INTO rating_history (id, user_id, game_id, created_at, rating_before, rating_after)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: ListRatingHistoryByUserID :many
+SELECT * FROM rating_history
+WHERE user_id = ?
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?;
+
+-- name: ListRatingHistory :many
+SELECT * FROM rating_history
+ORDER BY created_at, rowid;
+
+-- name: ImportRatingHistory :execrows
+INSERT INTO rating_history (id, user_id, game_id, created_at, rating_before, rating_after)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: DeleteRatingHistoryByUserID :exec
+DELETE FROM rating_history
+WHERE user_id = ?;
+
+-- name: ResetRatingHistory :exec
+DELETE FROM rating_history;
\ No newline at end of file
@@ -26,4 +26,80 @@ WHERE user_id = ?
 RETURNING *;
 
 -- name: ResetRecords :exec
-DELETE FROM records;
\ No newline at end of file
+DELETE FROM records;
+
+-- name: UpdateRating :one
+UPDATE records
+SET
+    updated_at = ?,
+    rating = ?,
+    rated_games = rated_games + 1
+WHERE user_id = ?
+RETURNING *;
+
+-- name: UpdateGlicko2 :one
+UPDATE records
+SET
+    updated_at = ?,
+    glicko_rating = ?,
+    glicko_deviation = ?,
+    glicko_volatility = ?,
+    glicko_rated_at = ?
+WHERE user_id = ?
+RETURNING *;
+
+-- name: AddResult :one
+INSERT INTO records (id, user_id, created_at, updated_at, wins, losses, draws)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    updated_at = excluded.updated_at,
+    wins = COALESCE(records.wins, 0) + excluded.wins,
+    losses = COALESCE(records.losses, 0) + excluded.losses,
+    draws = COALESCE(records.draws, 0) + excluded.draws
+RETURNING *;
+
+-- name: ListRecords :many
+SELECT * FROM records
+ORDER BY user_id;
+
+-- name: ImportRecord :execrows
+INSERT INTO records (id, user_id, created_at, updated_at, wins, losses, draws, rating, rated_games, glicko_rating, glicko_deviation, glicko_volatility, glicko_rated_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    updated_at = excluded.updated_at,
+    wins = excluded.wins,
+    losses = excluded.losses,
+    draws = excluded.draws,
+    rating = excluded.rating,
+    rated_games = excluded.rated_games,
+    glicko_rating = excluded.glicko_rating,
+    glicko_deviation = excluded.glicko_deviation,
+    glicko_volatility = excluded.glicko_volatility,
+    glicko_rated_at = excluded.glicko_rated_at
+WHERE COALESCE(excluded.updated_at, '') > COALESCE(records.updated_at, '');
+
+-- name: DeleteRecordsByUserID :exec
+DELETE FROM records
+WHERE user_id = ?;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- name: GetSavedGame :one
+SELECT * FROM saved_games
+WHERE id = ?;
+
+-- name: GetLatestSavedGame :one
+SELECT * FROM saved_games
+WHERE white_user_id IS sqlc.narg(white_user_id) AND black_user_id IS sqlc.narg(black_user_id)
+    AND id NOT IN (SELECT game_id FROM tournament_pairings WHERE game_id IS NOT NULL)
+    AND id NOT IN (SELECT game_id FROM match_games WHERE game_id IS NOT NULL)
+ORDER BY updated_at DESC
+LIMIT 1;
+
+-- name: SaveGame :one
+INSERT INTO saved_games (id, white_user_id, black_user_id, created_at, updated_at, fen, moves, offered_draw)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT (id) DO UPDATE SET
+    updated_at = excluded.updated_at,
+    fen = excluded.fen,
+    moves = excluded.moves,
+    offered_draw = excluded.offered_draw
+RETURNING *;
+
+-- name: DeleteSavedGame :exec
+DELETE FROM saved_games
+WHERE id = ?;
+
+-- name: DeleteSavedGames :exec
+DELETE FROM saved_games;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- name: CreateSession :one
+INSERT INTO sessions (token_hash, user_id, created_at, expires_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: GetSessionUser :one
+SELECT users.id, users.username, sessions.expires_at FROM sessions
+JOIN users ON users.id = sessions.user_id
+WHERE sessions.token_hash = ?;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = ?;
+
+-- name: DeleteSessionsByUserID :exec
+DELETE FROM sessions
+WHERE user_id = ?;
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions
+WHERE expires_at <= ?;
\ No newline at end of file
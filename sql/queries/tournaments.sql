@@ -0,0 +1,78 @@
+-- name: CreateTournament :one
+INSERT INTO tournaments (id, name, format, rounds, created_at)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: GetTournament :one
+SELECT * FROM tournaments
+WHERE id = ?;
+
+-- name: ListTournaments :many
+SELECT * FROM tournaments
+ORDER BY created_at DESC, id;
+
+-- name: StartTournament :one
+UPDATE tournaments
+SET rounds = ?, started_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteTournament :exec
+DELETE FROM tournaments
+WHERE id = ?;
+
+-- name: DeleteTournaments :exec
+DELETE FROM tournaments;
+
+-- name: AddTournamentPlayer :one
+INSERT INTO tournament_players (id, tournament_id, user_id, name, seed)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: ListTournamentPlayers :many
+SELECT * FROM tournament_players
+WHERE tournament_id = ?
+ORDER BY seed;
+
+-- name: DeleteTournamentPlayer :exec
+DELETE FROM tournament_players
+WHERE id = ?;
+
+-- name: CreateTournamentPairing :one
+INSERT INTO tournament_pairings (id, tournament_id, round, board, white_player_id, black_player_id)
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+RETURNING *;
+
+-- name: ListTournamentPairings :many
+SELECT * FROM tournament_pairings
+WHERE tournament_id = ?
+ORDER BY round, board;
+
+-- name: SetTournamentPairingGame :exec
+UPDATE tournament_pairings
+SET game_id = ?
+WHERE id = ?;
+
+-- name: SetTournamentPairingResult :execrows
+UPDATE tournament_pairings
+SET result = ?, game_id = ?
+WHERE id = ? AND result IS NULL;
\ No newline at end of file
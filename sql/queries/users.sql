@@ -17,4 +17,28 @@ RETURNING *;
 
 -- name: ResetUsers :exec
 DELETE FROM users;
+--
+
+-- name: UpdatePassword :one
+UPDATE users
+SET hashed_password = ?, updated_at = ?
+WHERE id = ?
+RETURNING *;
+--
+
+-- name: UpdateUsername :one
+UPDATE users
+SET username = ?, updated_at = ?
+WHERE id = ?
+RETURNING *;
+--
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+--
+
+-- name: ListUsers :many
+SELECT * FROM users
+ORDER BY username;
 --
\ No newline at end of file
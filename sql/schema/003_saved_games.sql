@@ -0,0 +1,14 @@
+-- +goose up
+CREATE TABLE saved_games (
+    id TEXT PRIMARY KEY,
+    white_user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    black_user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    created_at TEXT DEFAULT (datetime('now')),
+    updated_at TEXT DEFAULT (datetime('now')),
+    fen TEXT NOT NULL,
+    moves TEXT NOT NULL DEFAULT '',
+    offered_draw BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- +goose down
+DROP TABLE saved_games;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- +goose up
+CREATE TABLE games (
+    id TEXT PRIMARY KEY,
+    white_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    black_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    white_name TEXT NOT NULL,
+    black_name TEXT NOT NULL,
+    result TEXT NOT NULL CHECK (result IN ('1-0', '0-1', '1/2-1/2')),
+    termination TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    ended_at TEXT NOT NULL,
+    moves TEXT NOT NULL DEFAULT '',
+    final_fen TEXT NOT NULL,
+    time_control TEXT NOT NULL DEFAULT '-'
+);
+
+CREATE INDEX games_white_user_id_idx ON games(white_user_id);
+CREATE INDEX games_black_user_id_idx ON games(black_user_id);
+
+-- +goose down
+DROP TABLE games;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- +goose up
+ALTER TABLE records ADD COLUMN rating INTEGER NOT NULL DEFAULT 1500;
+ALTER TABLE records ADD COLUMN rated_games INTEGER NOT NULL DEFAULT 0 CHECK (rated_games >= 0);
+
+CREATE TABLE rating_history (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    game_id TEXT NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+    created_at TEXT DEFAULT (datetime('now')),
+    rating_before INTEGER NOT NULL,
+    rating_after INTEGER NOT NULL
+);
+
+CREATE INDEX rating_history_user_id_idx ON rating_history(user_id);
+
+-- +goose down
+DROP TABLE rating_history;
+ALTER TABLE records DROP COLUMN rated_games;
+ALTER TABLE records DROP COLUMN rating;
\ No newline at end of file
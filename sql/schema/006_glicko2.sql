@@ -0,0 +1,11 @@
+-- +goose up
+ALTER TABLE records ADD COLUMN glicko_rating REAL NOT NULL DEFAULT 1500;
+ALTER TABLE records ADD COLUMN glicko_deviation REAL NOT NULL DEFAULT 350;
+ALTER TABLE records ADD COLUMN glicko_volatility REAL NOT NULL DEFAULT 0.06;
+ALTER TABLE records ADD COLUMN glicko_rated_at TEXT;
+
+-- +goose down
+ALTER TABLE records DROP COLUMN glicko_rated_at;
+ALTER TABLE records DROP COLUMN glicko_volatility;
+ALTER TABLE records DROP COLUMN glicko_deviation;
+ALTER TABLE records DROP COLUMN glicko_rating;
\ No newline at end of file
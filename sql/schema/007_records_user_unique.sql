@@ -0,0 +1,15 @@
+-- +goose up
+UPDATE records
+SET
+    wins = (SELECT SUM(COALESCE(r.wins, 0)) FROM records r WHERE r.user_id = records.user_id),
+    losses = (SELECT SUM(COALESCE(r.losses, 0)) FROM records r WHERE r.user_id = records.user_id),
+    draws = (SELECT SUM(COALESCE(r.draws, 0)) FROM records r WHERE r.user_id = records.user_id)
+WHERE rowid IN (SELECT MIN(rowid) FROM records GROUP BY user_id HAVING COUNT(*) > 1);
+
+DELETE FROM records
+WHERE rowid NOT IN (SELECT MIN(rowid) FROM records GROUP BY user_id);
+
+CREATE UNIQUE INDEX records_user_id_idx ON records(user_id);
+
+-- +goose down
+DROP INDEX records_user_id_idx;
\ No newline at end of file
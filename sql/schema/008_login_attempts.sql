@@ -0,0 +1,10 @@
+-- +goose up
+CREATE TABLE login_attempts (
+    username TEXT PRIMARY KEY,
+    failed_attempts INTEGER NOT NULL DEFAULT 0 CHECK (failed_attempts >= 0),
+    last_failed_at TEXT NOT NULL,
+    locked_until TEXT
+);
+
+-- +goose down
+DROP TABLE login_attempts;
\ No newline at end of file
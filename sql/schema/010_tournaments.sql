@@ -0,0 +1,37 @@
+-- +goose up
+CREATE TABLE tournaments (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    format TEXT NOT NULL,
+    rounds INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    started_at TEXT
+);
+
+CREATE TABLE tournament_players (
+    id TEXT PRIMARY KEY,
+    tournament_id TEXT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    name TEXT NOT NULL,
+    seed INTEGER NOT NULL,
+    UNIQUE (tournament_id, name)
+);
+
+CREATE TABLE tournament_pairings (
+    id TEXT PRIMARY KEY,
+    tournament_id TEXT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    board INTEGER NOT NULL,
+    white_player_id TEXT NOT NULL REFERENCES tournament_players(id) ON DELETE CASCADE,
+    black_player_id TEXT REFERENCES tournament_players(id) ON DELETE CASCADE,
+    result TEXT CHECK (result IN ('1-0', '0-1', '1/2-1/2')),
+    game_id TEXT,
+    UNIQUE (tournament_id, round, board)
+);
+
+CREATE INDEX tournament_players_user_id_idx ON tournament_players(user_id);
+
+-- +goose down
+DROP TABLE tournament_pairings;
+DROP TABLE tournament_players;
+DROP TABLE tournaments;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- +goose up
+CREATE TABLE matches (
+    id TEXT PRIMARY KEY,
+    player1_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    player2_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    player1_name TEXT NOT NULL,
+    player2_name TEXT NOT NULL,
+    games INTEGER NOT NULL,
+    armageddon BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TEXT NOT NULL,
+    finished_at TEXT
+);
+
+CREATE TABLE match_games (
+    id TEXT PRIMARY KEY,
+    match_id TEXT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    player1_white BOOLEAN NOT NULL,
+    armageddon BOOLEAN NOT NULL DEFAULT FALSE,
+    result TEXT CHECK (result IN ('1-0', '0-1', '1/2-1/2')),
+    game_id TEXT,
+    UNIQUE (match_id, number)
+);
+
+CREATE INDEX matches_player1_user_id_idx ON matches(player1_user_id);
+CREATE INDEX matches_player2_user_id_idx ON matches(player2_user_id);
+
+-- +goose down
+DROP TABLE match_games;
+DROP TABLE matches;
\ No newline at end of file
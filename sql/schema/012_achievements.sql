@@ -0,0 +1,11 @@
+-- +goose up
+CREATE TABLE achievements (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    unlocked_at TEXT NOT NULL,
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- +goose down
+DROP TABLE achievements;
\ No newline at end of file